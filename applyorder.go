@@ -0,0 +1,64 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"flag"
+	"strings"
+)
+
+// DynamicFlagDependencies is implemented by dynamic flags to list the flags they should be applied
+// after during bulk updates (see WithApplyAfter and ApplyOrder).
+type DynamicFlagDependencies interface {
+	ApplyAfter() []string
+}
+
+// WithApplyAfter declares that when a bulk update (configmap directory read, endpoint bulk set or import,
+// source) also changes any of the named flags of the same FlagSet, they are set before this one, so its
+// notifier and validator see their new values: e.g. a pool size flag applied after the max connections one.
+func (d *DynValue[T]) WithApplyAfter(names ...string) *DynValue[T] {
+	d.applyAfter = append(d.applyAfter, names...)
+	return d
+}
+
+// ApplyAfter returns the names of the flags this one is applied after, see WithApplyAfter.
+func (d *DynValue[T]) ApplyAfter() []string {
+	return d.applyAfter
+}
+
+// ApplyOrder returns the (distinct) names of flags being updated together in the order to set them: each flag after
+// the ones it depends on (WithApplyAfter) that are also in names, otherwise in the order of names (of a
+// dependency cycle, the first flag in names is set last). lookup is typically flagSet.Lookup: when it
+// returns a flag whose name is a suffix of the name it was given, the dependencies get the same prefix
+// (e.g. for the prefixed names of the endpoint's and the configmap updater's additional FlagSets).
+func ApplyOrder(names []string, lookup func(name string) *flag.Flag) []string {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	res := make([]string, 0, len(names))
+	done := make(map[string]bool, len(names))
+	var visit func(name string)
+	visit = func(name string) {
+		if done[name] {
+			return
+		}
+		done[name] = true // before the dependencies, for cycles.
+		if f := lookup(name); f != nil {
+			if d, ok := f.Value.(DynamicFlagDependencies); ok {
+				prefix := strings.TrimSuffix(name, f.Name)
+				for _, dep := range d.ApplyAfter() {
+					if wanted[prefix+dep] {
+						visit(prefix + dep)
+					}
+				}
+			}
+		}
+		res = append(res, name)
+	}
+	for _, name := range names {
+		visit(name)
+	}
+	return res
+}
@@ -0,0 +1,32 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"flag"
+	"strings"
+	"testing"
+
+	"fortio.org/assert"
+)
+
+func TestApplyOrder(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	Dyn(set, "a_pool_size", int64(10), "").WithApplyAfter("max_conns", "not_updated")
+	Dyn(set, "max_conns", int64(100), "").WithApplyAfter("z_limit")
+	Dyn(set, "z_limit", int64(1000), "")
+	Dyn(set, "b_cycle", "", "").WithApplyAfter("c_cycle")
+	Dyn(set, "c_cycle", "", "").WithApplyAfter("b_cycle")
+	set.Int("static", 1, "")
+	assert.Equal(t, []string{"z_limit", "max_conns", "a_pool_size", "static"},
+		ApplyOrder([]string{"a_pool_size", "max_conns", "static", "z_limit"}, set.Lookup))
+	assert.Equal(t, []string{"a_pool_size", "unknown"}, ApplyOrder([]string{"a_pool_size", "unknown"}, set.Lookup))
+	assert.Equal(t, []string{"c_cycle", "b_cycle"}, ApplyOrder([]string{"b_cycle", "c_cycle"}, set.Lookup))
+	// prefixed names, e.g. of the endpoint's additional FlagSets.
+	prefixed := func(name string) *flag.Flag {
+		return set.Lookup(strings.TrimPrefix(name, "lib."))
+	}
+	assert.Equal(t, []string{"lib.max_conns", "lib.a_pool_size", "max_conns"},
+		ApplyOrder([]string{"lib.a_pool_size", "lib.max_conns", "max_conns"}, prefixed))
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Package bridge connects dflag to the viper and koanf configuration libraries, without depending
+// on them, for an incremental migration in either direction:
+//
+//   - NewProvider exposes the flags as a koanf Provider, e.g. k.Load(bridge.NewProvider(flagSet, "."), nil)
+//   - Apply sets the flags from a configuration map, e.g. viper's AllSettings() (typically again from
+//     viper.OnConfigChange) or koanf's All()
+package bridge
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"fortio.org/dflag"
+	"fortio.org/dflag/source"
+	"fortio.org/sets"
+)
+
+// ErrReadBytesNotSupported is returned by Provider.ReadBytes, the flags have no serialized form.
+var ErrReadBytesNotSupported = errors.New("dflag provider does not support ReadBytes")
+
+// Provider implements the koanf.Provider interface for the flags of a FlagSet.
+type Provider struct {
+	flagSet *flag.FlagSet
+	delim   string
+}
+
+// NewProvider returns a koanf Provider of the flags of flagSet. With a non empty delim, flag names are
+// split into nested keys on it (e.g. "grpc.timeout" becomes grpc -> timeout with "."), like koanf's own
+// flag providers do.
+func NewProvider(flagSet *flag.FlagSet, delim string) *Provider {
+	return &Provider{flagSet: flagSet, delim: delim}
+}
+
+// ReadBytes isn't supported, use Read.
+func (p *Provider) ReadBytes() ([]byte, error) {
+	return nil, ErrReadBytesNotSupported
+}
+
+// Read returns the current values of all the flags: the typed value for the dynamic flags and the ones
+// implementing flag.Getter (e.g. an int64, []string or time.Duration), the string representation otherwise.
+func (p *Provider) Read() (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+	var err error
+	p.flagSet.VisitAll(func(f *flag.Flag) {
+		if err == nil {
+			err = setNested(res, f.Name, p.delim, flagValue(f))
+		}
+	})
+	return res, err
+}
+
+// flagValue returns the typed value of the flag, string sets as sorted slices.
+func flagValue(f *flag.Flag) interface{} {
+	var value interface{} = f.Value.String()
+	if g, ok := f.Value.(flag.Getter); ok {
+		value = g.Get()
+	} else if dflag.IsFlagDynamic(f) {
+		// The dynamic flags' Get returns their type (e.g. int64), not an interface{}.
+		m := reflect.ValueOf(f.Value).MethodByName("Get")
+		if m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() == 1 {
+			value = m.Call(nil)[0].Interface()
+		}
+	}
+	if s, ok := value.(sets.Set[string]); ok {
+		return sets.Sort(s)
+	}
+	return value
+}
+
+func setNested(m map[string]interface{}, name, delim string, value interface{}) error {
+	if delim == "" {
+		m[name] = value
+		return nil
+	}
+	keys := strings.Split(name, delim)
+	for _, key := range keys[:len(keys)-1] {
+		sub, found := m[key]
+		if !found {
+			sub = make(map[string]interface{})
+			m[key] = sub
+		}
+		subMap, ok := sub.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("dflag: flag %q conflicts with flag %q", name, key)
+		}
+		m = subMap
+	}
+	last := keys[len(keys)-1]
+	if _, found := m[last]; found {
+		return fmt.Errorf("dflag: flag %q conflicts with another flag's prefix", name)
+	}
+	m[last] = value
+	return nil
+}
+
+// Apply sets the flags from settings, whose nested maps are flattened with "." (e.g. viper's
+// AllSettings() or koanf's Raw()), except the ones named after a flag (e.g. a DynJSON one) which are
+// JSON encoded, like slices for JSON flags. Other slices are joined with commas, []byte values are kept as
+// is for binary flags, floats are formatted without exponent (e.g. 1e6 is "1000000" for int flags) and
+// other values formatted with fmt. Values equal to the flag's current one are
+// skipped, so reloading a whole configuration only sets and notifies the changed flags. The values are
+// then set like the other sources do (source.SetFlags): unknown flags are logged, static flags are
+// skipped when dynamicOnly is true (e.g. on reloads) and errors are aggregated. Note that viper lower
+// cases its keys.
+func Apply(flagSet *flag.FlagSet, settings map[string]interface{}, dynamicOnly bool) error {
+	values := make(map[string][]byte)
+	if err := flatten(flagSet, values, "", settings); err != nil {
+		return err
+	}
+	for name, value := range values {
+		if f := flagSet.Lookup(name); f != nil && f.Value.String() == string(value) {
+			delete(values, name)
+		}
+	}
+	return source.SetFlags(flagSet, values, dynamicOnly)
+}
+
+func flatten(flagSet *flag.FlagSet, values map[string][]byte, prefix string, settings map[string]interface{}) error {
+	for key, value := range settings {
+		name := prefix + key
+		f := flagSet.Lookup(name)
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if f == nil {
+				if err := flatten(flagSet, values, name+".", v); err != nil {
+					return err
+				}
+				continue
+			}
+			b, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("dflag: flag %q: %w", name, err)
+			}
+			values[name] = b
+		case []byte:
+			values[name] = v
+		case []string:
+			values[name] = []byte(strings.Join(v, ","))
+		case []interface{}:
+			if isJSON(f) {
+				b, err := json.Marshal(v)
+				if err != nil {
+					return fmt.Errorf("dflag: flag %q: %w", name, err)
+				}
+				values[name] = b
+				continue
+			}
+			s := make([]string, 0, len(v))
+			for _, e := range v {
+				s = append(s, formatScalar(e))
+			}
+			values[name] = []byte(strings.Join(s, ","))
+		default:
+			values[name] = []byte(formatScalar(v))
+		}
+	}
+	return nil
+}
+
+func isJSON(f *flag.Flag) bool {
+	if f == nil {
+		return false
+	}
+	j, ok := f.Value.(dflag.DynamicJSONFlagValue)
+	return ok && j.IsJSON()
+}
+
+// formatScalar formats the floats (e.g. decoded from JSON or YAML numbers) without exponent.
+func formatScalar(v interface{}) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprint(v)
+	}
+}
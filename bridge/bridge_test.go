@@ -0,0 +1,107 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package bridge
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/sets"
+)
+
+// koanfProvider is the koanf.Provider interface.
+type koanfProvider interface {
+	ReadBytes() ([]byte, error)
+	Read() (map[string]interface{}, error)
+}
+
+func testFlagSet() *flag.FlagSet {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	dflag.DynInt64(set, "grpc.port", 8080, "dynamic int")
+	dflag.DynDuration(set, "grpc.timeout", time.Second, "dynamic duration")
+	dflag.DynStringSet(set, "names", []string{"b", "a"}, "dynamic set")
+	dflag.DynBool(set, "debug", false, "dynamic bool")
+	set.String("static", "s", "static string")
+	return set
+}
+
+func TestProvider(t *testing.T) {
+	var p koanfProvider = NewProvider(testFlagSet(), ".")
+	_, err := p.ReadBytes()
+	assert.Error(t, err)
+	m, err := p.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"grpc": map[string]interface{}{
+			"port":    int64(8080),
+			"timeout": time.Second,
+		},
+		"names":  []string{"a", "b"},
+		"debug":  false,
+		"static": "s",
+	}, m)
+	m, err = NewProvider(testFlagSet(), "").Read()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8080), m["grpc.port"])
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	set.String("a", "", "")
+	set.String("a.b", "", "")
+	_, err = NewProvider(set, ".").Read()
+	assert.Error(t, err, "a is both a value and a prefix")
+}
+
+func TestApply(t *testing.T) {
+	set := testFlagSet()
+	var notified []int64
+	set.Lookup("grpc.port").Value.(*dflag.DynValue[int64]).WithSyncNotifier(func(_, newValue int64) {
+		notified = append(notified, newValue)
+	})
+	settings := map[string]interface{}{ // as returned by viper's AllSettings()
+		"grpc": map[string]interface{}{
+			"port":    9090,
+			"timeout": "2s",
+		},
+		"names":   []interface{}{"x", "y"},
+		"debug":   true,
+		"static":  "t",
+		"unknown": 1,
+	}
+	assert.NoError(t, Apply(set, settings, true))
+	assert.Equal(t, "9090", set.Lookup("grpc.port").Value.String())
+	assert.Equal(t, "2s", set.Lookup("grpc.timeout").Value.String())
+	assert.Equal(t, sets.New("x", "y"), set.Lookup("names").Value.(*dflag.DynValue[sets.Set[string]]).Get())
+	assert.Equal(t, "true", set.Lookup("debug").Value.String())
+	assert.Equal(t, "s", set.Lookup("static").Value.String(), "static flags skipped when dynamicOnly")
+	assert.NoError(t, Apply(set, settings, false))
+	assert.Equal(t, "t", set.Lookup("static").Value.String())
+	assert.Equal(t, []int64{9090}, notified, "unchanged values aren't set again")
+	assert.Error(t, Apply(set, map[string]interface{}{"grpc": map[string]interface{}{"port": "x"}}, true))
+}
+
+type limits struct {
+	Max   int      `json:"max"`
+	Hosts []string `json:"hosts"`
+}
+
+func TestApplyJSONAndFloats(t *testing.T) {
+	set := testFlagSet()
+	limitsFlag := dflag.DynJSON(set, "limits", &limits{}, "dynamic json")
+	hostsFlag := dflag.DynJSON(set, "hosts", &[]string{}, "dynamic json array")
+	settings := map[string]interface{}{ // as decoded from a JSON or YAML config file.
+		"grpc": map[string]interface{}{
+			"port": 1e6,
+		},
+		"limits": map[string]interface{}{"max": 3.0, "hosts": []interface{}{"a", "b"}},
+		"hosts":  []interface{}{"c", "d"},
+		"names":  []interface{}{0.5, 2e7},
+	}
+	assert.NoError(t, Apply(set, settings, true))
+	assert.Equal(t, "1000000", set.Lookup("grpc.port").Value.String())
+	assert.Equal(t, &limits{Max: 3, Hosts: []string{"a", "b"}}, limitsFlag.Get())
+	assert.Equal(t, &[]string{"c", "d"}, hostsFlag.Get())
+	assert.Equal(t, sets.New("0.5", "20000000"), set.Lookup("names").Value.(*dflag.DynValue[sets.Set[string]]).Get())
+}
@@ -29,3 +29,18 @@ func ChecksumFlagSet(flagSet *flag.FlagSet, flagFilter func(flag *flag.Flag) boo
 	})
 	return h.Sum(nil)
 }
+
+// GenerationFlagSet returns the sum of the Generation() of the dynamic flags of the FlagSet, a counter that
+// increases whenever one of them is set: a cheap alternative to ChecksumFlagSet to detect changes, for large
+// FlagSets. Static flags aren't tracked (flagSet.NFlag() changes when they are first set).
+func GenerationFlagSet(flagSet *flag.FlagSet) uint64 {
+	var gen uint64
+	visitAllMutex.Lock()
+	defer visitAllMutex.Unlock()
+	flagSet.VisitAll(func(flag *flag.Flag) {
+		if w, ok := flag.Value.(DynamicFlagWatcher); ok {
+			gen += w.Generation()
+		}
+	})
+	return gen
+}
@@ -62,3 +62,17 @@ func TestChecksumFlagSet_Filters(t *testing.T) {
 	t.Logf("post set2 checksum: %x", postSet2Checksum)
 	assert.NotEqual(t, postSet1Checksum, postSet2Checksum, "checksum change when some_duration_1 changes")
 }
+
+func TestGenerationFlagSet(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_int_1", 1, "Use it or lose it")
+	dflag.DynBool(set, "some_bool_1", false, "Use it or lose it")
+	set.String("static_string_1", "foobar", "meh")
+	assert.Equal(t, uint64(0), dflag.GenerationFlagSet(set))
+	assert.NoError(t, set.Set("some_int_1", "2"))
+	assert.NoError(t, set.Set("some_bool_1", "true"))
+	assert.NoError(t, set.Set("static_string_1", "goodbar"))
+	assert.Equal(t, uint64(2), dflag.GenerationFlagSet(set), "only dynamic flags are tracked")
+	assert.Error(t, set.Set("some_int_1", "x"))
+	assert.Equal(t, uint64(2), dflag.GenerationFlagSet(set), "failed sets don't count")
+}
@@ -0,0 +1,265 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Package client is a Go client for the flags endpoints (see the endpoint package's Mount): typed
+// List/Get/Set/Watch calls with retries and authentication, and a Fleet of them to flip flags across
+// many instances and verify they converged.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"fortio.org/dflag/endpoint"
+)
+
+// Flag is a flag as listed by the endpoint.
+type Flag struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	CurrentValue string `json:"current_value"`
+	DefaultValue string `json:"default_value"`
+	IsChanged    bool   `json:"is_changed"`
+	IsDynamic    bool   `json:"is_dynamic"`
+	IsJSON       bool   `json:"is_json"`
+	IsMutable    bool   `json:"is_mutable"`
+	Section      string `json:"section,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// StatusError is returned for unexpected HTTP statuses (e.g. 404 for unknown flags).
+type StatusError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Status, e.Body)
+}
+
+// Client calls one instance's flags endpoint.
+type Client struct {
+	base       string
+	setURL     string
+	httpClient *http.Client
+	header     http.Header
+	user       string
+	password   string
+	retries    int
+	backoff    time.Duration
+}
+
+// New returns a client of the endpoint mounted at baseURL (e.g. http://host:8080/debug/flags), whose set
+// handler is at baseURL/set (see WithSetURL). It retries twice by default, see WithRetries.
+func New(baseURL string) *Client {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	return &Client{
+		base:       baseURL,
+		setURL:     baseURL + "/set",
+		httpClient: http.DefaultClient,
+		header:     http.Header{},
+		retries:    2,
+		backoff:    100 * time.Millisecond,
+	}
+}
+
+// URL returns the base URL of the endpoint.
+func (c *Client) URL() string {
+	return c.base
+}
+
+// WithSetURL changes the URL of the set handler, when not mounted under the base URL.
+func (c *Client) WithSetURL(setURL string) *Client {
+	c.setURL = setURL
+	return c
+}
+
+// WithHTTPClient replaces the http.DefaultClient used, e.g. for TLS client certificates or timeouts.
+func (c *Client) WithHTTPClient(httpClient *http.Client) *Client {
+	c.httpClient = httpClient
+	return c
+}
+
+// WithBasicAuth sends the user and password with every request.
+func (c *Client) WithBasicAuth(user, password string) *Client {
+	c.user, c.password = user, password
+	return c
+}
+
+// WithHeader adds a header sent with every request, e.g. "Authorization" with a bearer token.
+func (c *Client) WithHeader(name, value string) *Client {
+	c.header.Add(name, value)
+	return c
+}
+
+// WithRetries sets how many times requests failing with network errors, 429 or 5xx statuses are retried,
+// waiting backoff and doubling it each time (or the Retry-After of 429s).
+func (c *Client) WithRetries(retries int, backoff time.Duration) *Client {
+	c.retries, c.backoff = retries, backoff
+	return c
+}
+
+// do sends the request, with the retries, and returns the response and its body.
+func (c *Client) do(ctx context.Context, method, u string, body []byte, contentType string) (*http.Response, []byte, error) {
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		resp, data, err := c.doOnce(ctx, method, u, body, contentType)
+		retryable := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if !retryable || attempt >= c.retries || ctx.Err() != nil {
+			return resp, data, err
+		}
+		wait := backoff
+		if err == nil {
+			if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+				wait = time.Duration(s) * time.Second
+			}
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) doOnce(ctx context.Context, method, u string, body []byte, contentType string) (*http.Response, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	return resp, data, err
+}
+
+func statusError(resp *http.Response, data []byte) error {
+	return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: strings.TrimSpace(string(data))}
+}
+
+// getJSON gets u and decodes the JSON reply into v, a StatusError for non 200 statuses.
+func (c *Client) getJSON(ctx context.Context, u string, v interface{}) error {
+	resp, data, err := c.do(ctx, http.MethodGet, u, nil, "")
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return statusError(resp, data)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// List returns the flags whose name or description contains filter (all of them when empty).
+func (c *Client) List(ctx context.Context, filter string) ([]Flag, error) {
+	var res struct {
+		Flags []Flag `json:"flags"`
+	}
+	err := c.getJSON(ctx, c.base+"?format=json&filter="+url.QueryEscape(filter), &res)
+	return res.Flags, err
+}
+
+// Diff returns the flags whose value differs from their default.
+func (c *Client) Diff(ctx context.Context) ([]Flag, error) {
+	var res []Flag
+	err := c.getJSON(ctx, c.base+"/diff?format=json", &res)
+	return res, err
+}
+
+// Get returns the named flag.
+func (c *Client) Get(ctx context.Context, name string) (Flag, error) {
+	var res Flag
+	err := c.getJSON(ctx, c.base+"/"+url.PathEscape(name)+"?format=json", &res)
+	return res, err
+}
+
+// Set sets the flags, all the values being validated before any is applied (see endpoint.SetFlag).
+// The error is nil when the endpoint replied, check the response's Applied and Results' errors.
+func (c *Client) Set(ctx context.Context, values map[string]string) (*endpoint.BulkSetResponse, error) {
+	return c.set(ctx, values, false)
+}
+
+// DryRun validates the values without setting them, see Set.
+func (c *Client) DryRun(ctx context.Context, values map[string]string) (*endpoint.BulkSetResponse, error) {
+	return c.set(ctx, values, true)
+}
+
+func (c *Client) set(ctx context.Context, values map[string]string, dryRun bool) (*endpoint.BulkSetResponse, error) {
+	body, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	u := c.setURL
+	if dryRun {
+		u += "?dryrun=1"
+	}
+	resp, data, err := c.do(ctx, http.MethodPost, u, body, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	res := &endpoint.BulkSetResponse{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, statusError(resp, data)
+	}
+	return res, nil
+}
+
+// Watch long polls the dynamic flag, each request waiting up to wait (at most endpoint.MaxGetWait), and calls
+// changed with the flag each time it is set, until ctx is done (then returning nil) or an error occurs
+// (e.g. a 400 StatusError for static flags).
+func (c *Client) Watch(ctx context.Context, name string, wait time.Duration, changed func(f Flag)) error {
+	generation := ""
+	for {
+		u := c.base + "/" + url.PathEscape(name) + "?format=json&wait=" + wait.String()
+		if generation != "" {
+			u += "&last_generation=" + generation
+		}
+		resp, data, err := c.do(ctx, http.MethodGet, u, nil, "")
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			return err
+		case resp.StatusCode == http.StatusNotModified:
+			continue
+		case resp.StatusCode != http.StatusOK:
+			return statusError(resp, data)
+		}
+		g := resp.Header.Get(endpoint.GenerationHeader)
+		if _, err := strconv.ParseUint(g, 10, 64); err != nil {
+			return fmt.Errorf("invalid %s header %q for flag %q", endpoint.GenerationHeader, g, name)
+		}
+		var f Flag
+		if err := json.Unmarshal(data, &f); err != nil {
+			return err
+		}
+		generation = g
+		changed(f)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/endpoint"
+)
+
+func newServer(t *testing.T) (*flag.FlagSet, string) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing").WithRange(1, 10)
+	dflag.DynString(set, "some_dynstr", "a", "dynamic string for testing")
+	set.Int("some_int", 1, "static int for testing")
+	e := endpoint.NewFlagsEndpoint(set, "/debug/flags/set").
+		WithAuth(func(req *http.Request) error {
+			if _, pass, _ := req.BasicAuth(); pass != "secret" {
+				return errors.New("bad password")
+			}
+			return nil
+		})
+	srv := httptest.NewServer(e.Handler("/debug/flags"))
+	t.Cleanup(srv.Close)
+	return set, srv.URL + "/debug/flags"
+}
+
+func TestClient(t *testing.T) {
+	_, u := newServer(t)
+	ctx := context.Background()
+	_, err := New(u).List(ctx, "")
+	var se *StatusError
+	assert.True(t, errors.As(err, &se), "status error")
+	assert.Equal(t, http.StatusForbidden, se.StatusCode)
+	c := New(u).WithBasicAuth("ops", "secret")
+	flags, err := c.List(ctx, "dyn")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(flags))
+	assert.Equal(t, "some_dynint", flags[0].Name)
+	res, err := c.DryRun(ctx, map[string]string{"some_dynint": "5"})
+	assert.NoError(t, err)
+	assert.True(t, res.DryRun)
+	res, err = c.Set(ctx, map[string]string{"some_dynint": "11"})
+	assert.NoError(t, err)
+	assert.False(t, res.Applied)
+	assert.Contains(t, res.Results[0].Error, "range")
+	res, err = c.Set(ctx, map[string]string{"some_dynint": "5", "some_dynstr": "b"})
+	assert.NoError(t, err)
+	assert.True(t, res.Applied)
+	f, err := c.Get(ctx, "some_dynint")
+	assert.NoError(t, err)
+	assert.Equal(t, "5", f.CurrentValue)
+	assert.True(t, f.IsChanged)
+	diff, err := c.Diff(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(diff))
+	_, err = c.Get(ctx, "nope")
+	assert.True(t, errors.As(err, &se), "status error")
+	assert.Equal(t, http.StatusNotFound, se.StatusCode)
+	err = c.Watch(ctx, "some_int", time.Second, func(Flag) {})
+	assert.True(t, errors.As(err, &se), "static flags can't be watched")
+	assert.Equal(t, http.StatusBadRequest, se.StatusCode)
+}
+
+func TestRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"flags": [{"name": "a"}]}`))
+	}))
+	defer srv.Close()
+	flags, err := New(srv.URL).WithRetries(2, time.Millisecond).List(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, "a", flags[0].Name)
+	assert.Equal(t, int32(3), calls.Load())
+	_, err = New(srv.URL).WithRetries(0, time.Millisecond).List(context.Background(), "")
+	assert.NoError(t, err, "4th call succeeds")
+	calls.Store(0)
+	_, err = New(srv.URL).WithRetries(0, time.Millisecond).List(context.Background(), "")
+	assert.Error(t, err, "no retry")
+}
+
+func TestFleet(t *testing.T) {
+	set1, u1 := newServer(t)
+	set2, u2 := newServer(t)
+	fleet := NewFleet(u1, u2)
+	for _, c := range fleet {
+		c.WithBasicAuth("ops", "secret")
+	}
+	ctx := context.Background()
+	results := fleet.Set(ctx, map[string]string{"some_dynint": "7"})
+	assert.Equal(t, 2, len(results))
+	assert.True(t, results[0].OK() && results[1].OK(), "set on all")
+	assert.Equal(t, u2, results[1].Client.URL())
+	assert.Equal(t, "7", set2.Lookup("some_dynint").Value.String())
+	assert.NoError(t, fleet.WaitConverged(ctx, map[string]string{"some_dynint": "7"}, 10*time.Millisecond))
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = set1.Set("some_dynstr", "c")
+		_ = set2.Set("some_dynstr", "c")
+	}()
+	assert.NoError(t, fleet.WaitConverged(ctx, map[string]string{"some_dynstr": "c"}, 10*time.Millisecond))
+	short, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	err := fleet.WaitConverged(short, map[string]string{"some_dynstr": "d"}, 10*time.Millisecond)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), u2+` some_dynstr="c"`)
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"fortio.org/dflag/endpoint"
+)
+
+// Fleet is a set of instances' clients, to change flags on all of them at once.
+type Fleet []*Client
+
+// NewFleet returns the Fleet of New clients of the base URLs.
+func NewFleet(baseURLs ...string) Fleet {
+	res := make(Fleet, len(baseURLs))
+	for i, u := range baseURLs {
+		res[i] = New(u)
+	}
+	return res
+}
+
+// SetResult is the outcome of a Fleet's Set on one instance.
+type SetResult struct {
+	Client   *Client
+	Response *endpoint.BulkSetResponse
+	Err      error
+}
+
+// OK returns whether the values were applied on the instance.
+func (r SetResult) OK() bool {
+	return r.Err == nil && r.Response != nil && r.Response.Applied
+}
+
+// Set sets the values on all the instances concurrently, returning the results in the Fleet's order.
+func (f Fleet) Set(ctx context.Context, values map[string]string) []SetResult {
+	res := make([]SetResult, len(f))
+	var wg sync.WaitGroup
+	for i, c := range f {
+		wg.Add(1)
+		go func(i int, c *Client) {
+			defer wg.Done()
+			resp, err := c.Set(ctx, values)
+			res[i] = SetResult{Client: c, Response: resp, Err: err}
+		}(i, c)
+	}
+	wg.Wait()
+	return res
+}
+
+// diverging returns the instances whose flags don't have the values yet, with their current value.
+func (f Fleet) diverging(ctx context.Context, values map[string]string) []string {
+	var mu sync.Mutex
+	var res []string
+	var wg sync.WaitGroup
+	for _, c := range f {
+		for name, value := range values {
+			wg.Add(1)
+			go func(c *Client, name, value string) {
+				defer wg.Done()
+				fl, err := c.Get(ctx, name)
+				if err == nil && fl.CurrentValue == value {
+					return
+				}
+				current := fl.CurrentValue
+				if err != nil {
+					current = err.Error()
+				}
+				mu.Lock()
+				res = append(res, fmt.Sprintf("%s %s=%q", c.URL(), name, current))
+				mu.Unlock()
+			}(c, name, value)
+		}
+	}
+	wg.Wait()
+	return res
+}
+
+// WaitConverged polls all the instances every poll interval until their flags all have the values (compared
+// as the endpoint's canonical string representation, e.g. "1s" for durations), for instance after changing
+// them through a ConfigMap or another source. The error lists the diverging instances when ctx is done first.
+func (f Fleet) WaitConverged(ctx context.Context, values map[string]string, poll time.Duration) error {
+	var diverging []string
+	for {
+		current := f.diverging(ctx, values)
+		if ctx.Err() == nil { // otherwise keep the previous, complete, poll's.
+			diverging = current
+		}
+		if len(diverging) == 0 && ctx.Err() == nil {
+			return nil
+		}
+		select {
+		case <-time.After(poll):
+		case <-ctx.Done():
+			sort.Strings(diverging)
+			return fmt.Errorf("%w, not converged: %s", ctx.Err(), strings.Join(diverging, ", "))
+		}
+	}
+}
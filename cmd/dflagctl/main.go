@@ -0,0 +1,229 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// dflagctl is a command line client for the dflag endpoints (see the endpoint package's Mount),
+// to list, get, set, watch and diff the flags of one or many instances, e.g.
+//
+//	dflagctl -url http://host1:8080/debug/flags -url http://host2:8080/debug/flags set log_level debug
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"fortio.org/dflag/client"
+)
+
+const usage = `Usage: dflagctl [flags] command [args]
+
+Commands:
+  list [filter]       list the flags (* marks changed ones)
+  diff                list the flags whose value differs from the default
+  get name            print the current value of a flag
+  set name value...   set the flags (name value pairs, all validated before any is applied)
+  watch name          print the flag's value each time it changes
+
+Flags:
+`
+
+// urlsFlag is a repeatable (or comma separated) list of URLs.
+type urlsFlag []string
+
+func (u *urlsFlag) String() string { return strings.Join(*u, ",") }
+
+func (u *urlsFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*u = append(*u, strings.TrimSuffix(v, "/"))
+		}
+	}
+	return nil
+}
+
+// headersFlag is a repeatable "Name: value" header flag.
+type headersFlag http.Header
+
+func (h headersFlag) String() string { return "" }
+
+func (h headersFlag) Set(value string) error {
+	name, v, ok := strings.Cut(value, ":")
+	if !ok {
+		return fmt.Errorf("expecting Name: value, got %q", value)
+	}
+	http.Header(h).Add(strings.TrimSpace(name), strings.TrimSpace(v))
+	return nil
+}
+
+// action runs a command on a target, printing its output lines with out.
+type action func(ctx context.Context, c *client.Client, out func(format string, args ...interface{})) error
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line args against all the targets, returning the exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("dflagctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	var urls urlsFlag
+	headers := headersFlag{}
+	fs.Var(&urls, "url", "`URL` where the flags endpoint is mounted, e.g. http://host:8080/debug/flags (repeatable or comma separated)")
+	fs.Var(headers, "H", "extra `header` (Name: value) sent with every request, e.g. for a bearer token (repeatable)")
+	setPath := fs.String("set-path", "/set", "path of the set handler, relative to the URL when it doesn't start with http")
+	user := fs.String("user", "", "basic auth `user` (the password is read from the DFLAGCTL_PASSWORD environment variable)")
+	timeout := fs.Duration("timeout", 10*time.Second, "timeout of each request (watches long poll for that long)")
+	dryRun := fs.Bool("dry-run", false, "only validate the values for set")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if len(urls) == 0 || fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	cmd, cmdArgs := fs.Arg(0), fs.Args()[1:]
+	targets := client.NewFleet(urls...)
+	for _, c := range targets {
+		setURL := *setPath
+		if !strings.HasPrefix(setURL, "http") {
+			setURL = c.URL() + setURL
+		}
+		c.WithSetURL(setURL).WithHTTPClient(&http.Client{Timeout: *timeout + 5*time.Second})
+		if *user != "" {
+			c.WithBasicAuth(*user, os.Getenv("DFLAGCTL_PASSWORD"))
+		}
+		for name, values := range headers {
+			for _, v := range values {
+				c.WithHeader(name, v)
+			}
+		}
+	}
+	var do action
+	switch {
+	case cmd == "list" && len(cmdArgs) <= 1:
+		do = func(ctx context.Context, c *client.Client, out func(string, ...interface{})) error {
+			flags, err := c.List(ctx, strings.Join(cmdArgs, ""))
+			for _, f := range flags {
+				changed := " "
+				if f.IsChanged {
+					changed = "*"
+				}
+				out("%s %s=%s", changed, f.Name, f.CurrentValue)
+			}
+			return err
+		}
+	case cmd == "diff" && len(cmdArgs) == 0:
+		do = func(ctx context.Context, c *client.Client, out func(string, ...interface{})) error {
+			flags, err := c.Diff(ctx)
+			for _, f := range flags {
+				out("%s: %s -> %s", f.Name, f.DefaultValue, f.CurrentValue)
+			}
+			return err
+		}
+	case cmd == "get" && len(cmdArgs) == 1:
+		do = func(ctx context.Context, c *client.Client, out func(string, ...interface{})) error {
+			f, err := c.Get(ctx, cmdArgs[0])
+			if err == nil {
+				out("%s", f.CurrentValue)
+			}
+			return err
+		}
+	case cmd == "set" && len(cmdArgs) > 0 && len(cmdArgs)%2 == 0:
+		values := make(map[string]string)
+		for i := 0; i < len(cmdArgs); i += 2 {
+			values[cmdArgs[i]] = cmdArgs[i+1]
+		}
+		do = func(ctx context.Context, c *client.Client, out func(string, ...interface{})) error {
+			set := c.Set
+			if *dryRun {
+				set = c.DryRun
+			}
+			res, err := set(ctx, values)
+			if err != nil {
+				return err
+			}
+			for _, r := range res.Results {
+				switch {
+				case r.Error != "":
+					out("%s: %s", r.Name, r.Error)
+				case r.Applied:
+					out("%s=%s", r.Name, r.Value)
+				default:
+					out("%s=%s valid", r.Name, r.Value)
+				}
+			}
+			if !res.Applied && !res.DryRun {
+				return errors.New("no value applied")
+			}
+			return nil
+		}
+	case cmd == "watch" && len(cmdArgs) == 1:
+		do = func(ctx context.Context, c *client.Client, out func(string, ...interface{})) error {
+			return c.Watch(ctx, cmdArgs[0], *timeout, func(f client.Flag) {
+				out("%s %s=%s", time.Now().Format(time.RFC3339), f.Name, f.CurrentValue)
+			})
+		}
+	default:
+		fs.Usage()
+		return 2
+	}
+	return runAll(ctx, targets, cmd == "watch", do, stdout, stderr)
+}
+
+// runAll runs the action on all the targets concurrently. With several targets the output lines are
+// prefixed by the target's URL and, unless streaming (watch), grouped per target.
+func runAll(ctx context.Context, targets client.Fleet, stream bool, action action, stdout, stderr io.Writer) int {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	failed := false
+	for _, c := range targets {
+		wg.Add(1)
+		go func(c *client.Client) {
+			defer wg.Done()
+			prefix := ""
+			if len(targets) > 1 {
+				prefix = c.URL() + ": "
+			}
+			var lines []string
+			out := func(format string, args ...interface{}) {
+				line := prefix + fmt.Sprintf(format, args...)
+				if !stream {
+					lines = append(lines, line)
+					return
+				}
+				mu.Lock()
+				fmt.Fprintln(stdout, line)
+				mu.Unlock()
+			}
+			err := action(ctx, c, out)
+			mu.Lock()
+			defer mu.Unlock()
+			for _, l := range lines {
+				fmt.Fprintln(stdout, l)
+			}
+			if err != nil {
+				failed = true
+				fmt.Fprintf(stderr, "%serror: %v\n", prefix, err)
+			}
+		}(c)
+	}
+	wg.Wait()
+	if failed {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/endpoint"
+)
+
+func newServer(t *testing.T) (*flag.FlagSet, string) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing").WithRange(1, 10)
+	dflag.DynString(set, "some_dynstr", "a", "dynamic string for testing")
+	srv := httptest.NewServer(endpoint.NewFlagsEndpoint(set, "/debug/flags/set").Handler("/debug/flags"))
+	t.Cleanup(srv.Close)
+	return set, srv.URL + "/debug/flags"
+}
+
+func dflagctl(ctx context.Context, args ...string) (int, string, string) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	code := run(ctx, args, stdout, stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestCommands(t *testing.T) {
+	_, u1 := newServer(t)
+	set2, u2 := newServer(t)
+	ctx := context.Background()
+	code, out, _ := dflagctl(ctx, "-url", u1, "list", "dyn")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "  some_dynint=1\n  some_dynstr=a\n", out)
+	code, out, errs := dflagctl(ctx, "-url", u1+","+u2, "set", "some_dynint", "5", "some_dynstr", "b")
+	assert.Equal(t, 0, code, errs)
+	assert.Contains(t, out, u2+": some_dynint=5\n")
+	assert.Equal(t, "5", set2.Lookup("some_dynint").Value.String())
+	code, _, errs = dflagctl(ctx, "-url", u1, "set", "some_dynint", "11")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errs, "no value applied")
+	code, out, _ = dflagctl(ctx, "-url", u1, "-dry-run", "set", "some_dynint", "7")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "some_dynint=7 valid\n", out)
+	code, out, _ = dflagctl(ctx, "-url", u1, "get", "some_dynint")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "5\n", out)
+	code, out, _ = dflagctl(ctx, "-url", u1, "diff")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "some_dynint: 1 -> 5\nsome_dynstr: a -> b\n", out)
+	code, _, errs = dflagctl(ctx, "-url", u1, "get", "nope")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errs, "404")
+	code, _, _ = dflagctl(ctx, "-url", u1, "set", "some_dynint")
+	assert.Equal(t, 2, code, "usage error")
+}
+
+func TestWatch(t *testing.T) {
+	set, u := newServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	stdout := &syncBuffer{}
+	done := make(chan int)
+	go func() {
+		done <- run(ctx, []string{"-url", u, "-timeout", "1s", "watch", "some_dynint"}, stdout, stdout)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	assert.NoError(t, set.Set("some_dynint", "3"))
+	for i := 0; i < 100 && !strings.Contains(stdout.String(), "some_dynint=3"); i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	cancel()
+	assert.Equal(t, 0, <-done)
+	assert.Contains(t, stdout.String(), "some_dynint=3")
+}
+
+// syncBuffer is a bytes.Buffer safe for the concurrent watch output and reads.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
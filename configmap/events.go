@@ -0,0 +1,81 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultEventsBufferSize is how many of the most recent events are kept by default.
+const DefaultEventsBufferSize = 100
+
+// Event is the record of an attempt by the updater to set a flag from a file.
+type Event struct {
+	Time     time.Time `json:"time"`
+	File     string    `json:"file"`
+	Flag     string    `json:"flag"`
+	OldValue string    `json:"old_value"`
+	NewValue string    `json:"new_value"`
+	Error    string    `json:"error,omitempty"` // empty on success.
+}
+
+func newEvent(fileName, flagName, oldValue, newValue string, err error) Event {
+	e := Event{Time: time.Now(), File: fileName, Flag: flagName, OldValue: oldValue, NewValue: newValue}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// eventsRing is a bounded buffer keeping the most recent events.
+type eventsRing struct {
+	mu     sync.Mutex
+	size   int
+	next   int // index of the next write once the buffer is full.
+	events []Event
+}
+
+func (r *eventsRing) add(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.size <= 0 {
+		return
+	}
+	if len(r.events) < r.size {
+		r.events = append(r.events, e)
+		return
+	}
+	r.events[r.next] = e
+	r.next = (r.next + 1) % r.size
+}
+
+// all returns a copy of the events, oldest first.
+func (r *eventsRing) all() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res := make([]Event, 0, len(r.events))
+	res = append(res, r.events[r.next:]...)
+	return append(res, r.events[:r.next]...)
+}
+
+// Events returns the most recent updater events (oldest first), see WithEventsBuffer().
+func (u *Updater) Events() []Event {
+	return u.events.all()
+}
+
+// EventsHandler returns an http.Handler serving the recent events as JSON.
+func (u *Updater) EventsHandler() http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, _ *http.Request) {
+		out, err := json.MarshalIndent(u.Events(), "", "  ")
+		if err != nil {
+			resp.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		resp.Header().Set("Content-Type", "application/json")
+		_, _ = resp.Write(out)
+	})
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap_test
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/configmap"
+)
+
+func TestEvents(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("events_test", flag.ContinueOnError)
+	dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	dflag.Dyn(fs, "some_binary", []byte{1, 2}, "dynamic binary for testing")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("42"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_binary"), []byte{1, 2, 3}, 0o644))
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.NoError(t, u.Initialize())
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("abc"), 0o644))
+	assert.Error(t, u.Reload())
+	events := u.Events()
+	assert.Equal(t, 4, len(events))
+	// ReadDir is sorted by name.
+	assert.Equal(t, "some_binary", events[0].Flag)
+	assert.Equal(t, "(binary, 2 bytes)", events[0].OldValue)
+	assert.Equal(t, "(binary, 3 bytes)", events[0].NewValue)
+	assert.Equal(t, "", events[0].Error)
+	assert.Equal(t, "1", events[1].OldValue)
+	assert.Equal(t, "42", events[1].NewValue)
+	assert.Equal(t, "abc", events[3].NewValue)
+	assert.Contains(t, events[3].Error, "invalid syntax")
+	resp := httptest.NewRecorder()
+	u.EventsHandler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/events", nil))
+	var fromJSON []configmap.Event
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &fromJSON))
+	assert.Equal(t, 4, len(fromJSON))
+	assert.Equal(t, "some_dynint", fromJSON[3].Flag)
+}
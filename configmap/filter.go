@@ -0,0 +1,53 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// WithInclude restricts the files considered to the ones whose name matches at least one of the
+// glob patterns (`filepath.Match` syntax, e.g. "*.conf" or "myapp_*"). Can be called multiple times.
+func (u *Updater) WithInclude(patterns ...string) *Updater {
+	u.includes = append(u.includes, patterns...)
+	return u
+}
+
+// WithExclude makes the updater skip (without warnings) the files whose name matches one of the
+// glob patterns, e.g. "README*" or "*.sha256" for non flag files living in the watched directory.
+// Exclusions take precedence over WithInclude. Can be called multiple times.
+func (u *Updater) WithExclude(patterns ...string) *Updater {
+	u.excludes = append(u.excludes, patterns...)
+	return u
+}
+
+// checkPatterns returns an error for the first malformed include or exclude pattern.
+func (u *Updater) checkPatterns() error {
+	for _, patterns := range [][]string{u.includes, u.excludes} {
+		for _, p := range patterns {
+			if _, err := filepath.Match(p, ""); err != nil {
+				return fmt.Errorf("dflag: invalid pattern %q: %w", p, err)
+			}
+		}
+	}
+	return nil
+}
+
+func matchAny(patterns []string, fileName string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, fileName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ignored returns true if the file isn't selected by the include/exclude patterns.
+func (u *Updater) ignored(fileName string) bool {
+	if len(u.includes) > 0 && !matchAny(u.includes, fileName) {
+		return true
+	}
+	return matchAny(u.excludes, fileName)
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/configmap"
+)
+
+func TestIncludeExclude(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("filter_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	dynStr := dflag.DynString(fs, "some_dynstr", "a", "dynamic string for testing")
+	for name, value := range map[string]string{
+		"some_dynint": "42", "some_dynstr": "b", "some_dynint.sha256": "abcd", "README.md": "# doc", "notes": "x",
+	} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(value), 0o644))
+	}
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	u.WithInclude("some_*", "notes").WithExclude("*.sha256").WithExclude("notes", "some_dynstr")
+	assert.NoError(t, u.Initialize())
+	assert.NoError(t, u.Start())
+	assert.Equal(t, 0, u.Warnings(), "non flag files should be ignored")
+	assert.Equal(t, int64(42), dynInt.Get())
+	assert.Equal(t, "a", dynStr.Get(), "excluded")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# updated"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("43"), 0o644))
+	eventually(t, 1*time.Second, assert.ObjectsAreEqualValues, int64(43),
+		func() interface{} { return dynInt.Get() }, "some_dynint should change")
+	assert.Equal(t, 0, u.Warnings(), "README.md changes should be ignored")
+	assert.Equal(t, 0, u.Errors())
+}
+
+func TestBadPattern(t *testing.T) {
+	fs := flag.NewFlagSet("filter_test", flag.ContinueOnError)
+	u, err := configmap.New(fs, t.TempDir())
+	assert.NoError(t, err)
+	defer u.Close()
+	err = u.WithExclude("[").Initialize()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid pattern")
+}
+
+func TestApplyAfter(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("filter_test", flag.ContinueOnError)
+	maxConns := dflag.DynInt64(fs, "max_conns", 10, "")
+	seen := int64(0)
+	dflag.DynInt64(fs, "a_pool_size", 5, "").WithApplyAfter("max_conns").WithSyncNotifier(func(_, _ int64) {
+		seen = maxConns.Get()
+	})
+	for name, value := range map[string]string{"a_pool_size": "50", "max_conns": "100"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(value), 0o644))
+	}
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.NoError(t, u.Initialize())
+	assert.Equal(t, int64(100), seen, "max_conns set before a_pool_size")
+}
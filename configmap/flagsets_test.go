@@ -0,0 +1,46 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/configmap"
+)
+
+func TestMultipleFlagSets(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("main", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	libFS := flag.NewFlagSet("lib", flag.ContinueOnError)
+	libTimeout := dflag.DynDuration(libFS, "timeout", time.Second, "library's dynamic duration for testing")
+	libStatic := libFS.Int("workers", 1, "library's static int for testing")
+	otherFS := flag.NewFlagSet("other", flag.ContinueOnError)
+	otherTimeout := dflag.DynDuration(otherFS, "timeout", time.Second, "other library's dynamic duration for testing")
+	for name, value := range map[string]string{
+		"some_dynint": "42", "lib.timeout": "5s", "lib.workers": "3", "lib.unknown": "x", "other_timeout": "7s",
+	} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(value), 0o644))
+	}
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	u.WithFlagSet(libFS, "lib.").WithFlagSet(otherFS, "other_")
+	assert.NoError(t, u.Initialize())
+	assert.NoError(t, u.Start())
+	assert.Equal(t, int64(42), dynInt.Get())
+	assert.Equal(t, 5*time.Second, libTimeout.Get())
+	assert.Equal(t, 3, *libStatic)
+	assert.Equal(t, 7*time.Second, otherTimeout.Get())
+	assert.Equal(t, 1, u.Warnings(), "lib.unknown should be the only unknown flag")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "lib.timeout"), []byte("10s"), 0o644))
+	eventually(t, 1*time.Second, assert.ObjectsAreEqualValues, 10*time.Second,
+		func() interface{} { return libTimeout.Get() }, "lib.timeout should change")
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrInitTimeout is returned by Initialize when the initial read doesn't complete within the WithInitTimeout duration.
+var ErrInitTimeout = errors.New("dflag: initial read timed out")
+
+// InitPolicy is what Initialize does when some files have invalid values.
+type InitPolicy int
+
+const (
+	// InitFailOnErrors applies all the valid files then fails with the errors for the others (default).
+	InitFailOnErrors InitPolicy = iota
+	// InitFailFast stops at the first invalid file, the following files aren't applied.
+	InitFailFast
+	// InitApplyValid applies all the valid files and only logs the errors for the others, which are then
+	// available through InitError(), so a single malformed value doesn't prevent startup. Initialize still
+	// fails if a directory can't be read at all or on timeout.
+	InitApplyValid
+)
+
+// WithInitPolicy sets how invalid values are handled by Initialize, see InitPolicy.
+func (u *Updater) WithInitPolicy(policy InitPolicy) *Updater {
+	u.initPolicy = policy
+	return u
+}
+
+// WithInitTimeout makes Initialize fail with ErrInitTimeout if the initial read of the files takes longer than
+// timeout (e.g. hung network file system). The read is then abandoned: no flag is set after the timeout, but a
+// blocked file read can't be interrupted and the watcher (once started) waits for it to return before applying
+// updates. 0 (the default) means no timeout.
+func (u *Updater) WithInitTimeout(timeout time.Duration) *Updater {
+	u.initTimeout = timeout
+	return u
+}
+
+// InitError returns the errors that were ignored by Initialize because of the InitApplyValid policy, if any.
+func (u *Updater) InitError() error {
+	return u.initErr
+}
+
+// stopReading is true if the read should stop because of errors and the InitFailFast policy.
+func (u *Updater) stopReading(dynamicOnly bool, errorStrings []string) bool {
+	return !dynamicOnly && u.initPolicy == InitFailFast && len(errorStrings) > 0
+}
+
+// initialRead reads all the layers for Initialize applying the timeout and policy.
+func (u *Updater) initialRead() error {
+	err := u.readAllWithTimeout()
+	if err == nil || u.initPolicy != InitApplyValid || errors.Is(err, ErrInitTimeout) {
+		return err
+	}
+	for _, dirPath := range u.layers() {
+		if _, statErr := os.Stat(dirPath); statErr != nil {
+			return err
+		}
+	}
+	u.logger.Error("dflag: ignoring initial read errors (apply valid policy)", "error", err)
+	u.initErr = err
+	return nil
+}
+
+func (u *Updater) readAllWithTimeout() error {
+	read := func(ctx context.Context) error {
+		u.applyMu.Lock()
+		defer u.applyMu.Unlock()
+		return u.readAll(ctx, false /* dynamicOnly */)
+	}
+	if u.initTimeout <= 0 {
+		return read(context.Background())
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel() // on timeout, makes the abandoned read stop before setting any more flag.
+	done := make(chan error, 1)
+	go func() {
+		done <- read(ctx)
+	}()
+	timer := time.NewTimer(u.initTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("%w after %v reading %v", ErrInitTimeout, u.initTimeout, u.dirPath)
+	}
+}
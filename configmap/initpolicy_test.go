@@ -0,0 +1,88 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap_test
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/configmap"
+)
+
+func TestInitPolicy(t *testing.T) {
+	dir := t.TempDir()
+	// files are read in name order: a_dynint is invalid, b_dynstr valid.
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a_dynint"), []byte("not a number"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b_dynstr"), []byte("b"), 0o644))
+	for _, tst := range []struct {
+		policy   configmap.InitPolicy
+		fails    bool
+		expected string
+	}{
+		{configmap.InitFailOnErrors, true, "b"},
+		{configmap.InitFailFast, true, "a"},
+		{configmap.InitApplyValid, false, "b"},
+	} {
+		fs := flag.NewFlagSet("initpolicy_test", flag.ContinueOnError)
+		dynInt := dflag.DynInt64(fs, "a_dynint", 1, "dynamic int for testing")
+		dynStr := dflag.DynString(fs, "b_dynstr", "a", "dynamic string for testing")
+		u, err := configmap.New(fs, dir)
+		assert.NoError(t, err)
+		err = u.WithInitPolicy(tst.policy).Initialize()
+		msg := fmt.Sprintf("policy %d, error %v", tst.policy, err)
+		assert.Equal(t, tst.fails, err != nil, msg)
+		assert.Equal(t, tst.expected, dynStr.Get(), msg)
+		assert.Equal(t, int64(1), dynInt.Get())
+		if tst.policy == configmap.InitApplyValid {
+			assert.Error(t, u.InitError())
+			assert.Contains(t, u.InitError().Error(), "a_dynint")
+			assert.NoError(t, u.Start())
+			assert.True(t, u.Ready(), "apply valid policy should be ready")
+		} else {
+			assert.NoError(t, u.InitError())
+		}
+		u.Close()
+	}
+	// missing directory still fails with the apply valid policy.
+	fs := flag.NewFlagSet("initpolicy_test", flag.ContinueOnError)
+	u, err := configmap.New(fs, filepath.Join(dir, "nope"))
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.Error(t, u.WithInitPolicy(configmap.InitApplyValid).Initialize())
+}
+
+func TestInitTimeout(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynstr"), []byte("b"), 0o644))
+	fs := flag.NewFlagSet("initpolicy_test", flag.ContinueOnError)
+	dynStr := dflag.DynString(fs, "some_dynstr", "a", "dynamic string for testing")
+	unblock := make(chan struct{})
+	slow := func(content []byte) ([]byte, error) {
+		<-unblock // simulates a hung file system.
+		return content, nil
+	}
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	err = u.WithTransform(slow).WithInitTimeout(50 * time.Millisecond).Initialize()
+	assert.True(t, errors.Is(err, configmap.ErrInitTimeout), "timeout error expected")
+	assert.False(t, u.Ready())
+	assert.Equal(t, "a", dynStr.Get())
+	close(unblock)
+	// Reload waits for the abandoned read, which doesn't set the flag after the timeout.
+	assert.NoError(t, u.Reload())
+	events := u.Events()
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, context.Canceled.Error(), events[0].Error)
+	assert.Equal(t, "", events[1].Error)
+	assert.Equal(t, "b", dynStr.Get())
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Package kubeapi watches a Kubernetes ConfigMap directly through the Kubernetes API server
+// (instead of a mounted volume) using a client-go informer, and updates the corresponding flags.
+// This avoids the kubelet sync delay and works for pods that can't mount the ConfigMap.
+// In cluster, the pod's service account is used, which needs `get`, `list` and `watch` permissions
+// on the ConfigMap; out of cluster the kubeconfig (KUBECONFIG or ~/.kube/config) is used.
+// It is a separate module so the main dflag module doesn't depend on client-go.
+// It registers the k8s:// scheme with the source package, see FromURL.
+package kubeapi
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"fortio.org/dflag/source"
+	"fortio.org/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// DefaultResync is the default period at which the informer re-delivers the ConfigMap, so values
+// rejected earlier (e.g. by a validator) are retried even if the ConfigMap doesn't change.
+const DefaultResync = 10 * time.Minute
+
+// Config is the configuration of the Kubernetes API ConfigMap watcher.
+// Only Name is required.
+type Config struct {
+	// Name of the ConfigMap to watch.
+	Name string
+	// Namespace of the ConfigMap, defaults to the pod's namespace (or the kubeconfig context's one).
+	Namespace string
+	// Kubeconfig file to use instead of the default loading rules (in cluster config, then
+	// KUBECONFIG or ~/.kube/config). Ignored if Client is set.
+	Kubeconfig string
+	// Context of the kubeconfig to use instead of its current one. Ignored if Client is set.
+	Context string
+	// Resync period of the informer, DefaultResync if 0, negative to disable resyncs.
+	Resync time.Duration
+	// Client to use instead of the one configured from the above.
+	Client kubernetes.Interface
+}
+
+// Watcher is the Kubernetes API ConfigMap watcher, mapping each key of the ConfigMap's
+// `data` and `binaryData` to the flag of the same name.
+type Watcher struct {
+	cfg     Config
+	client  kubernetes.Interface
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	factory informers.SharedInformerFactory
+}
+
+func init() {
+	source.Register("k8s", FromURL)
+}
+
+// FromURL creates a Watcher from a k8s://namespace/name URL, or k8s:///name for the default
+// namespace. The `kubeconfig`, `context` and `resync` (duration) query parameters set the
+// corresponding Config fields.
+func FromURL(u *url.URL) (source.Source, error) {
+	q := u.Query()
+	cfg := Config{
+		Namespace:  u.Host,
+		Name:       strings.Trim(u.Path, "/"),
+		Kubeconfig: q.Get("kubeconfig"),
+		Context:    q.Get("context"),
+	}
+	if r := q.Get("resync"); r != "" {
+		d, err := time.ParseDuration(r)
+		if err != nil {
+			return nil, fmt.Errorf("dflag: kubeapi invalid resync %q: %w", r, err)
+		}
+		cfg.Resync = d
+	}
+	return New(cfg)
+}
+
+// New creates a Watcher for the ConfigMap, creating the client from the in cluster or kubeconfig
+// configuration unless Config.Client is set.
+func New(cfg Config) (*Watcher, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("dflag: kubeapi ConfigMap name is required")
+	}
+	if cfg.Resync == 0 {
+		cfg.Resync = DefaultResync
+	} else if cfg.Resync < 0 {
+		cfg.Resync = 0
+	}
+	client := cfg.Client
+	if client == nil || cfg.Namespace == "" {
+		rules := clientcmd.NewDefaultClientConfigLoadingRules()
+		rules.ExplicitPath = cfg.Kubeconfig
+		clientCfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules,
+			&clientcmd.ConfigOverrides{CurrentContext: cfg.Context})
+		if cfg.Namespace == "" {
+			ns, _, err := clientCfg.Namespace()
+			if err != nil {
+				return nil, fmt.Errorf("dflag: kubeapi namespace not set and can't be determined: %w", err)
+			}
+			cfg.Namespace = ns
+		}
+		if client == nil {
+			restCfg, err := clientCfg.ClientConfig()
+			if err != nil {
+				return nil, fmt.Errorf("dflag: kubeapi client configuration: %w", err)
+			}
+			if client, err = kubernetes.NewForConfig(restCfg); err != nil {
+				return nil, fmt.Errorf("dflag: kubeapi client: %w", err)
+			}
+		}
+	}
+	return &Watcher{cfg: cfg, client: client}, nil
+}
+
+// Setup is a combination/shortcut for New+source.Apply: the ConfigMap is read, setting both
+// static and dynamic flags, then watched for changes of dynamic flags.
+func Setup(ctx context.Context, flagSet *flag.FlagSet, cfg Config) (*source.Applier, error) {
+	w, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return source.Apply(ctx, flagSet, w)
+}
+
+// Initialize reads the ConfigMap for the first time.
+func (w *Watcher) Initialize(ctx context.Context) (map[string][]byte, error) {
+	cm, err := w.client.CoreV1().ConfigMaps(w.cfg.Namespace).Get(ctx, w.cfg.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("dflag: kubeapi get %v/%v: %w", w.cfg.Namespace, w.cfg.Name, err)
+	}
+	return values(cm), nil
+}
+
+// values returns the ConfigMap's values.
+func values(cm *corev1.ConfigMap) map[string][]byte {
+	values := make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+	for k, v := range cm.Data {
+		values[k] = []byte(v)
+	}
+	for k, v := range cm.BinaryData {
+		values[k] = v
+	}
+	return values
+}
+
+// Watch starts the informer watching the ConfigMap for changes (it takes care of reconnecting,
+// re-listing when the watched version expired and resyncs). It stops when the context is done or
+// Stop() is called.
+func (w *Watcher) Watch(ctx context.Context, updates chan<- source.Update) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.factory != nil {
+		return errors.New("dflag: kubeapi watcher already started")
+	}
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.factory = informers.NewSharedInformerFactoryWithOptions(w.client, w.cfg.Resync,
+		informers.WithNamespace(w.cfg.Namespace),
+		informers.WithTweakListOptions(func(o *metav1.ListOptions) {
+			o.FieldSelector = fields.OneTermEqualSelector("metadata.name", w.cfg.Name).String()
+		}))
+	informer := w.factory.Core().V1().ConfigMaps().Informer()
+	send := func(obj interface{}) {
+		cm, ok := obj.(*corev1.ConfigMap)
+		if !ok || cm.Name != w.cfg.Name {
+			return
+		}
+		log.Infof("dflag: kubeapi ConfigMap %v/%v updated (version %v)", w.cfg.Namespace, w.cfg.Name, cm.ResourceVersion)
+		source.Send(ctx, updates, values(cm))
+	}
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    send,
+		UpdateFunc: func(_, obj interface{}) { send(obj) },
+		DeleteFunc: func(_ interface{}) {
+			log.Warnf("dflag: kubeapi ConfigMap %v/%v was deleted, keeping current values", w.cfg.Namespace, w.cfg.Name)
+		},
+	})
+	if err != nil {
+		w.cancel()
+		w.factory = nil
+		return fmt.Errorf("dflag: kubeapi informer: %w", err)
+	}
+	log.Infof("dflag: kubeapi watching ConfigMap %v/%v", w.cfg.Namespace, w.cfg.Name)
+	w.factory.Start(ctx.Done())
+	return nil
+}
+
+// Stop stops the informer and waits for its go routines to exit.
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.factory == nil {
+		return
+	}
+	w.cancel()
+	w.factory.Shutdown()
+	w.factory = nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package kubeapi
+
+import (
+	"context"
+	"flag"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/source"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestKubeAPIWatcher(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm1", Namespace: "ns1"},
+		Data:       map[string]string{"some_dynint": "5", "some_int": "6", "unknown": "x"},
+		BinaryData: map[string][]byte{"some_binary": {0, 1, 2}},
+	}
+	other := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns1"},
+		Data:       map[string]string{"some_dynint": "1000"},
+	}
+	client := fake.NewClientset(cm, other)
+	fs := flag.NewFlagSet("kubeapi_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	staticInt := fs.Int("some_int", 1, "static int for testing")
+	binF := dflag.Dyn(fs, "some_binary", []byte{}, "dynamic binary for testing")
+	_, err := New(Config{})
+	assert.Error(t, err, "name is required")
+	ctx := context.Background()
+	a, err := Setup(ctx, fs, Config{Name: "cm1", Namespace: "ns1", Client: client})
+	assert.NoError(t, err)
+	defer a.Stop()
+	assert.Equal(t, int64(5), dynInt.Get())
+	assert.Equal(t, 6, *staticInt)
+	assert.Equal(t, []byte{0, 1, 2}, binF.Get())
+	cm = cm.DeepCopy()
+	cm.Data = map[string]string{"some_dynint": "7", "some_int": "99"}
+	_, err = client.CoreV1().ConfigMaps("ns1").Update(ctx, cm, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	for i := 0; i < 100 && dynInt.Get() != 7; i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.Equal(t, int64(7), dynInt.Get())
+	assert.Equal(t, 6, *staticInt, "static flags aren't changed by updates")
+	// other ConfigMaps of the namespace are ignored.
+	other = other.DeepCopy()
+	other.Data["some_dynint"] = "1001"
+	_, err = client.CoreV1().ConfigMaps("ns1").Update(ctx, other, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, client.CoreV1().ConfigMaps("ns1").Delete(ctx, "cm1", metav1.DeleteOptions{}))
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int64(7), dynInt.Get(), "deletion keeps the current values")
+	a.Stop()
+	a.Stop() // no-op
+}
+
+func TestNotFound(t *testing.T) {
+	w, err := New(Config{Name: "nope", Namespace: "ns1", Client: fake.NewClientset()})
+	assert.NoError(t, err)
+	_, err = w.Initialize(context.Background())
+	assert.Error(t, err)
+}
+
+func TestFromURLKubeconfig(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	assert.NoError(t, os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: c1
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: ctx1
+  context:
+    cluster: c1
+    namespace: from-kubeconfig
+current-context: ctx1
+`), 0o600))
+	s, err := source.Open("k8s:///cm1?kubeconfig=" + url.QueryEscape(kubeconfig) + "&resync=1m")
+	assert.NoError(t, err)
+	w := s.(*Watcher)
+	assert.Equal(t, "from-kubeconfig", w.cfg.Namespace)
+	assert.Equal(t, "cm1", w.cfg.Name)
+	assert.Equal(t, time.Minute, w.cfg.Resync)
+	s, err = source.Open("k8s://ns2/cm2?kubeconfig=" + url.QueryEscape(kubeconfig) + "&resync=-1s")
+	assert.NoError(t, err)
+	assert.Equal(t, "ns2", s.(*Watcher).cfg.Namespace)
+	assert.Equal(t, time.Duration(0), s.(*Watcher).cfg.Resync, "negative disables resyncs")
+	_, err = source.Open("k8s:///cm1?resync=bad")
+	assert.Error(t, err)
+}
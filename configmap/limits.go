@@ -0,0 +1,70 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+var errNULByte = errors.New("content has NUL byte(s) and the flag isn't binary")
+
+// WithMaxFileSize sets the maximum size of the files the updater will read, larger files are
+// rejected (with an error) instead of being loaded in memory. 0 (the default) means no limit.
+func (u *Updater) WithMaxFileSize(maxBytes int64) *Updater {
+	u.maxFileSize = maxBytes
+	return u
+}
+
+// WithRejectNUL sets whether files with NUL bytes are rejected for non binary flags, which catches
+// mistakenly mounted binary files. Defaults to true.
+func (u *Updater) WithRejectNUL(reject bool) *Updater {
+	u.allowNUL = !reject
+	return u
+}
+
+// readFile reads the file while enforcing the security checks and max size limit if set.
+func (u *Updater) readFile(fullPath string) ([]byte, error) {
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	// Checks are done on the opened file (following symlinks, as ConfigMap files are) to avoid races.
+	st, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkFileSecurity(st); err != nil {
+		return nil, err
+	}
+	if u.maxFileSize <= 0 {
+		return io.ReadAll(f)
+	}
+	if st.Size() > u.maxFileSize {
+		return nil, fmt.Errorf("file size %d is over the %d bytes limit", st.Size(), u.maxFileSize)
+	}
+	// The file may have grown since Stat, so also bound the read itself.
+	content, err := io.ReadAll(io.LimitReader(f, u.maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > u.maxFileSize {
+		return nil, fmt.Errorf("file size is over the %d bytes limit", u.maxFileSize)
+	}
+	return content, nil
+}
+
+func (u *Updater) checkContent(isBinary bool, content []byte) error {
+	if isBinary || u.allowNUL {
+		return nil
+	}
+	if bytes.IndexByte(content, 0) >= 0 {
+		return errNULByte
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/configmap"
+)
+
+func TestLimits(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("limits_test", flag.ContinueOnError)
+	dynStr := dflag.DynString(fs, "some_dynstr", "", "dynamic string for testing")
+	binF := dflag.Dyn(fs, "some_binary", []byte{}, "dynamic binary for testing")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynstr"), []byte("a\x00b"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_binary"), []byte{0, 1, 0}, 0o644))
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	err = u.Initialize()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "NUL")
+	assert.Equal(t, "", dynStr.Get())
+	assert.Equal(t, []byte{0, 1, 0}, binF.Get(), "NUL bytes are fine for binary flags")
+	assert.NoError(t, u.WithRejectNUL(false).Reload())
+	assert.Equal(t, "a\x00b", dynStr.Get())
+	u.WithMaxFileSize(2)
+	err = u.Reload()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "over the 2 bytes limit")
+	assert.Contains(t, err.Error(), "2 errors")
+	assert.NoError(t, u.WithMaxFileSize(3).Reload())
+}
@@ -18,7 +18,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"path"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -42,15 +42,15 @@ func TestDynamicLogLevelAndBinaryFlag(t *testing.T) {
 		t.Fatalf("unexpected error getting tempdir %v", err)
 	}
 	defer os.RemoveAll(tmpDir)
-	pDir := path.Join(tmpDir, "config")
+	pDir := filepath.Join(tmpDir, "config")
 	if err = os.Mkdir(pDir, 0o755); err != nil {
 		t.Fatalf("unable to make %v: %v", pDir, err)
 	}
-	fName := path.Join(pDir, "extra_flag")
+	fName := filepath.Join(pDir, "extra_flag")
 	if err = os.WriteFile(fName, []byte("ignored"), 0o644); err != nil {
 		t.Fatalf("unable to write %v: %v", fName, err)
 	}
-	binaryFlag := path.Join(pDir, "binary_flag")
+	binaryFlag := filepath.Join(pDir, "binary_flag")
 	if err = os.WriteFile(binaryFlag, []byte{0, 1, 2, 3}, 0o644); err != nil {
 		t.Fatalf("unable to write %v: %v", binaryFlag, err)
 	}
@@ -70,7 +70,7 @@ func TestDynamicLogLevelAndBinaryFlag(t *testing.T) {
 	if err = os.WriteFile(binaryFlag, []byte{1, 0}, 0o644); err != nil {
 		t.Fatalf("unable to write %v: %v", binaryFlag, err)
 	}
-	fName = path.Join(pDir, "loglevel")
+	fName = filepath.Join(pDir, "loglevel")
 	// Test also the new normalization (space trimming and capitalization)
 	if err = os.WriteFile(fName, []byte(" InFO\n\n"), 0o644); err != nil {
 		t.Fatalf("unable to write %v: %v", fName, err)
@@ -0,0 +1,145 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fortio.org/dflag"
+)
+
+// metrics are the updater's counters, exposed in Prometheus text format by MetricsHandler()
+// without requiring a dependency on the Prometheus client library.
+type metrics struct {
+	reloads         atomic.Int64
+	reloadFailures  atomic.Int64
+	lastReload      atomic.Int64 // unix nanoseconds.
+	lastReloadOk    atomic.Bool
+	mu              sync.Mutex
+	flagSuccesses   map[string]int64
+	flagFailures    map[string]int64
+	flagLastSuccess map[string]time.Time
+	// hooks also get the flag updates, see WithMetrics.
+	hooks dflag.Metrics
+}
+
+func (m *metrics) recordReload(err error) {
+	m.reloads.Add(1)
+	if err != nil {
+		m.reloadFailures.Add(1)
+	}
+	m.lastReloadOk.Store(err == nil)
+	m.lastReload.Store(time.Now().UnixNano())
+}
+
+func (m *metrics) recordFlagUpdate(flagName string, err error) {
+	if m.hooks != nil {
+		if err != nil {
+			m.hooks.IncSetError(flagName)
+		} else {
+			m.hooks.IncSetSuccess(flagName)
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.flagSuccesses == nil {
+		m.flagSuccesses = make(map[string]int64)
+		m.flagFailures = make(map[string]int64)
+		m.flagLastSuccess = make(map[string]time.Time)
+	}
+	if err != nil {
+		m.flagFailures[flagName]++
+		return
+	}
+	m.flagSuccesses[flagName]++
+	m.flagLastSuccess[flagName] = time.Now()
+}
+
+// escapeLabel escapes a Prometheus label value.
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func writeMetric(w io.Writer, name, mtype, help string, value interface{}) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, mtype, name, value)
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// WriteMetrics writes the updater's metrics in the Prometheus text exposition format.
+func (u *Updater) WriteMetrics(w io.Writer) {
+	m := &u.metrics
+	writeMetric(w, "dflag_configmap_reloads_total", "counter",
+		"Number of full reads of the config directory.", m.reloads.Load())
+	writeMetric(w, "dflag_configmap_reload_failures_total", "counter",
+		"Number of full reads of the config directory that had errors.", m.reloadFailures.Load())
+	writeMetric(w, "dflag_configmap_last_reload_timestamp_seconds", "gauge",
+		"Time of the last full read of the config directory.", float64(m.lastReload.Load())/1e9)
+	writeMetric(w, "dflag_configmap_last_reload_success", "gauge",
+		"Whether the last full read of the config directory was without errors.", boolToInt(m.lastReloadOk.Load()))
+	writeMetric(w, "dflag_configmap_watcher_errors_total", "counter",
+		"Number of errors received from the file watcher.", u.WatchErrors())
+	writeMetric(w, "dflag_configmap_watcher_restarts_total", "counter",
+		"Number of times the file watches were re-established.", u.Rewatches())
+	writeMetric(w, "dflag_configmap_unknown_flags_total", "counter",
+		"Number of files found for unknown flags.", u.Warnings())
+	writeMetric(w, "dflag_configmap_ready", "gauge",
+		"Whether the initial read succeeded and the watcher is running.", boolToInt(u.Ready()))
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.flagSuccesses)+len(m.flagFailures))
+	for n := range m.flagSuccesses {
+		names = append(names, n)
+	}
+	for n := range m.flagFailures {
+		if _, found := m.flagSuccesses[n]; !found {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	fmt.Fprintf(w, "# HELP dflag_configmap_flag_updates_total Number of flag updates from files by result.\n")
+	fmt.Fprintf(w, "# TYPE dflag_configmap_flag_updates_total counter\n")
+	for _, n := range names {
+		l := escapeLabel(n)
+		fmt.Fprintf(w, "dflag_configmap_flag_updates_total{flag=\"%s\",result=\"success\"} %d\n", l, m.flagSuccesses[n])
+		fmt.Fprintf(w, "dflag_configmap_flag_updates_total{flag=\"%s\",result=\"failure\"} %d\n", l, m.flagFailures[n])
+	}
+	fmt.Fprintf(w, "# HELP dflag_configmap_flag_last_update_timestamp_seconds Time of the last successful update of a flag.\n")
+	fmt.Fprintf(w, "# TYPE dflag_configmap_flag_last_update_timestamp_seconds gauge\n")
+	for _, n := range names {
+		if t, found := m.flagLastSuccess[n]; found {
+			fmt.Fprintf(w, "dflag_configmap_flag_last_update_timestamp_seconds{flag=\"%s\"} %v\n",
+				escapeLabel(n), float64(t.UnixNano())/1e9)
+		}
+	}
+}
+
+// WithMetrics also reports the outcome of each flag update the updater applies to m (by file name, including
+// static flags at startup). The flags' own sets and notifier latency are reported by dflag.WithMetrics and
+// dflag.SetMetrics. Must be called before Initialize().
+func (u *Updater) WithMetrics(m dflag.Metrics) *Updater {
+	u.metrics.hooks = m
+	return u
+}
+
+// MetricsHandler returns an http.Handler serving the updater's metrics in the Prometheus
+// text exposition format, to be scraped directly or merged with other metrics.
+func (u *Updater) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, _ *http.Request) {
+		resp.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		u.WriteMetrics(resp)
+	})
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap_test
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/configmap"
+	"fortio.org/dflag/dflagtest"
+)
+
+func TestMetrics(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("metrics_test", flag.ContinueOnError)
+	dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	dflag.DynInt64(fs, "other_dynint", 1, "dynamic int for testing")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("42"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "other_dynint"), []byte("not a number"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "unknown"), []byte("x"), 0o644))
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.Error(t, u.Initialize())
+	resp := httptest.NewRecorder()
+	u.MetricsHandler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	out := resp.Body.String()
+	for _, expected := range []string{
+		"# TYPE dflag_configmap_reloads_total counter\ndflag_configmap_reloads_total 1\n",
+		"dflag_configmap_reload_failures_total 1\n",
+		"dflag_configmap_last_reload_success 0\n",
+		"dflag_configmap_unknown_flags_total 1\n",
+		"dflag_configmap_ready 0\n",
+		`dflag_configmap_flag_updates_total{flag="other_dynint",result="failure"} 1` + "\n",
+		`dflag_configmap_flag_updates_total{flag="some_dynint",result="success"} 1` + "\n",
+		`dflag_configmap_flag_last_update_timestamp_seconds{flag="some_dynint"} `,
+	} {
+		assert.Contains(t, out, expected)
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("metrics_hooks_test", flag.ContinueOnError)
+	dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	dflag.DynInt64(fs, "other_dynint", 1, "dynamic int for testing")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("42"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "other_dynint"), []byte("not a number"), 0o644))
+	m := &dflagtest.Metrics{}
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.Error(t, u.WithMetrics(m).Initialize())
+	assert.Equal(t, 1, m.Successes("some_dynint"))
+	assert.Equal(t, 0, m.Errors("some_dynint"))
+	assert.Equal(t, 1, m.Errors("other_dynint"))
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WithNestedDirs makes the updater descend into subdirectories of the watched directory, mapping
+// files to flags named after their relative path with "/" replaced by separator, e.g. with "."
+// the file `redis/timeout` sets the `redis.timeout` flag. This lets a single mounted ConfigMap tree
+// (using `items` with paths) configure multiple components. An empty separator (the default)
+// disables the behavior. Must be called before Initialize().
+func (u *Updater) WithNestedDirs(separator string) *Updater {
+	u.nestedSep = separator
+	return u
+}
+
+// flagName returns the name of the flag for the file, i.e. its base name unless nested
+// directories are enabled, in which case it's its path relative to the directory it's read from.
+func (u *Updater) flagName(fullPath string) string {
+	if u.nestedSep == "" {
+		return filepath.Base(fullPath)
+	}
+	for _, root := range u.layers() {
+		if rel, ok := relPath(root, fullPath); ok {
+			return strings.ReplaceAll(filepath.ToSlash(rel), "/", u.nestedSep)
+		}
+	}
+	return filepath.Base(fullPath)
+}
+
+// isNestedDir returns true if nested directories are enabled and fullPath is (a symlink to) a directory.
+func (u *Updater) isNestedDir(fullPath string) bool {
+	if u.nestedSep == "" {
+		return false
+	}
+	st, err := os.Stat(fullPath)
+	return err == nil && st.IsDir()
+}
+
+// watchSubdir records the subdirectory and adds a watch on it if the updater is currently watching.
+// The watch is (re)added at each read as Kubernetes' ..data swap replaces the directories behind the paths.
+func (u *Updater) watchSubdir(dirPath string) {
+	if u.watcher == nil {
+		return // Validate()
+	}
+	u.filesMu.Lock()
+	defer u.filesMu.Unlock()
+	if u.subdirs == nil {
+		u.subdirs = make(map[string]bool)
+	}
+	u.subdirs[dirPath] = true
+	if u.watchingSubdirs {
+		u.addSubdirWatch(dirPath)
+	}
+}
+
+// addSubdirWatch must be called with filesMu held.
+func (u *Updater) addSubdirWatch(dirPath string) {
+	if err := u.watcher.Add(dirPath); err != nil {
+		u.logger.Error("dflag: unable to add config sub directory to watch", "dir", dirPath, "error", err)
+	}
+}
+
+// setSubdirWatches adds (or removes) the watches on all the known subdirectories.
+func (u *Updater) setSubdirWatches(watching bool) {
+	u.filesMu.Lock()
+	defer u.filesMu.Unlock()
+	u.watchingSubdirs = watching
+	for dirPath := range u.subdirs {
+		if watching {
+			u.addSubdirWatch(dirPath)
+		} else {
+			_ = u.watcher.Remove(dirPath)
+		}
+	}
+}
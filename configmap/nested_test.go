@@ -0,0 +1,64 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/configmap"
+)
+
+func TestNestedDirs(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("nested_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	redisTimeout := dflag.DynDuration(fs, "redis.timeout", time.Second, "dynamic duration for testing")
+	dbPool := dflag.DynInt64(fs, "db.pool.size", 1, "deeply nested dynamic int for testing")
+	newFlag := dflag.DynString(fs, "cache.mode", "none", "dynamic string for testing")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "redis"), 0o755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "db", "pool"), 0o755))
+	for name, value := range map[string]string{"some_dynint": "42", "redis/timeout": "5s", "db/pool/size": "10"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(value), 0o644))
+	}
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.NoError(t, u.WithNestedDirs(".").Initialize())
+	assert.NoError(t, u.Start())
+	assert.Equal(t, 0, u.Warnings())
+	assert.Equal(t, int64(42), dynInt.Get())
+	assert.Equal(t, 5*time.Second, redisTimeout.Get())
+	assert.Equal(t, int64(10), dbPool.Get())
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "db", "pool", "size"), []byte("20"), 0o644))
+	eventually(t, 1*time.Second, assert.ObjectsAreEqualValues, int64(20),
+		func() interface{} { return dbPool.Get() }, "db.pool.size should change")
+	// new sub directory after start: triggers a full re-read which also starts watching it.
+	numEvents := len(u.Events())
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "cache"), 0o755))
+	eventually(t, 1*time.Second, assert.ObjectsAreEqualValues, true,
+		func() interface{} { return len(u.Events()) >= numEvents+3 }, "new directory should trigger a re-read")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "cache", "mode"), []byte("lru"), 0o644))
+	eventually(t, 1*time.Second, assert.ObjectsAreEqualValues, "lru",
+		func() interface{} { return newFlag.Get() }, "cache.mode in new sub dir should change")
+}
+
+func TestSubdirsIgnoredByDefault(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("nested_test", flag.ContinueOnError)
+	redisTimeout := dflag.DynDuration(fs, "redis.timeout", time.Second, "dynamic duration for testing")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "redis"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "redis", "timeout"), []byte("5s"), 0o644))
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.NoError(t, u.WithExclude("redis").Initialize())
+	assert.Equal(t, time.Second, redisTimeout.Get())
+	assert.Equal(t, 0, u.Warnings())
+}
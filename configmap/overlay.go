@@ -0,0 +1,79 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WithOverlay adds directories layered on top of the watched one, e.g. `config-prod/` over `config/`, so the
+// same image can carry shared defaults plus per environment overrides: for each flag, the file from the last
+// overlay having it wins. Overlays are watched like the base directory and removing a file from an overlay
+// falls back to the value from the layers below. Must be called before Initialize().
+func (u *Updater) WithOverlay(dirPaths ...string) *Updater {
+	for _, d := range dirPaths {
+		u.overlays = append(u.overlays, filepath.Clean(d))
+	}
+	return u
+}
+
+// layers returns the base directory followed by the overlays and the write-back directory (if any),
+// in increasing precedence order.
+func (u *Updater) layers() []string {
+	layers := u.configLayers()
+	if u.writeBackDir != "" {
+		layers = append(layers, u.writeBackDir)
+	}
+	return layers
+}
+
+// configLayers returns the base directory followed by the overlays.
+func (u *Updater) configLayers() []string {
+	return append([]string{u.dirPath}, u.overlays...)
+}
+
+// shadowed returns true if the rel file of the dirPath layer is overridden by a higher layer.
+func (u *Updater) shadowed(dirPath, rel string) bool {
+	above := false
+	for _, layer := range u.layers() {
+		if layer == dirPath {
+			above = true
+			continue
+		}
+		if !above {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(layer, rel)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// effectivePath returns the file, from the highest layer having it, to read for the changed fullPath.
+// It returns fullPath itself if it isn't in a layer or no layer has the file (anymore).
+func (u *Updater) effectivePath(fullPath string) string {
+	layers := u.layers()
+	if len(layers) == 1 {
+		return fullPath
+	}
+	rel := ""
+	for _, layer := range layers {
+		if r, ok := relPath(layer, fullPath); ok {
+			rel = r
+			break
+		}
+	}
+	if rel == "" {
+		return fullPath
+	}
+	for i := len(layers) - 1; i >= 0; i-- {
+		candidate := filepath.Join(layers[i], rel)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return fullPath
+}
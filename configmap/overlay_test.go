@@ -0,0 +1,69 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/configmap"
+)
+
+func TestOverlay(t *testing.T) {
+	root := t.TempDir()
+	base := filepath.Join(root, "config")
+	prod := filepath.Join(root, "config-prod")
+	assert.NoError(t, os.Mkdir(base, 0o755))
+	assert.NoError(t, os.Mkdir(prod, 0o755))
+	fs := flag.NewFlagSet("overlay_test", flag.ContinueOnError)
+	var sets []int64
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing").WithSyncNotifier(func(_, n int64) {
+		sets = append(sets, n)
+	})
+	dynStr := dflag.DynString(fs, "some_dynstr", "a", "dynamic string for testing")
+	onlyProd := dflag.DynString(fs, "prod_only", "x", "dynamic string for testing")
+	for name, value := range map[string]string{
+		"config/some_dynint": "5", "config/some_dynstr": "base", "config-prod/some_dynint": "50", "config-prod/prod_only": "y",
+	} {
+		assert.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(value), 0o644))
+	}
+	u, err := configmap.New(fs, base)
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.NoError(t, u.WithOverlay(prod).WithRevertOnDelete(true).Initialize())
+	assert.NoError(t, u.Start())
+	assert.Equal(t, int64(50), dynInt.Get())
+	assert.Equal(t, []int64{50}, sets, "shadowed base value shouldn't be set")
+	assert.Equal(t, "base", dynStr.Get())
+	assert.Equal(t, "y", onlyProd.Get())
+	// base change of an overridden flag: overlay still wins.
+	assert.NoError(t, os.WriteFile(filepath.Join(base, "some_dynint"), []byte("6"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(base, "some_dynstr"), []byte("base2"), 0o644))
+	eventually(t, 1*time.Second, assert.ObjectsAreEqualValues, "base2",
+		func() interface{} { return dynStr.Get() }, "some_dynstr should change")
+	assert.Equal(t, int64(50), dynInt.Get())
+	// overlay change, then removal falls back to the base value.
+	assert.NoError(t, os.WriteFile(filepath.Join(prod, "some_dynint"), []byte("51"), 0o644))
+	eventually(t, 1*time.Second, assert.ObjectsAreEqualValues, int64(51),
+		func() interface{} { return dynInt.Get() }, "overlay change should apply")
+	assert.NoError(t, os.Remove(filepath.Join(prod, "some_dynint")))
+	eventually(t, 1*time.Second, assert.ObjectsAreEqualValues, int64(6),
+		func() interface{} { return dynInt.Get() }, "should fall back to base value")
+	assert.NoError(t, os.Remove(filepath.Join(prod, "prod_only")))
+	eventually(t, 1*time.Second, assert.ObjectsAreEqualValues, "x",
+		func() interface{} { return onlyProd.Get() }, "should revert to default")
+}
+
+func TestMissingOverlay(t *testing.T) {
+	fs := flag.NewFlagSet("overlay_test", flag.ContinueOnError)
+	u, err := configmap.New(fs, t.TempDir())
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.Error(t, u.WithOverlay(filepath.Join(t.TempDir(), "nope")).Initialize())
+}
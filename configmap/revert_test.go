@@ -0,0 +1,48 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/configmap"
+)
+
+func TestRevertOnDelete(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("revert_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	dynStr := dflag.DynString(fs, "some_dynstr", "default", "dynamic string for testing")
+	staticInt := fs.Int("some_int", 1, "static int for testing")
+	for name, value := range map[string]string{"some_dynint": "42", "some_dynstr": "foo", "some_int": "43"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(value), 0o644))
+	}
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.NoError(t, u.WithRevertOnDelete(true).Initialize())
+	assert.Equal(t, int64(42), dynInt.Get())
+	assert.Equal(t, "foo", dynStr.Get())
+	assert.Equal(t, 43, *staticInt)
+	assert.NoError(t, u.Start())
+	// single file removal event
+	assert.NoError(t, os.Remove(filepath.Join(dir, "some_dynint")))
+	eventually(t, 1*time.Second,
+		assert.ObjectsAreEqualValues, int64(1),
+		func() interface{} { return dynInt.Get() },
+		"some_dynint should revert to its default value")
+	assert.NoError(t, u.Stop())
+	// removal noticed by a full read (like a ConfigMap directory swap)
+	assert.NoError(t, os.Remove(filepath.Join(dir, "some_dynstr")))
+	assert.NoError(t, os.Remove(filepath.Join(dir, "some_int")))
+	assert.NoError(t, u.Reload())
+	assert.Equal(t, "default", dynStr.Get())
+	assert.Equal(t, 43, *staticInt, "static flags are not reverted")
+}
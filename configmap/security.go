@@ -0,0 +1,42 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap
+
+import (
+	"fmt"
+	"os"
+)
+
+// WithRejectWorldWritable makes the updater reject files that are writable by anyone,
+// to prevent untrusted processes (e.g. sidecars) from injecting flag values.
+func (u *Updater) WithRejectWorldWritable(reject bool) *Updater {
+	u.rejectWorldWritable = reject
+	return u
+}
+
+// WithOwnerUID makes the updater reject files not owned by the given user id.
+// Not supported (all files rejected) on platforms without unix style file ownership.
+func (u *Updater) WithOwnerUID(uid int) *Updater {
+	u.checkOwner = true
+	u.ownerUID = uid
+	return u
+}
+
+// checkFileSecurity checks the opened file's info against the opt-in security requirements.
+func (u *Updater) checkFileSecurity(fi os.FileInfo) error {
+	if u.rejectWorldWritable && fi.Mode().Perm()&0o002 != 0 {
+		return fmt.Errorf("file is world writable (%v)", fi.Mode().Perm())
+	}
+	if !u.checkOwner {
+		return nil
+	}
+	uid, ok := fileOwner(fi)
+	if !ok {
+		return fmt.Errorf("file ownership check not supported on this platform")
+	}
+	if uid != u.ownerUID {
+		return fmt.Errorf("file is owned by uid %d instead of the expected %d", uid, u.ownerUID)
+	}
+	return nil
+}
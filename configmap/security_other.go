@@ -0,0 +1,12 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+//go:build !unix
+
+package configmap
+
+import "os"
+
+func fileOwner(_ os.FileInfo) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+//go:build unix
+
+package configmap_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/configmap"
+)
+
+func TestFileSecurity(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("security_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	fName := filepath.Join(dir, "some_dynint")
+	assert.NoError(t, os.WriteFile(fName, []byte("42"), 0o644))
+	assert.NoError(t, os.Chmod(fName, 0o666)) // not subject to umask unlike WriteFile
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.NoError(t, u.Initialize(), "checks are opt-in")
+	assert.Equal(t, int64(42), dynInt.Get())
+	u.WithRejectWorldWritable(true)
+	assert.NoError(t, os.WriteFile(fName, []byte("43"), 0o644))
+	err = u.Reload()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "world writable")
+	assert.Equal(t, int64(42), dynInt.Get())
+	assert.NoError(t, os.Chmod(fName, 0o644))
+	assert.NoError(t, u.WithOwnerUID(os.Getuid()).Reload())
+	assert.Equal(t, int64(43), dynInt.Get())
+	err = u.WithOwnerUID(os.Getuid() + 1).Reload()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "instead of the expected")
+}
@@ -0,0 +1,13 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+//go:build !js
+
+package configmap
+
+import (
+	"os"
+	"syscall"
+)
+
+var defaultReloadSignals = []os.Signal{syscall.SIGHUP}
@@ -0,0 +1,11 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+//go:build js
+
+package configmap
+
+import "os"
+
+// no SIGHUP for js/wasm.
+var defaultReloadSignals []os.Signal
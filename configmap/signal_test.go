@@ -0,0 +1,71 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+//go:build !windows && !plan9 && !js
+
+package configmap_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/configmap"
+)
+
+func TestReloadOnSignal(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("signal_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.NoError(t, u.Initialize())
+	// Not started on purpose: the only way to get the update is the signal.
+	stop := u.ReloadOnSignal()
+	defer stop()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("42"), 0o644))
+	assert.Equal(t, int64(1), dynInt.Get())
+	assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	for i := 0; i < 50 && dynInt.Get() != 42; i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.Equal(t, int64(42), dynInt.Get())
+}
+
+func TestReloadWhileWatching(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("reload_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	dynStr := dflag.DynString(fs, "some_dynstring", "a", "dynamic string for testing")
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.NoError(t, u.Initialize())
+	assert.NoError(t, u.Start())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			_ = u.Reload()
+		}
+	}()
+	// file events (applied by the watcher) concurrent with the reloads, ran with -race.
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynstring"), []byte(fmt.Sprintf("v%d", i)), 0o644))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("42"), 0o644))
+	<-done
+	for i := 0; i < 50 && dynInt.Get() != 42; i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.Equal(t, int64(42), dynInt.Get())
+	assert.NoError(t, u.Reload())
+	assert.Equal(t, "v19", dynStr.Get())
+}
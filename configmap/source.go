@@ -0,0 +1,128 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/url"
+	"os"
+	"sync"
+
+	"fortio.org/dflag/source"
+)
+
+func init() {
+	source.Register("dir", FromURL)
+	source.Register("file", FromURL)
+}
+
+// Source is the source.Source of the values of a directory's files (e.g. a mounted ConfigMap),
+// for use with the source package's Applier like the remote backends. The Updater options
+// (WithInclude, WithOverlay, WithDebounce,...) are available through the embedded Updater.
+// As the flags aren't known to the Updater, all the files are treated as text: global transforms
+// and the NUL byte check apply to all of them (see WithRejectNUL for binary flags' files).
+type Source struct {
+	*Updater
+	mu sync.Mutex
+	// values collected by Initialize.
+	values map[string][]byte
+	// ctx and updates of the current Watch.
+	ctx     context.Context
+	updates chan<- source.Update
+}
+
+// FromURL creates a Source from a dir:///path/to/dir or file:///path/to/dir URL.
+func FromURL(u *url.URL) (source.Source, error) {
+	dirPath := u.Path
+	if dirPath == "" {
+		dirPath = u.Opaque // relative path, e.g. dir:config
+	}
+	return NewSource(dirPath)
+}
+
+// NewSource creates a Source for the directory.
+func NewSource(dirPath string) (*Source, error) {
+	if dirPath == "" {
+		return nil, errors.New("dflag: configmap source directory is required")
+	}
+	u, err := New(flag.NewFlagSet(dirPath, flag.ContinueOnError), dirPath)
+	if err != nil {
+		return nil, err
+	}
+	s := &Source{Updater: u}
+	u.emit = s.send
+	return s, nil
+}
+
+// Initialize reads the values from the directory for the first time.
+func (s *Source) Initialize(_ context.Context) (map[string][]byte, error) {
+	s.mu.Lock()
+	s.values = make(map[string][]byte)
+	s.mu.Unlock()
+	err := s.Updater.Initialize()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	values := s.values
+	s.values = nil
+	return values, err
+}
+
+// Watch starts watching the directory, sending the changed files' values as updates
+// (and removed files as Deleted ones) until the context is done or Stop() is called.
+func (s *Source) Watch(ctx context.Context, updates chan<- source.Update) error {
+	s.mu.Lock()
+	s.ctx, s.updates = ctx, updates
+	s.mu.Unlock()
+	return s.Updater.StartWithContext(ctx)
+}
+
+// Stop stops watching the directory and waits for the watching go routine to exit.
+// Use Close() to also release the underlying file watcher.
+func (s *Source) Stop() {
+	_ = s.Updater.Stop()
+}
+
+// send records the update during Initialize or sends it while watching.
+func (s *Source) send(update source.Update) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values != nil {
+		if !update.Deleted {
+			s.values[update.Name] = update.Value
+		}
+		return
+	}
+	if s.updates == nil {
+		return
+	}
+	select {
+	case s.updates <- update:
+	case <-s.ctx.Done():
+	}
+}
+
+// emitFromFile reads the file and sends its value for the Source.
+func (u *Updater) emitFromFile(ctx context.Context, flagName, fullPath string) (string, error) {
+	content, err := u.readFile(fullPath)
+	if errors.Is(err, os.ErrNotExist) {
+		u.emit(source.Update{Name: flagName, Deleted: true})
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if err = u.checkContent(false, content); err != nil {
+		return "", err
+	}
+	if content, err = u.transform(flagName, false, content); err != nil {
+		return "", err
+	}
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	u.emit(source.Update{Name: flagName, Value: content})
+	return string(content), nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap_test
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/configmap"
+	"fortio.org/dflag/source"
+)
+
+func TestSource(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("5"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_int"), []byte("6"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "unknown"), []byte("x"), 0o644))
+	fs := flag.NewFlagSet("source_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	dynStr := dflag.DynString(fs, "some_dynstr", "a", "dynamic string for testing")
+	staticInt := fs.Int("some_int", 1, "static int for testing")
+	a, err := source.Setup(context.Background(), fs, "dir://"+dir)
+	assert.NoError(t, err)
+	defer a.Stop()
+	assert.Equal(t, int64(5), dynInt.Get())
+	assert.Equal(t, 6, *staticInt)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("7"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_int"), []byte("99"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynstr"), []byte("b"), 0o644))
+	for i := 0; i < 100 && (dynInt.Get() != 7 || dynStr.Get() != "b"); i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.Equal(t, int64(7), dynInt.Get())
+	assert.Equal(t, "b", dynStr.Get())
+	assert.Equal(t, 6, *staticInt, "static flags aren't changed by updates")
+	a.Stop()
+	_ = a.Source().(*configmap.Source).Close()
+	_, err = source.Open("file:")
+	assert.Error(t, err, "directory is required")
+}
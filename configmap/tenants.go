@@ -0,0 +1,52 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fortio.org/dflag/source"
+)
+
+// ApplyTenants sets, and keeps updated, the per tenant overlay values of the dynamic flags of flagSet
+// (see dflag.GetFor) from the subdirectories of dirPath: the files of dirPath/acme set the values for
+// the "acme" tenant. Tenant directories created later need another call (or their own Applier, see
+// source.Applier's WithTenant). On error, the Appliers already started are stopped.
+func ApplyTenants(ctx context.Context, flagSet *flag.FlagSet, dirPath string) ([]*source.Applier, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	var appliers []*source.Applier
+	stopAll := func() {
+		for _, a := range appliers {
+			a.Stop()
+		}
+	}
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue // ConfigMap internals, e.g. ..data
+		}
+		src, err := NewSource(filepath.Join(dirPath, e.Name()))
+		if err != nil {
+			stopAll()
+			return nil, err
+		}
+		a := source.NewApplier(flagSet, src).WithTenant(e.Name())
+		if err = a.Initialize(ctx); err == nil {
+			err = a.Start(ctx)
+		}
+		if err != nil {
+			_ = src.Close()
+			stopAll()
+			return nil, err
+		}
+		appliers = append(appliers, a)
+	}
+	return appliers, nil
+}
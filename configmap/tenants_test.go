@@ -0,0 +1,50 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap_test
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/configmap"
+)
+
+func TestApplyTenants(t *testing.T) {
+	dir := t.TempDir()
+	for _, tenant := range []string{"acme", "beta", "..data"} {
+		assert.NoError(t, os.Mkdir(filepath.Join(dir, tenant), 0o755))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "acme", "some_dynint"), []byte("5"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "beta", "some_dynint"), []byte("6"), 0o644))
+	fs := flag.NewFlagSet("tenants_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	appliers, err := configmap.ApplyTenants(context.Background(), fs, dir)
+	assert.NoError(t, err)
+	defer func() {
+		for _, a := range appliers {
+			a.Stop()
+			_ = a.Source().(*configmap.Source).Close()
+		}
+	}()
+	assert.Equal(t, 2, len(appliers))
+	assert.Equal(t, int64(1), dynInt.Get(), "base value unchanged")
+	assert.Equal(t, int64(5), dynInt.GetFor("acme"))
+	assert.Equal(t, int64(6), dynInt.GetFor("beta"))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "acme", "some_dynint"), []byte("7"), 0o644))
+	assert.NoError(t, os.Remove(filepath.Join(dir, "beta", "some_dynint")))
+	for i := 0; i < 100 && (dynInt.GetFor("acme") != 7 || dynInt.GetFor("beta") != 1); i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.Equal(t, int64(7), dynInt.GetFor("acme"))
+	assert.Equal(t, int64(1), dynInt.GetFor("beta"), "overlay removed with its file")
+	assert.Equal(t, []string{"acme"}, dynInt.Tenants())
+	_, err = configmap.ApplyTenants(context.Background(), fs, filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
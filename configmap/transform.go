@@ -0,0 +1,87 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// Transform changes the content of a file before it is used to set a flag.
+type Transform func(content []byte) ([]byte, error)
+
+// WithTransform adds a transform applied, in the order they are added, to the content of the files
+// for the given flag names before setting them. Without flag names the transform is global and applies to
+// all the non binary flags. Global transforms run before the per flag ones.
+func (u *Updater) WithTransform(t Transform, flagNames ...string) *Updater {
+	if len(flagNames) == 0 {
+		u.globalTransforms = append(u.globalTransforms, t)
+		return u
+	}
+	if u.flagTransforms == nil {
+		u.flagTransforms = make(map[string][]Transform)
+	}
+	for _, name := range flagNames {
+		u.flagTransforms[name] = append(u.flagTransforms[name], t)
+	}
+	return u
+}
+
+func (u *Updater) transform(flagName string, isBinary bool, content []byte) ([]byte, error) {
+	var err error
+	if !isBinary {
+		for _, t := range u.globalTransforms {
+			if content, err = t(content); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, t := range u.flagTransforms[flagName] {
+		if content, err = t(content); err != nil {
+			return nil, err
+		}
+	}
+	return content, nil
+}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// TrimBOM removes the UTF-8 byte order mark some editors add at the beginning of files.
+func TrimBOM(content []byte) ([]byte, error) {
+	return bytes.TrimPrefix(content, utf8BOM), nil
+}
+
+// StripComments removes the lines starting with # (ignoring leading whitespace),
+// so operators can document values in ConfigMaps.
+func StripComments(content []byte) ([]byte, error) {
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	res := make([]byte, 0, len(content))
+	for _, l := range lines {
+		if bytes.HasPrefix(bytes.TrimLeft(l, " \t"), []byte("#")) {
+			continue
+		}
+		res = append(res, l...)
+	}
+	return res, nil
+}
+
+var envVarRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExpandEnv replaces ${VAR} references by the value of the corresponding environment variable.
+// It errors if a variable isn't set. Lone $ and $VAR (without braces) are left as is.
+func ExpandEnv(content []byte) ([]byte, error) {
+	var err error
+	res := envVarRef.ReplaceAllFunc(content, func(ref []byte) []byte {
+		name := string(envVarRef.FindSubmatch(ref)[1])
+		v, found := os.LookupEnv(name)
+		if !found {
+			err = fmt.Errorf("environment variable %q is not set", name)
+			return ref
+		}
+		return []byte(v)
+	})
+	return res, err
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap_test
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/configmap"
+)
+
+func TestBuiltinTransforms(t *testing.T) {
+	out, err := configmap.TrimBOM([]byte("\xEF\xBB\xBF42"))
+	assert.NoError(t, err)
+	assert.Equal(t, "42", string(out))
+	out, err = configmap.StripComments([]byte("# the answer\n  # really\n42\nfoo#bar\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "42\nfoo#bar\n", string(out))
+	t.Setenv("DFLAG_TEST_VAR", "xyz")
+	out, err = configmap.ExpandEnv([]byte("a ${DFLAG_TEST_VAR} $DFLAG_TEST_VAR $ b"))
+	assert.NoError(t, err)
+	assert.Equal(t, "a xyz $DFLAG_TEST_VAR $ b", string(out))
+	_, err = configmap.ExpandEnv([]byte("${DFLAG_TEST_NO_SUCH_VAR}"))
+	assert.Error(t, err)
+}
+
+func TestTransforms(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("transform_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	dynStr := dflag.DynString(fs, "some_dynstr", "", "dynamic string for testing")
+	binF := dflag.Dyn(fs, "some_binary", []byte{}, "dynamic binary for testing")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("\xEF\xBB\xBF# comment\n42\n"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynstr"), []byte("${HOME}"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_binary"), []byte("# not a comment"), 0o644))
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	u.WithTransform(configmap.TrimBOM).WithTransform(configmap.StripComments)
+	u.WithTransform(configmap.ExpandEnv, "some_dynstr")
+	assert.NoError(t, u.Initialize())
+	assert.Equal(t, int64(42), dynInt.Get())
+	assert.Equal(t, os.Getenv("HOME"), dynStr.Get())
+	assert.Equal(t, "# not a comment", string(binF.Get()), "global transforms don't apply to binary flags")
+	u.WithTransform(func([]byte) ([]byte, error) { return nil, errors.New("transform error") }, "some_dynint")
+	err = u.Reload()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "transform error")
+}
@@ -6,52 +6,128 @@
 package configmap
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
-	"path"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"fortio.org/dflag"
 	"fortio.org/dflag/dynloglevel"
-	"fortio.org/log"
-	"github.com/fsnotify/fsnotify"
+	"fortio.org/dflag/source"
 )
 
 const (
 	k8sInternalsPrefix = ".."
 	k8sDataSymlink     = "..data"
+	// Initial and maximum delay between attempts to re-establish the watches after a watcher error.
+	minRewatchBackoff = 100 * time.Millisecond
+	maxRewatchBackoff = 30 * time.Second
 )
 
 var (
-	errFlagNotDynamic = errors.New("flag is not dynamic")
-	errFlagNotFound   = errors.New("flag not found")
+	// ErrFlagNotDynamic is returned when trying to update a static flag after initialization.
+	ErrFlagNotDynamic = source.ErrFlagNotDynamic
+	// ErrFlagNotFound is returned for values of unknown flags.
+	ErrFlagNotFound = source.ErrFlagNotFound
 )
 
 // Updater is the encapsulation of the directory watcher.
 // TODO: hide details, just return opaque interface.
 type Updater struct {
 	started    bool
+	initCalled bool // Initialize can only be called once.
 	dirPath    string
 	parentPath string
-	watcher    *fsnotify.Watcher
+	watcher    *fsWatcher
 	flagSet    *flag.FlagSet
-	done       chan bool
-	warnings   atomic.Int32 // Count of unknown flags that have been logged (increases at each iteration).
-	errors     atomic.Int32 // Count of validation errors that have been logged (increases at each iteration).
+	// additional FlagSets driven by this updater, see WithFlagSet.
+	extraFlagSets []prefixedFlagSet
+	ctx           context.Context // stops the watching go routine when done.
+	done          chan bool
+	exited        chan struct{} // closed when the watching go routine returns.
+	warnings      atomic.Int32  // Count of unknown flags that have been logged (increases at each iteration).
+	errors        atomic.Int32  // Count of validation errors that have been logged (increases at each iteration).
+	// Count of errors received from the file watcher.
+	watchErrors atomic.Int32
+	// Count of successful re-establishment of the watches after errors.
+	rewatches atomic.Int32
+	// initialized is set once the initial read of the directory succeeded.
+	initialized atomic.Bool
+	// watching is true while the background watcher go routine is running.
+	watching atomic.Bool
+	// dryRun makes the updater only validate the values instead of setting them.
+	dryRun bool
+	// emit, when set, gets the values instead of them being set on flags, see Source.
+	emit func(update source.Update)
+	// writeBackDir is where Persist() writes values, empty when write-back isn't enabled.
+	writeBackDir string
+	writeBackErr error // invalid WithWriteBack call.
+	// revertOnDelete resets flags to their default when their file is removed.
+	revertOnDelete bool
+	// applyMu serializes reading files and setting flags between the watcher go routine, Reload and Start.
+	applyMu    sync.Mutex
+	filesMu    sync.Mutex
+	knownFiles map[string]bool // files found during the last full read of the directory.
+	// limits on the files contents.
+	maxFileSize int64
+	allowNUL    bool
+	// opt-in security checks on the files.
+	rejectWorldWritable bool
+	checkOwner          bool
+	ownerUID            int
+	// transforms applied to file contents before setting flags.
+	globalTransforms []Transform
+	flagTransforms   map[string][]Transform
+	metrics          metrics
+	events           eventsRing
+	// debounce is the window during which events are coalesced before acting on them (0 for none).
+	debounce time.Duration
+	// glob patterns selecting the files considered, see WithInclude and WithExclude.
+	includes []string
+	excludes []string
+	// nestedSep is the separator replacing "/" in flag names for files in subdirectories, see WithNestedDirs.
+	nestedSep string
+	// subdirs found when nested directories are enabled and whether they get watched (protected by filesMu).
+	subdirs         map[string]bool
+	watchingSubdirs bool
+	// overlays are directories layered, with precedence, on top of dirPath, see WithOverlay.
+	overlays []string
+	// initial read options, see WithInitPolicy and WithInitTimeout.
+	initPolicy  InitPolicy
+	initTimeout time.Duration
+	initErr     error // errors ignored because of the InitApplyValid policy.
+	// logger gets the updater's logs, see WithLogger.
+	logger dflag.Logger
+}
+
+// prefixedFlagSet is a FlagSet whose flags' files are named prefix + flag name.
+type prefixedFlagSet struct {
+	flagSet *flag.FlagSet
+	prefix  string
 }
 
 // Setup is a combination/shortcut for New+Initialize+Start.
 // It also sets up the `loglevel` flag.
 func Setup(flagSet *flag.FlagSet, dirPath string) (*Updater, error) {
+	return SetupWithContext(context.Background(), flagSet, dirPath)
+}
+
+// SetupWithContext is like Setup but the watching stops when the passed context is done.
+func SetupWithContext(ctx context.Context, flagSet *flag.FlagSet, dirPath string) (*Updater, error) {
 	dynloglevel.LoggerFlagSetup()
-	log.Infof("Configmap flag value watching on %v", dirPath)
-	u, err := New(flagSet, dirPath)
+	u, err := NewWithContext(ctx, flagSet, dirPath)
 	if err != nil {
 		return nil, err
 	}
+	u.logger.Info("dflag: configmap flag value watching", "dir", dirPath)
 	err = u.Initialize()
 	if err != nil {
 		return nil, err
@@ -64,87 +140,424 @@ func Setup(flagSet *flag.FlagSet, dirPath string) (*Updater, error) {
 
 // New creates an Updater for the directory.
 func New(flagSet *flag.FlagSet, dirPath string) (*Updater, error) {
-	watcher, err := fsnotify.NewWatcher()
+	return NewWithContext(context.Background(), flagSet, dirPath)
+}
+
+// NewWithContext creates an Updater for the directory whose watching go routine
+// (once started) stops when the context is done. Call Stop() before restarting it with Start(),
+// or use StartWithContext() to resume watching with a new context once ctx is done.
+func NewWithContext(ctx context.Context, flagSet *flag.FlagSet, dirPath string) (*Updater, error) {
+	watcher, err := newWatcher()
 	if err != nil {
-		return nil, errors.New("dflag: error initializing fsnotify watcher")
+		return nil, fmt.Errorf("dflag: error initializing file watcher: %w", err)
 	}
 	return &Updater{
 		flagSet:    flagSet,
-		dirPath:    path.Clean(dirPath),
-		parentPath: path.Clean(path.Join(dirPath, "..")), // add parent in case the dirPath is a symlink itself
+		dirPath:    filepath.Clean(dirPath),
+		parentPath: filepath.Clean(filepath.Join(dirPath, "..")), // add parent in case the dirPath is a symlink itself
 		watcher:    watcher,
+		ctx:        ctx,
+		logger:     dynloglevel.FortioLogger(),
+		events:     eventsRing{size: DefaultEventsBufferSize},
 		started:    false,
 		done:       nil,
 	}, nil
 }
 
+// WithDebounce sets a window (e.g. 100ms) during which file events are coalesced before
+// (re)reading the corresponding flags, to avoid repeated reads and log spam when a single
+// ConfigMap update generates many events. Must be called before Start().
+func (u *Updater) WithDebounce(window time.Duration) *Updater {
+	u.debounce = window
+	return u
+}
+
+// WithLogger sends the updater's logs to logger (e.g. a *slog.Logger) instead of fortio.org/log,
+// nil restores the default. Must be called before Initialize().
+func (u *Updater) WithLogger(logger dflag.Logger) *Updater {
+	if logger == nil {
+		logger = dynloglevel.FortioLogger()
+	}
+	u.logger = logger
+	return u
+}
+
+// Logger returns the updater's logger (see WithLogger): a Source's Applier logs to it too
+// (see source.LoggerProvider).
+func (u *Updater) Logger() dflag.Logger {
+	return u.logger
+}
+
+// WithFlagSet makes the updater also drive the flagSet (e.g. a library's private FlagSet),
+// whose flags are read from files named prefix + flag name (e.g. prefix "mylib." for a
+// `mylib.timeout` file setting the `timeout` flag of flagSet). The main FlagSet passed to New
+// is looked up first, then the additional ones in the order they were added.
+// Names used by the other Updater APIs (transforms, events, metrics, Persist) are the file names.
+// Must be called before Initialize().
+func (u *Updater) WithFlagSet(flagSet *flag.FlagSet, prefix string) *Updater {
+	u.extraFlagSets = append(u.extraFlagSets, prefixedFlagSet{flagSet: flagSet, prefix: prefix})
+	return u
+}
+
+// lookup finds the flag for the file name, returning its FlagSet and name within it.
+func (u *Updater) lookup(fileName string) (*flag.FlagSet, string, *flag.Flag) {
+	if f := u.flagSet.Lookup(fileName); f != nil {
+		return u.flagSet, fileName, f
+	}
+	for _, pfs := range u.extraFlagSets {
+		if !strings.HasPrefix(fileName, pfs.prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(fileName, pfs.prefix)
+		if f := pfs.flagSet.Lookup(name); f != nil {
+			return pfs.flagSet, name, f
+		}
+	}
+	return nil, "", nil
+}
+
+// WithEventsBuffer changes how many of the most recent events are kept for Events().
+// 0 disables keeping events. Must be called before Initialize().
+func (u *Updater) WithEventsBuffer(size int) *Updater {
+	u.events = eventsRing{size: size}
+	return u
+}
+
+// Validate parses and validates every file of the directory against the registered flags
+// without changing any flag value, e.g. for CI to check a ConfigMap render against the actual binary.
+// Unlike Initialize, files for unknown flags are reported as errors.
+func Validate(flagSet *flag.FlagSet, dirPath string) error {
+	u := &Updater{flagSet: flagSet, dirPath: filepath.Clean(dirPath), dryRun: true, logger: dynloglevel.FortioLogger()}
+	err := u.readAll(context.Background(), false /* dynamicOnly */)
+	if u.Warnings() == 0 {
+		return err
+	}
+	unknown := fmt.Errorf("%d file(s) for unknown flags", u.Warnings())
+	if err == nil {
+		return unknown
+	}
+	return fmt.Errorf("%w\n%v", err, unknown)
+}
+
 // Initialize reads the values from the directory for the first time.
+// It can only be called once, including after a Stop(): restarting is done with Start().
 func (u *Updater) Initialize() error {
-	if u.started {
+	if u.started || u.initCalled {
 		return errors.New("dflag: already initialized updater")
 	}
-	return u.readAll( /* allowNonDynamic */ false)
+	if err := u.checkPatterns(); err != nil {
+		return err
+	}
+	if err := u.checkWriteBack(); err != nil {
+		return err
+	}
+	u.initCalled = true
+	err := u.initialRead()
+	u.initialized.Store(err == nil)
+	return err
 }
 
 // Start kicks off the go routine that watches the directory for updates of values.
+// It can be called again after Stop() to resume watching, in which case the dynamic flags are
+// re-read to catch up with changes made in between.
 func (u *Updater) Start() error {
+	return u.StartWithContext(u.ctx)
+}
+
+// StartWithContext is like Start but the watching go routine stops when ctx is done, which also
+// replaces the updater's context: this is how an updater whose context is done can be restarted.
+func (u *Updater) StartWithContext(ctx context.Context) error {
 	if u.started {
-		return errors.New("dflag: updater already started")
+		select {
+		case <-u.exited:
+			// context was done, go routine is gone: cleanup and allow restart.
+			u.stopWatches()
+		default:
+			return errors.New("dflag: updater already started")
+		}
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("dflag: can't start updater: %w", ctx.Err())
 	}
-	if err := u.watcher.Add(u.parentPath); err != nil {
-		return fmt.Errorf("unable to add parent dir %v to watch: %w", u.parentPath, err)
+	if err := u.addWatches(); err != nil {
+		return err
 	}
-	if err := u.watcher.Add(u.dirPath); err != nil { // add the dir itself.
-		return fmt.Errorf("unable to add config dir %v to watch: %w", u.dirPath, err)
+	if u.exited != nil {
+		u.logger.Info("dflag: re-reading flags on restart", "dir", u.dirPath)
+		if err := u.Reload(); err != nil {
+			u.logger.Error("dflag: directory reload yielded errors", "dir", u.dirPath, "error", err)
+		}
 	}
-	log.Infof("Now watching %v and %v", u.parentPath, u.dirPath)
+	u.logger.Info("dflag: now watching", "parent", u.parentPath, "dir", u.dirPath)
 	u.started = true
+	u.ctx = ctx
 	u.done = make(chan bool)
-	go u.watchForUpdates()
+	u.exited = make(chan struct{})
+	u.watching.Store(true)
+	go u.watchForUpdates(ctx)
+	return nil
+}
+
+func (u *Updater) addWatches() error {
+	for _, dirPath := range u.layers() {
+		parentPath := filepath.Clean(filepath.Join(dirPath, "..")) // add parent in case the dirPath is a symlink itself
+		if err := u.watcher.Add(parentPath); err != nil {
+			return fmt.Errorf("unable to add parent dir %v to watch: %w", parentPath, err)
+		}
+		if err := u.watcher.Add(dirPath); err != nil { // add the dir itself.
+			return fmt.Errorf("unable to add config dir %v to watch: %w", dirPath, err)
+		}
+	}
+	u.setSubdirWatches(true)
 	return nil
 }
 
-// Stop stops the auto-updating go-routine.
+// Stop stops the auto-updating go-routine and waits for it to exit.
+// The Updater can be started again afterwards (but not re-initialized).
 func (u *Updater) Stop() error {
 	if !u.started {
 		return errors.New("dflag: not updating")
 	}
-	u.done <- true
-	_ = u.watcher.Remove(u.dirPath)
-	_ = u.watcher.Remove(u.parentPath)
+	close(u.done)
+	<-u.exited
+	u.stopWatches()
 	return nil
 }
 
-func (u *Updater) readAll(dynamicOnly bool) error {
-	files, err := os.ReadDir(u.dirPath)
-	if err != nil {
-		return fmt.Errorf("dflag: updater initialization: %w", err)
+// Close stops the updater if needed and releases the underlying file watcher.
+// The Updater can't be used after Close.
+func (u *Updater) Close() error {
+	if u.started {
+		_ = u.Stop()
+	}
+	return u.watcher.Close()
+}
+
+func (u *Updater) stopWatches() {
+	for _, dirPath := range u.layers() {
+		_ = u.watcher.Remove(dirPath)
+		_ = u.watcher.Remove(filepath.Clean(filepath.Join(dirPath, "..")))
+	}
+	u.setSubdirWatches(false)
+	u.started = false
+}
+
+// readAll reads all the layers, stopping (without setting any more flag) once ctx is done.
+func (u *Updater) readAll(ctx context.Context, dynamicOnly bool) (err error) {
+	defer func() { u.metrics.recordReload(err) }()
+	names := []string{}
+	complete := true
+	for _, dirPath := range u.layers() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		layerNames, layerErr := u.readDir(ctx, dirPath, dynamicOnly)
+		if layerNames == nil {
+			complete = false // don't revert flags because a layer couldn't be read.
+		}
+		names = append(names, layerNames...)
+		if layerErr != nil {
+			if err == nil {
+				err = layerErr
+			} else {
+				err = fmt.Errorf("%w\n%v", err, layerErr)
+			}
+			if u.stopReading(dynamicOnly, []string{layerErr.Error()}) {
+				break
+			}
+		}
 	}
+	if complete && u.revertOnDelete && !u.dryRun {
+		u.revertRemoved(names)
+	}
+	return err
+}
+
+// readDir returns the names (relative paths) of the files considered (nil if the directory couldn't be read).
+func (u *Updater) readDir(ctx context.Context, dirPath string, dynamicOnly bool) ([]string, error) {
+	names := []string{}
 	errorStrings := []string{}
+	if err := u.readTree(ctx, dirPath, "", dynamicOnly, &names, &errorStrings); err != nil {
+		return nil, fmt.Errorf("dflag: updater initialization: %w", err)
+	}
+	if len(errorStrings) > 0 {
+		return names, fmt.Errorf("encountered %d errors while parsing flags from directory  \n  %v",
+			len(errorStrings), strings.Join(errorStrings, "\n"))
+	}
+	return names, nil
+}
+
+// readTree reads the files of the rel sub directory of dirPath, recursing into subdirectories
+// when nested directories are enabled.
+func (u *Updater) readTree(ctx context.Context, dirPath, rel string, dynamicOnly bool, names, errorStrings *[]string) error {
+	files, err := os.ReadDir(filepath.Join(dirPath, rel))
+	if err != nil {
+		return err
+	}
+	fileNames := []string{}
 	for _, f := range files {
-		if strings.HasPrefix(path.Base(f.Name()), ".") {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if u.stopReading(dynamicOnly, *errorStrings) {
+			return nil
+		}
+		if strings.HasPrefix(filepath.Base(f.Name()), ".") {
 			// skip random ConfigMap internals and dot files
 			continue
 		}
-		fullPath := path.Join(u.dirPath, f.Name())
-		log.S(log.Debug, "checking flag", log.Str("flag", f.Name()), log.Str("path", fullPath))
-		if err := u.readFlagFile(fullPath, dynamicOnly); err != nil {
-			if errors.Is(err, errFlagNotFound) {
-				log.S(log.Warning, "config map for unknown flag", log.Str("flag", f.Name()), log.Str("path", fullPath))
-				u.warnings.Add(1)
-			} else if !(errors.Is(err, errFlagNotDynamic) && dynamicOnly) {
-				errorStrings = append(errorStrings, fmt.Sprintf("flag %v: %v", f.Name(), err.Error()))
+		if u.ignored(f.Name()) {
+			u.logger.Debug("dflag: ignoring file (include/exclude patterns)", "file", f.Name())
+			continue
+		}
+		name := filepath.Join(rel, f.Name())
+		fullPath := filepath.Join(dirPath, name)
+		if u.isNestedDir(fullPath) {
+			u.watchSubdir(fullPath)
+			if err := u.readTree(ctx, dirPath, name, dynamicOnly, names, errorStrings); err != nil {
+				*errorStrings = append(*errorStrings, fmt.Sprintf("directory %v: %v", name, err.Error()))
 				u.errors.Add(1)
 			}
+			continue
 		}
+		fileNames = append(fileNames, name)
 	}
-	if len(errorStrings) > 0 {
-		return fmt.Errorf("encountered %d errors while parsing flags from directory  \n  %v",
-			len(errorStrings), strings.Join(errorStrings, "\n"))
+	for _, name := range u.applyOrder(dirPath, fileNames) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if u.stopReading(dynamicOnly, *errorStrings) {
+			return nil
+		}
+		fullPath := filepath.Join(dirPath, name)
+		*names = append(*names, name)
+		if u.shadowed(dirPath, name) {
+			continue // the value comes from an overlay.
+		}
+		flagName := u.flagName(fullPath)
+		u.logger.Debug("checking flag", "flag", flagName, "path", fullPath)
+		if err := u.readFlagFile(ctx, fullPath, dynamicOnly); err != nil {
+			if errors.Is(err, ErrFlagNotFound) {
+				u.logger.Warn("config map for unknown flag", "flag", flagName, "path", fullPath)
+				u.warnings.Add(1)
+			} else if !(errors.Is(err, ErrFlagNotDynamic) && dynamicOnly) {
+				*errorStrings = append(*errorStrings, fmt.Sprintf("flag %v: %v", flagName, err.Error()))
+				u.errors.Add(1)
+			}
+		}
 	}
 	return nil
 }
 
+// applyOrder returns the file names (of a directory) in the order their flags should be set,
+// see dflag.ApplyOrder.
+func (u *Updater) applyOrder(dirPath string, fileNames []string) []string {
+	flagNames := make([]string, 0, len(fileNames))
+	byFlag := make(map[string][]string, len(fileNames)) // files of the same flag are kept in order.
+	for _, name := range fileNames {
+		flagName := u.flagName(filepath.Join(dirPath, name))
+		if _, found := byFlag[flagName]; !found {
+			flagNames = append(flagNames, flagName)
+		}
+		byFlag[flagName] = append(byFlag[flagName], name)
+	}
+	ordered := make([]string, 0, len(fileNames))
+	for _, flagName := range dflag.ApplyOrder(flagNames, func(name string) *flag.Flag {
+		_, _, f := u.lookup(name)
+		return f
+	}) {
+		ordered = append(ordered, byFlag[flagName]...)
+	}
+	return ordered
+}
+
+// WithRevertOnDelete makes removing a flag's file (or its key from the ConfigMap) reset the flag to its
+// default value instead of keeping the last value, making the directory the single source of truth.
+func (u *Updater) WithRevertOnDelete(revert bool) *Updater {
+	u.revertOnDelete = revert
+	return u
+}
+
+// revertRemoved resets the dynamic flags whose files were present during the previous full read
+// but aren't anymore (e.g. key removed from the ConfigMap, which swaps the whole directory).
+func (u *Updater) revertRemoved(names []string) {
+	current := make(map[string]bool, len(names))
+	for _, n := range names {
+		current[n] = true
+	}
+	u.filesMu.Lock()
+	previous := u.knownFiles
+	u.knownFiles = current
+	u.filesMu.Unlock()
+	for n := range previous {
+		if current[n] {
+			continue
+		}
+		if err := u.revertToDefault(filepath.Join(u.dirPath, n)); err != nil {
+			u.logger.Error("dflag: failed reverting flag", "flag", n, "error", err)
+			u.errors.Add(1)
+		}
+	}
+}
+
+func (u *Updater) revertToDefault(fullPath string) error {
+	flagName := u.flagName(fullPath)
+	oldValue := u.currentValue(flagName)
+	flagSet, name, f := u.lookup(flagName)
+	if f == nil || !dflag.IsFlagDynamic(f) {
+		return nil
+	}
+	u.logger.Info("dflag: reverting flag to its default value as its file was removed", "flag", flagName, "path", fullPath)
+	err := dflag.ResetFlag(flagSet, name)
+	u.metrics.recordFlagUpdate(flagName, err)
+	u.events.add(newEvent(fullPath, flagName, oldValue, u.currentValue(flagName), err))
+	return err
+}
+
+// Reload re-reads all the dynamic flags from the directory, e.g. as a manual
+// escape hatch when file events were missed. It is safe to call while the updater is
+// watching, the reload and the updates from file events are applied one at a time.
+func (u *Updater) Reload() error {
+	u.logger.Info("dflag: reloading flags", "dir", u.dirPath)
+	u.applyMu.Lock()
+	defer u.applyMu.Unlock()
+	return u.readAll(context.Background(), true /* dynamicOnly */)
+}
+
+// ReloadOnSignal sets up a go routine calling Reload() each time one of the signals
+// (SIGHUP if none is passed) is received, following the classic unix daemon convention.
+// It stops when the updater's context (at the time of the call) is done or when the returned function is called.
+// On platforms without signals (js) and no signals passed, it does nothing.
+func (u *Updater) ReloadOnSignal(signals ...os.Signal) (stop func()) {
+	if len(signals) == 0 {
+		signals = defaultReloadSignals
+	}
+	if len(signals) == 0 {
+		return func() {}
+	}
+	ctx := u.ctx
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, signals...)
+	stopChan := make(chan struct{})
+	go func() {
+		defer signal.Stop(sigChan)
+		for {
+			select {
+			case sig := <-sigChan:
+				u.logger.Info("dflag: got signal", "signal", sig.String())
+				if err := u.Reload(); err != nil {
+					u.logger.Error("dflag: directory reload yielded errors", "dir", u.dirPath, "error", err)
+				}
+			case <-stopChan:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return func() { close(stopChan) }
+}
+
 // Return the warnings count.
 func (u *Updater) Warnings() int {
 	return int(u.warnings.Load())
@@ -155,71 +568,301 @@ func (u *Updater) Errors() int {
 	return int(u.errors.Load())
 }
 
-func (u *Updater) readFlagFile(fullPath string, dynamicOnly bool) error {
-	flagName := path.Base(fullPath)
-	flag := u.flagSet.Lookup(flagName)
+// WatchErrors returns the count of errors received from the underlying file watcher.
+func (u *Updater) WatchErrors() int {
+	return int(u.watchErrors.Load())
+}
+
+// Rewatches returns how many times the watches were successfully re-established after an error.
+func (u *Updater) Rewatches() int {
+	return int(u.rewatches.Load())
+}
+
+func (u *Updater) readFlagFile(ctx context.Context, fullPath string, dynamicOnly bool) error {
+	flagName := u.flagName(fullPath)
+	oldValue := u.currentValue(flagName)
+	newValue, err := u.setFlagFromFile(ctx, fullPath, dynamicOnly)
+	if errors.Is(err, os.ErrNotExist) && u.revertOnDelete && !u.dryRun {
+		return u.revertToDefault(fullPath)
+	}
+	if !errors.Is(err, ErrFlagNotFound) && !errors.Is(err, ErrFlagNotDynamic) {
+		u.metrics.recordFlagUpdate(flagName, err)
+		u.events.add(newEvent(fullPath, flagName, oldValue, newValue, err))
+	}
+	return err
+}
+
+// currentValue returns the current value of the flag for the events log (or "" if not found).
+func (u *Updater) currentValue(flagName string) string {
+	_, _, flag := u.lookup(flagName)
+	if flag == nil {
+		return ""
+	}
+	if v := dflag.IsBinary(flag); v != nil {
+		return binaryDescription(v.Get())
+	}
+	return flag.Value.String()
+}
+
+func binaryDescription(content []byte) string {
+	return fmt.Sprintf("(binary, %d bytes)", len(content))
+}
+
+// setFlagFromFile returns the new value (or its description for binary flags) it attempted to set.
+func (u *Updater) setFlagFromFile(ctx context.Context, fullPath string, dynamicOnly bool) (string, error) {
+	flagName := u.flagName(fullPath)
+	if u.emit != nil {
+		return u.emitFromFile(ctx, flagName, fullPath)
+	}
+	flagSet, name, flag := u.lookup(flagName)
 	if flag == nil {
-		return errFlagNotFound
+		return "", ErrFlagNotFound
 	}
 	if dynamicOnly && !dflag.IsFlagDynamic(flag) {
-		return errFlagNotDynamic
+		return "", ErrFlagNotDynamic
 	}
-	content, err := os.ReadFile(fullPath)
+	content, err := u.readFile(fullPath)
 	if err != nil {
-		return err
+		return "", err
 	}
-	if v := dflag.IsBinary(flag); v != nil {
-		log.Infof("Updating binary %q to new blob (len %d)", flagName, len(content))
-		err = v.SetV(content)
-		if err != nil {
-			return err
+	v := dflag.IsBinary(flag)
+	if err = u.checkContent(v != nil, content); err != nil {
+		return "", err
+	}
+	if content, err = u.transform(flagName, v != nil, content); err != nil {
+		return "", err
+	}
+	desc := dflag.Redact(flag, string(content))
+	if v != nil {
+		desc = binaryDescription(content)
+	}
+	if ctx.Err() != nil {
+		return desc, ctx.Err() // e.g. initial read timed out while reading the file.
+	}
+	if !u.dryRun {
+		return desc, source.SetFlagWithLogger(u.logger, flagSet, name, content, dynamicOnly)
+	}
+	if v != nil {
+		return desc, v.ValidateV(content)
+	}
+	err = dflag.ValidateFlag(flag, string(content))
+	if errors.Is(err, dflag.ErrNotValidatable) {
+		u.logger.Warn("dflag: can't validate flag value", "flag", flagName, "value", desc)
+		return desc, nil
+	}
+	return desc, err
+}
+
+// SetFlag sets the named flag from raw content the same way the Updater does for files.
+//
+// Deprecated: use source.SetFlag.
+func SetFlag(flagSet *flag.FlagSet, name string, content []byte, dynamicOnly bool) error {
+	return source.SetFlag(flagSet, name, content, dynamicOnly)
+}
+
+// SetFlags sets all the flags from the name to content map using SetFlag (in name order).
+//
+// Deprecated: use source.SetFlags.
+func SetFlags(flagSet *flag.FlagSet, values map[string][]byte, dynamicOnly bool) error {
+	return source.SetFlags(flagSet, values, dynamicOnly)
+}
+
+// Ready returns true when the initial read of the directory succeeded and the
+// watcher go routine is running. Use it (or ReadyHandler) to gate traffic on valid configuration.
+func (u *Updater) Ready() bool {
+	return u.initialized.Load() && u.watching.Load()
+}
+
+// ReadyHandler returns an http.Handler suitable for Kubernetes readiness/health probes:
+// it replies 200 when Ready() and 503 with the reason otherwise.
+func (u *Updater) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, _ *http.Request) {
+		resp.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+		switch {
+		case !u.initialized.Load():
+			resp.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = resp.Write([]byte("initial config read not successful\n"))
+		case !u.watching.Load():
+			resp.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = resp.Write([]byte("config watcher not running\n"))
+		default:
+			_, _ = resp.Write([]byte("ok\n"))
+		}
+	})
+}
+
+// pendingWork accumulates what needs to be re-read as a result of events until the debounce window expires.
+type pendingWork struct {
+	all   bool     // whole directory re-read.
+	files []string // individual files, in event order, without duplicates.
+	seen  map[string]bool
+}
+
+func (p *pendingWork) addFile(name string) {
+	if p.seen == nil {
+		p.seen = make(map[string]bool)
+	}
+	if p.seen[name] {
+		return
+	}
+	p.seen[name] = true
+	p.files = append(p.files, name)
+}
+
+func (p *pendingWork) empty() bool {
+	return !p.all && len(p.files) == 0
+}
+
+// process applies the pending work and resets it.
+func (u *Updater) process(p *pendingWork) {
+	u.applyMu.Lock()
+	defer u.applyMu.Unlock()
+	if p.all {
+		u.logger.Info("dflag: re-reading flags after ConfigMap update", "dir", u.dirPath)
+		if err := u.readAll(context.Background(), true /* dynamicOnly */); err != nil {
+			u.logger.Error("dflag: directory reload yielded errors", "dir", u.dirPath, "error", err)
+		}
+	} else {
+		for _, fileName := range p.files {
+			if err := u.readFlagFile(context.Background(), u.effectivePath(fileName), true); err != nil {
+				u.logger.Error("dflag: failed setting flag", "flag", u.flagName(fileName), "error", err)
+				u.errors.Add(1)
+			}
+		}
+	}
+	*p = pendingWork{}
+}
+
+// handleEvent records in p the work resulting from the event and returns true if the
+// watch on the directory itself was lost.
+func (u *Updater) handleEvent(event fsEvent, p *pendingWork) bool {
+	u.logger.Debug("ConfigMap got fsnotify event", "event", event.String())
+	for _, dirPath := range u.layers() {
+		if lost, done := u.handleDirEvent(dirPath, event, p); done {
+			return lost
 		}
-		return nil
 	}
-	str := string(content)
-	log.Infof("Updating %q to %q", flagName, str)
-	// do not call flag.Value.Set, instead go through flagSet.Set to change "changed" state.
-	return u.flagSet.Set(flagName, str)
+	return false
 }
 
-func (u *Updater) watchForUpdates() {
-	log.Infof("Background thread watching %s now running", u.dirPath)
+// handleDirEvent handles the event if it's for the dirPath layer, returning done true in that case
+// and lost true if the watch on the directory itself was lost.
+func (u *Updater) handleDirEvent(dirPath string, event fsEvent, p *pendingWork) (lost, done bool) {
+	if event.Name == dirPath || event.Name == filepath.Join(dirPath, k8sDataSymlink) {
+		// case of the whole directory being re-symlinked
+		switch event.Op {
+		case opCreate:
+			if err := u.watcher.Add(dirPath); err != nil { // add the dir itself.
+				u.logger.Error("dflag: unable to add config dir to watch", "dir", dirPath, "error", err)
+			}
+			p.all = true
+		case opRemove, opRename:
+			// the watch on the directory itself is gone, try to get it back when it reappears.
+			return event.Name == dirPath, true
+		case opChmod, opWrite:
+		}
+		return false, true
+	}
+	if _, ok := relPath(dirPath, event.Name); !ok {
+		return false, false
+	}
+	if !isK8sInternalDirectory(event.Name) {
+		u.logger.Debug("ConfigMap got prefix event", "event", event.String())
+		switch event.Op {
+		case opCreate, opWrite, opRename, opRemove:
+			switch {
+			case strings.HasPrefix(filepath.Base(event.Name), "."):
+				// dot files (e.g. Persist's temporary files) are skipped like in readTree.
+			case u.ignored(filepath.Base(event.Name)):
+			case event.Op == opCreate && u.isNestedDir(event.Name):
+				p.all = true // new sub directory: re-read everything, which also watches it.
+			default:
+				p.addFile(event.Name)
+			}
+		case opChmod:
+		}
+	}
+	return false, true
+}
+
+func (u *Updater) watchForUpdates(ctx context.Context) {
+	u.logger.Info("dflag: background thread watching now running", "dir", u.dirPath)
+	defer close(u.exited)
+	defer u.watching.Store(false)
+	// retry is non nil when the watches need to be re-established (after an error or the directory removal).
+	var retry <-chan time.Time
+	backoff := minRewatchBackoff
+	// flush is non nil while events are being coalesced (debounce window).
+	var flush <-chan time.Time
+	pending := pendingWork{}
 	for {
 		select {
+		case err, ok := <-u.watcher.Errors:
+			if !ok {
+				u.logger.Error("dflag: watcher closed", "dir", u.dirPath)
+				return
+			}
+			u.logger.Error("dflag: watcher error", "dir", u.dirPath, "error", err)
+			u.watchErrors.Add(1)
+			if retry == nil {
+				retry = time.After(backoff)
+			}
+		case <-retry:
+			if err := u.addWatches(); err != nil {
+				backoff = nextBackoff(backoff)
+				u.logger.Warn("dflag: re-watch failed", "error", err, "retry_in", backoff.String())
+				retry = time.After(backoff)
+				continue
+			}
+			u.logger.Info("dflag: re-established watches, re-reading flags", "parent", u.parentPath, "dir", u.dirPath)
+			u.rewatches.Add(1)
+			retry = nil
+			backoff = minRewatchBackoff
+			// events may have been missed while not watching.
+			pending.all = true
+			u.process(&pending)
 		case event := <-u.watcher.Events:
-			log.LogVf("ConfigMap got fsnotify %v ", event)
-			if event.Name == u.dirPath || event.Name == path.Join(u.dirPath, k8sDataSymlink) { //nolint:nestif
-				// case of the whole directory being re-symlinked
-				switch event.Op {
-				case fsnotify.Create:
-					if err := u.watcher.Add(u.dirPath); err != nil { // add the dir itself.
-						log.Errf("unable to add config dir %v to watch: %v", u.dirPath, err)
-					}
-					log.Infof("dflag: Re-reading flags after ConfigMap update.")
-					if err := u.readAll( /* dynamicOnly */ true); err != nil {
-						log.Errf("dflag: directory reload yielded errors: %v", err.Error())
-					}
-				case fsnotify.Remove, fsnotify.Chmod, fsnotify.Rename, fsnotify.Write:
-				}
-			} else if strings.HasPrefix(event.Name, u.dirPath) && !isK8sInternalDirectory(event.Name) {
-				log.LogVf("ConfigMap got prefix %v", event)
-				switch event.Op {
-				case fsnotify.Create, fsnotify.Write, fsnotify.Rename, fsnotify.Remove:
-					flagName := path.Base(event.Name)
-					if err := u.readFlagFile(event.Name, true); err != nil {
-						log.Errf("dflag: failed setting flag %s: %v", flagName, err.Error())
-						u.errors.Add(1)
-					}
-				case fsnotify.Chmod:
-				}
+			if u.handleEvent(event, &pending) && retry == nil {
+				retry = time.After(backoff)
+			}
+			if pending.empty() {
+				continue
 			}
+			if u.debounce <= 0 {
+				u.process(&pending)
+			} else if flush == nil {
+				flush = time.After(u.debounce)
+			}
+		case <-flush:
+			flush = nil
+			u.process(&pending)
 		case <-u.done:
 			return
+		case <-ctx.Done():
+			u.logger.Info("dflag: context done, stopping watching", "dir", u.dirPath)
+			return
 		}
 	}
 }
 
+func nextBackoff(cur time.Duration) time.Duration {
+	next := 2 * cur
+	if next > maxRewatchBackoff {
+		return maxRewatchBackoff
+	}
+	return next
+}
+
+// relPath returns fullPath relative to the dirPath directory and true, or false if it's not inside it.
+func relPath(dirPath, fullPath string) (string, bool) {
+	prefix := dirPath + string(filepath.Separator)
+	if !strings.HasPrefix(fullPath, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(fullPath, prefix), true
+}
+
 func isK8sInternalDirectory(filePath string) bool {
-	basePath := path.Base(filePath)
+	basePath := filepath.Base(filePath)
 	return strings.HasPrefix(basePath, k8sInternalsPrefix)
 }
@@ -0,0 +1,103 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestNextBackoff(t *testing.T) {
+	assert.Equal(t, 2*minRewatchBackoff, nextBackoff(minRewatchBackoff))
+	assert.Equal(t, maxRewatchBackoff, nextBackoff(maxRewatchBackoff-time.Millisecond))
+	assert.Equal(t, maxRewatchBackoff, nextBackoff(maxRewatchBackoff))
+}
+
+func TestWatcherErrorRewatch(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("rewatch_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	u, err := New(fs, dir)
+	assert.NoError(t, err)
+	assert.NoError(t, u.Initialize())
+	assert.NoError(t, u.Start())
+	defer u.Stop()
+	u.watcher.Errors <- errors.New("simulated overflow")
+	// change made while the watch is (supposedly) broken is picked up by the full re-read.
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("42"), 0o644))
+	for i := 0; i < 50 && u.Rewatches() == 0; i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.Equal(t, 1, u.WatchErrors(), "watch errors count")
+	assert.Equal(t, 1, u.Rewatches(), "rewatches count")
+	assert.Equal(t, int64(42), dynInt.Get())
+}
+
+func TestRelPath(t *testing.T) {
+	dir := filepath.Join("some", "config")
+	rel, ok := relPath(dir, filepath.Join(dir, "redis", "timeout"))
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join("redis", "timeout"), rel)
+	_, ok = relPath(dir, filepath.Join("some", "config-prod", "x"))
+	assert.False(t, ok, "sibling with the same prefix isn't inside")
+	u := &Updater{dirPath: dir, nestedSep: "."}
+	assert.Equal(t, "redis.timeout", u.flagName(filepath.Join(dir, "redis", "timeout")))
+	assert.Equal(t, "x", u.flagName(filepath.Join("elsewhere", "x")))
+}
+
+func TestPendingWork(t *testing.T) {
+	p := pendingWork{}
+	assert.True(t, p.empty())
+	p.addFile("b")
+	p.addFile("a")
+	p.addFile("b")
+	assert.False(t, p.empty())
+	assert.Equal(t, []string{"b", "a"}, p.files)
+}
+
+func TestDebounce(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("debounce_test", flag.ContinueOnError)
+	var count atomic.Int32
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing").WithSyncNotifier(func(_, _ int64) {
+		count.Add(1)
+	})
+	u, err := New(fs, dir)
+	assert.NoError(t, err)
+	u.WithDebounce(300 * time.Millisecond)
+	assert.NoError(t, u.Initialize())
+	assert.NoError(t, u.Start())
+	defer u.Stop()
+	fName := filepath.Join(dir, "some_dynint")
+	for i := 2; i <= 10; i++ {
+		assert.NoError(t, os.WriteFile(fName, []byte(fmt.Sprint(i)), 0o644))
+	}
+	time.Sleep(600 * time.Millisecond)
+	assert.Equal(t, int64(10), dynInt.Get())
+	assert.Equal(t, int32(1), count.Load(), "burst of writes should result in a single set")
+}
+
+func TestEventsRing(t *testing.T) {
+	r := eventsRing{size: 3}
+	for i := 1; i <= 5; i++ {
+		r.add(Event{Flag: fmt.Sprint(i)})
+	}
+	names := []string{}
+	for _, e := range r.all() {
+		names = append(names, e.Flag)
+	}
+	assert.Equal(t, []string{"3", "4", "5"}, names)
+	r = eventsRing{size: 0}
+	r.add(Event{Flag: "x"})
+	assert.Equal(t, 0, len(r.all()))
+}
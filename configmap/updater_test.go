@@ -4,10 +4,12 @@
 package configmap_test
 
 import (
+	"context"
 	"flag"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
-	"path"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -16,6 +18,7 @@ import (
 	"fortio.org/assert"
 	"fortio.org/dflag"
 	"fortio.org/dflag/configmap"
+	"fortio.org/dflag/dflagtest"
 )
 
 const (
@@ -46,7 +49,7 @@ func (s *updaterTestSuite) SetupTest() {
 	s.dynInt = dflag.DynInt64(s.flagSet, "some_dynint", 1, "dynamic int for testing")
 	s.staticInt = s.flagSet.Int("some_int", 1, "static int for testing")
 
-	s.updater, err = configmap.New(s.flagSet, path.Join(s.tempDir, "testdata"))
+	s.updater, err = configmap.New(s.flagSet, filepath.Join(s.tempDir, "testdata"))
 	assert.NoError(s.T(), err, "creating a config map must not fail")
 }
 
@@ -71,8 +74,8 @@ func (s *updaterTestSuite) copyTestDataToDir() {
 
 func (s *updaterTestSuite) linkDataDirTo(newDataDir string) {
 	copyCmd := exec.Command("ln", "-s", "-n", "-f",
-		path.Join(s.tempDir, "testdata", newDataDir),
-		path.Join(s.tempDir, "testdata", "..data"))
+		filepath.Join(s.tempDir, "testdata", newDataDir),
+		filepath.Join(s.tempDir, "testdata", "..data"))
 	assert.NoError(s.T(), copyCmd.Run(), "relinking ..data in tempdir must not fail")
 }
 
@@ -82,7 +85,7 @@ func (s *updaterTestSuite) TestInitializeFailsOnBadFormedFlag() {
 }
 
 func (s *updaterTestSuite) TestSetupFunction() {
-	tmpU, err := configmap.Setup(s.flagSet, path.Join(s.tempDir, "testdata"))
+	tmpU, err := configmap.Setup(s.flagSet, filepath.Join(s.tempDir, "testdata"))
 	assert.NoError(s.T(), err, "setup for a config map must not fail")
 	assert.Error(s.T(), tmpU.Initialize(), "should error with already started")
 	assert.Error(s.T(), tmpU.Start(), "should error with already started")
@@ -105,6 +108,64 @@ func (s *updaterTestSuite) TestDynamicUpdatesPropagate() {
 		"some_dynint value should change to the value from secondGoodDir")
 }
 
+func (s *updaterTestSuite) TestReadiness() {
+	assert.False(s.T(), s.updater.Ready(), "should not be ready before initialize")
+	h := s.updater.ReadyHandler()
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(s.T(), http.StatusServiceUnavailable, resp.Code, "not ready status")
+	assert.NoError(s.T(), s.updater.Initialize(), "the updater initialize should not return errors on good flags")
+	assert.False(s.T(), s.updater.Ready(), "should not be ready before start")
+	assert.NoError(s.T(), s.updater.Start(), "updater start should not return an error")
+	assert.True(s.T(), s.updater.Ready(), "should be ready after initialize and start")
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(s.T(), http.StatusOK, resp.Code, "ready status")
+}
+
+func (s *updaterTestSuite) TestRestartAfterStop() {
+	assert.NoError(s.T(), s.updater.Initialize(), "the updater initialize should not return errors on good flags")
+	assert.NoError(s.T(), s.updater.Start(), "updater start should not return an error")
+	assert.NoError(s.T(), s.updater.Stop(), "stopping the watcher should succeed")
+	assert.Error(s.T(), s.updater.Stop(), "stopping twice should error")
+	assert.Error(s.T(), s.updater.Initialize(), "initialize is one-shot")
+	assert.False(s.T(), s.updater.Ready(), "should not be ready after stop")
+	// change while stopped is picked up on restart
+	s.linkDataDirTo(secondGoodDir)
+	assert.NoError(s.T(), s.updater.Start(), "restarting after stop should work")
+	assert.True(s.T(), s.updater.Ready(), "should be ready again after restart")
+	assert.EqualValues(s.T(), s.dynInt.Get(), int64(20002), "restart should re-read the flags")
+}
+
+func (s *updaterTestSuite) TestContextCancelStops() {
+	ctx, cancel := context.WithCancel(context.Background())
+	u, err := configmap.NewWithContext(ctx, s.flagSet, filepath.Join(s.tempDir, "testdata"))
+	assert.NoError(s.T(), err, "creating a config map must not fail")
+	defer u.Close()
+	assert.NoError(s.T(), u.Initialize(), "the updater initialize should not return errors on good flags")
+	assert.NoError(s.T(), u.Start(), "updater start should not return an error")
+	cancel()
+	eventually(s.T(), 1*time.Second,
+		assert.ObjectsAreEqualValues, false,
+		func() interface{} { return u.Ready() },
+		"updater should stop when the context is cancelled")
+	assert.Error(s.T(), u.Start(), "can't restart with a done context")
+	assert.Error(s.T(), u.Initialize(), "initialize is one-shot")
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	s.linkDataDirTo(secondGoodDir)
+	assert.NoError(s.T(), u.StartWithContext(ctx), "restart with a new context should work")
+	assert.True(s.T(), u.Ready(), "should be ready again after restart")
+	assert.EqualValues(s.T(), s.dynInt.Get(), int64(20002), "restart should re-read the flags")
+}
+
+func (s *updaterTestSuite) TestNotReadyOnBadInitialize() {
+	s.linkDataDirTo(badStaticDir)
+	assert.Error(s.T(), s.updater.Initialize(), "the updater initialize should return error on bad flags")
+	assert.NoError(s.T(), s.updater.Start(), "updater start should not return an error")
+	assert.False(s.T(), s.updater.Ready(), "should not be ready after a failed initialize")
+}
+
 func TestUpdaterSuite(t *testing.T) {
 	assert.Run(t, &updaterTestSuite{})
 }
@@ -127,3 +188,52 @@ func eventually(t *testing.T, duration time.Duration,
 	}
 	t.Fatalf(msgFmt, msgArgs...)
 }
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("validate_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing").WithValidator(dflag.ValidateDynInt64Range(0, 100))
+	staticInt := fs.Int("some_int", 1, "static int for testing")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("42"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_int"), []byte("43"), 0o644))
+	assert.NoError(t, configmap.Validate(fs, dir))
+	assert.Equal(t, int64(1), dynInt.Get(), "validate must not change values")
+	assert.Equal(t, 1, *staticInt, "validate must not change values")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("420"), 0o644))
+	err := configmap.Validate(fs, dir)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "some_dynint")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("42"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_unknown"), []byte("42"), 0o644))
+	err = configmap.Validate(fs, dir)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "1 file(s) for unknown flags")
+}
+
+func TestWithLogger(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("logger_test", flag.ContinueOnError)
+	dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("42"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_unknown"), []byte("42"), 0o644))
+	logger := &dflagtest.Logger{}
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.NoError(t, u.WithLogger(logger).Initialize())
+	warnings := logger.Entries("warn")
+	assert.Equal(t, 1, len(warnings))
+	assert.Equal(t, "config map for unknown flag", warnings[0].Msg)
+	assert.Equal(t, "some_unknown", warnings[0].Attr("flag"))
+	assert.Equal(t, 2, len(logger.Entries("debug")), "checking each flag")
+	infos := logger.Entries("info")
+	assert.Equal(t, 1, len(infos))
+	assert.Equal(t, "dflag: updating flag", infos[0].Msg, "the flag updates are logged to the logger too")
+	assert.Equal(t, "some_dynint", infos[0].Attr("flag"))
+	assert.Equal(t, "42", infos[0].Attr("value"))
+	assert.NoError(t, u.Reload())
+	infos = logger.Entries("info")
+	assert.Equal(t, 3, len(infos))
+	assert.Equal(t, dir, infos[1].Attr("dir"))
+	assert.Equal(t, "some_dynint", infos[2].Attr("flag"))
+}
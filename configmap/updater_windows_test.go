@@ -0,0 +1,50 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestWindowsPaths(t *testing.T) {
+	u := &Updater{dirPath: filepath.Clean(`C:\config\`), overlays: []string{`C:\config-prod`}, nestedSep: "."}
+	assert.Equal(t, `C:\config`, u.dirPath)
+	assert.Equal(t, "redis.timeout", u.flagName(`C:\config\redis\timeout`))
+	assert.Equal(t, "redis.timeout", u.flagName(`C:\config-prod\redis\timeout`))
+	_, ok := relPath(u.dirPath, `D:\config\x`)
+	assert.False(t, ok, "other drive")
+	assert.Equal(t, `C:\config`, filepath.Clean(filepath.Join(`C:\config\sub`, "..")))
+}
+
+func TestWindowsWatcher(t *testing.T) {
+	dir := t.TempDir() // has a drive letter and backslashes.
+	fs := flag.NewFlagSet("windows_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	redisTimeout := dflag.DynDuration(fs, "redis.timeout", time.Second, "dynamic duration for testing")
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "redis"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("42"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "redis", "timeout"), []byte("5s"), 0o644))
+	u, err := New(fs, dir+`\`)
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.NoError(t, u.WithNestedDirs(".").Initialize())
+	assert.NoError(t, u.Start())
+	assert.Equal(t, int64(42), dynInt.Get())
+	assert.Equal(t, 5*time.Second, redisTimeout.Get())
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("43"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "redis", "timeout"), []byte("7s"), 0o644))
+	for i := 0; i < 50 && (dynInt.Get() != 43 || redisTimeout.Get() != 7*time.Second); i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.Equal(t, int64(43), dynInt.Get())
+	assert.Equal(t, 7*time.Second, redisTimeout.Get())
+	assert.Equal(t, 0, u.Errors())
+}
@@ -0,0 +1,26 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+//go:build !no_fsnotify
+
+package configmap
+
+import "github.com/fsnotify/fsnotify"
+
+// The file watcher is fsnotify unless the no_fsnotify build tag is set, see watcher_poll.go.
+type (
+	fsWatcher = fsnotify.Watcher
+	fsEvent   = fsnotify.Event
+)
+
+const (
+	opCreate = fsnotify.Create
+	opWrite  = fsnotify.Write
+	opRemove = fsnotify.Remove
+	opRename = fsnotify.Rename
+	opChmod  = fsnotify.Chmod
+)
+
+func newWatcher() (*fsWatcher, error) {
+	return fsnotify.NewWatcher()
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+//go:build no_fsnotify
+
+package configmap
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// pollInterval is how often the watched directories are scanned for changes.
+var pollInterval = time.Second
+
+// fsOp mirrors fsnotify's Op for the polling watcher.
+type fsOp uint32
+
+const (
+	opCreate fsOp = 1 << iota
+	opWrite
+	opRemove
+	opRename
+	opChmod
+)
+
+func (op fsOp) String() string {
+	switch op {
+	case opCreate:
+		return "CREATE"
+	case opWrite:
+		return "WRITE"
+	case opRemove:
+		return "REMOVE"
+	case opRename:
+		return "RENAME"
+	case opChmod:
+		return "CHMOD"
+	}
+	return fmt.Sprintf("fsOp(%d)", uint32(op))
+}
+
+// fsEvent mirrors fsnotify's Event for the polling watcher.
+type fsEvent struct {
+	Name string
+	Op   fsOp
+}
+
+func (e fsEvent) String() string {
+	return fmt.Sprintf("%-13s %q", e.Op.String(), e.Name)
+}
+
+// entryState is what is compared between scans to detect changes of a directory entry.
+type entryState struct {
+	modTime time.Time
+	size    int64
+	mode    os.FileMode
+	target  string // for symlinks (e.g. Kubernetes' ..data swaps).
+}
+
+// fsWatcher is a polling replacement for fsnotify's Watcher, used with the no_fsnotify build tag
+// (e.g. for WASM or minimal builds). Like fsnotify, only the direct entries of the added directories
+// are watched.
+type fsWatcher struct {
+	Events    chan fsEvent
+	Errors    chan error
+	mu        sync.Mutex
+	dirs      map[string]map[string]entryState
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newWatcher() (*fsWatcher, error) {
+	w := &fsWatcher{
+		Events: make(chan fsEvent),
+		Errors: make(chan error),
+		dirs:   make(map[string]map[string]entryState),
+		done:   make(chan struct{}),
+	}
+	go w.loop()
+	return w, nil
+}
+
+// Add starts watching the directory.
+func (w *fsWatcher) Add(name string) error {
+	entries, err := scanDir(name)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.dirs[filepath.Clean(name)] = entries
+	return nil
+}
+
+// Remove stops watching the directory.
+func (w *fsWatcher) Remove(name string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	name = filepath.Clean(name)
+	if _, ok := w.dirs[name]; !ok {
+		return errors.New("can't remove non-existent watch for: " + name)
+	}
+	delete(w.dirs, name)
+	return nil
+}
+
+// Close stops the polling.
+func (w *fsWatcher) Close() error {
+	w.closeOnce.Do(func() { close(w.done) })
+	return nil
+}
+
+func scanDir(dirPath string) (map[string]entryState, error) {
+	files, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]entryState, len(files))
+	for _, f := range files {
+		info, err := f.Info()
+		if err != nil {
+			continue // removed since ReadDir.
+		}
+		st := entryState{modTime: info.ModTime(), size: info.Size(), mode: info.Mode()}
+		if info.Mode()&os.ModeSymlink != 0 {
+			st.target, _ = os.Readlink(filepath.Join(dirPath, f.Name()))
+		}
+		entries[f.Name()] = st
+	}
+	return entries, nil
+}
+
+func (w *fsWatcher) loop() {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+		for _, e := range w.scan() {
+			select {
+			case w.Events <- e:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+// scan returns the events since the previous scan, in a stable order.
+func (w *fsWatcher) scan() []fsEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var events []fsEvent
+	for _, dirPath := range sortedKeys(w.dirs) {
+		previous := w.dirs[dirPath]
+		current, err := scanDir(dirPath)
+		if err != nil {
+			// the watched directory itself is gone.
+			delete(w.dirs, dirPath)
+			events = append(events, fsEvent{Name: dirPath, Op: opRemove})
+			continue
+		}
+		w.dirs[dirPath] = current
+		for _, name := range sortedKeys(previous) {
+			if _, ok := current[name]; !ok {
+				events = append(events, fsEvent{Name: filepath.Join(dirPath, name), Op: opRemove})
+			}
+		}
+		for _, name := range sortedKeys(current) {
+			old, ok := previous[name]
+			cur := current[name]
+			switch {
+			case !ok || old.target != cur.target:
+				events = append(events, fsEvent{Name: filepath.Join(dirPath, name), Op: opCreate})
+			case old.modTime != cur.modTime || old.size != cur.size:
+				events = append(events, fsEvent{Name: filepath.Join(dirPath, name), Op: opWrite})
+			case old.mode != cur.mode:
+				events = append(events, fsEvent{Name: filepath.Join(dirPath, name), Op: opChmod})
+			}
+		}
+	}
+	return events
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+//go:build no_fsnotify
+
+package configmap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+)
+
+func init() {
+	pollInterval = 20 * time.Millisecond // for the whole package's tests to run fast with polling.
+}
+
+func nextEvent(t *testing.T, w *fsWatcher) fsEvent {
+	select {
+	case e := <-w.Events:
+		return e
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no event")
+	}
+	return fsEvent{}
+}
+
+func TestPollWatcher(t *testing.T) {
+	dir := t.TempDir()
+	w, err := newWatcher()
+	assert.NoError(t, err)
+	defer w.Close()
+	assert.Error(t, w.Add(filepath.Join(dir, "nope")))
+	assert.NoError(t, w.Add(dir))
+	f := filepath.Join(dir, "some_flag")
+	assert.NoError(t, os.WriteFile(f, []byte("1"), 0o644))
+	assert.Equal(t, fsEvent{Name: f, Op: opCreate}, nextEvent(t, w))
+	assert.NoError(t, os.WriteFile(f, []byte("22"), 0o644))
+	assert.Equal(t, fsEvent{Name: f, Op: opWrite}, nextEvent(t, w))
+	// Kubernetes style ..data symlink swap.
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "..v1"), 0o755))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "..v2"), 0o755))
+	assert.NoError(t, os.Symlink("..v1", filepath.Join(dir, "..data")))
+	e1, e2, e3 := nextEvent(t, w), nextEvent(t, w), nextEvent(t, w)
+	assert.Equal(t, fsEvent{Name: filepath.Join(dir, "..data"), Op: opCreate}, e1)
+	assert.Equal(t, opCreate, e2.Op)
+	assert.Equal(t, opCreate, e3.Op)
+	assert.NoError(t, os.Symlink("..v2", filepath.Join(dir, "..data_tmp")))
+	assert.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+	assert.Equal(t, fsEvent{Name: filepath.Join(dir, "..data"), Op: opCreate}, nextEvent(t, w))
+	assert.NoError(t, os.Remove(f))
+	assert.Equal(t, fsEvent{Name: f, Op: opRemove}, nextEvent(t, w))
+	assert.NoError(t, w.Remove(dir))
+	assert.Error(t, w.Remove(dir))
+	assert.Equal(t, `CREATE        "x"`, fsEvent{Name: "x", Op: opCreate}.String())
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"fortio.org/dflag"
+)
+
+// WithWriteBack enables Persist() to write flag values changed at runtime (e.g. through the endpoint's
+// set hook) into dirPath, so a container restart doesn't lose runtime tuning. dirPath is a separate overrides
+// directory, created by Initialize() if needed, which is the top layer (above the overlays): it's watched and
+// its files take precedence over the ConfigMap's until removed. Initialize() fails if dirPath is empty or is
+// (inside) the watched directory or an overlay. Must be called before Initialize().
+func (u *Updater) WithWriteBack(dirPath string) *Updater {
+	u.writeBackErr = nil
+	if dirPath == "" {
+		u.writeBackDir = ""
+		u.writeBackErr = errors.New("dflag: write-back directory can't be empty")
+		return u
+	}
+	u.writeBackDir = filepath.Clean(dirPath)
+	return u
+}
+
+// checkWriteBack returns an error if the write-back directory is invalid and creates it if needed.
+func (u *Updater) checkWriteBack() error {
+	if u.writeBackErr != nil || u.writeBackDir == "" {
+		return u.writeBackErr
+	}
+	for _, layer := range u.configLayers() {
+		if _, inside := relPath(layer, u.writeBackDir); inside || layer == u.writeBackDir {
+			return fmt.Errorf("dflag: write-back directory %v can't be inside the watched %v", u.writeBackDir, layer)
+		}
+	}
+	if err := os.MkdirAll(u.writeBackDir, 0o755); err != nil {
+		return fmt.Errorf("dflag: write-back directory: %w", err)
+	}
+	return nil
+}
+
+// Persist writes the current value of the flag into the write-back directory.
+// Meant to be used as the endpoint's set hook, e.g.
+//
+//	endpoint.NewFlagsEndpoint(flagSet, setURL).WithSetHook(func(name string) { _ = u.Persist(name) })
+func (u *Updater) Persist(flagName string) error {
+	if u.writeBackDir == "" && u.writeBackErr == nil {
+		return errors.New("dflag: write-back is not enabled")
+	}
+	if err := u.checkWriteBack(); err != nil {
+		return err
+	}
+	_, _, f := u.lookup(flagName)
+	if f == nil {
+		return ErrFlagNotFound
+	}
+	if dflag.IsSensitive(f) {
+		return fmt.Errorf("dflag: not persisting the sensitive flag %q (in the 0644 write-back files)", flagName)
+	}
+	var content []byte
+	if v := dflag.IsBinary(f); v != nil {
+		content = v.Get()
+	} else {
+		content = []byte(f.Value.String())
+	}
+	// Write to a dot (thus ignored) temporary file and rename so readers never see partial content.
+	tmpName := filepath.Join(u.writeBackDir, "."+flagName+".tmp")
+	if err := os.WriteFile(tmpName, content, 0o644); err != nil { //nolint:gosec // config isn't secret
+		return fmt.Errorf("dflag: write-back of %q: %w", flagName, err)
+	}
+	if err := os.Rename(tmpName, filepath.Join(u.writeBackDir, flagName)); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("dflag: write-back of %q: %w", flagName, err)
+	}
+	u.logger.Info("dflag: persisted flag", "flag", flagName, "dir", u.writeBackDir)
+	return nil
+}
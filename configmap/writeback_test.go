@@ -0,0 +1,119 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package configmap_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/configmap"
+	"fortio.org/dflag/dflagtest"
+)
+
+func TestWriteBackOverridesDir(t *testing.T) {
+	dir := t.TempDir()
+	overrides := filepath.Join(t.TempDir(), "overrides")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("42"), 0o644))
+	fs := flag.NewFlagSet("writeback_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	binF := dflag.Dyn(fs, "some_binary", []byte{1}, "dynamic binary for testing")
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	assert.Error(t, u.Persist("some_dynint"), "write-back not enabled")
+	u.WithWriteBack(overrides)
+	assert.NoError(t, u.Initialize(), "missing overrides dir is created")
+	assert.Equal(t, int64(42), dynInt.Get())
+	_, err = os.Stat(overrides)
+	assert.NoError(t, err)
+	// runtime change, persisted:
+	assert.NoError(t, fs.Set("some_dynint", "43"))
+	assert.NoError(t, u.Persist("some_dynint"))
+	assert.NoError(t, binF.SetV([]byte{0, 1, 2}))
+	assert.NoError(t, u.Persist("some_binary"))
+	assert.Error(t, u.Persist("no_such_flag"))
+	content, err := os.ReadFile(filepath.Join(overrides, "some_binary"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 1, 2}, content)
+	u.Close()
+	// "restart":
+	assert.NoError(t, fs.Set("some_dynint", "1"))
+	assert.NoError(t, binF.SetV([]byte{1}))
+	u, err = configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.NoError(t, u.WithWriteBack(overrides).Initialize())
+	assert.Equal(t, int64(43), dynInt.Get(), "override should win over the base directory")
+	assert.Equal(t, []byte{0, 1, 2}, binF.Get())
+}
+
+func TestWriteBackInvalidDir(t *testing.T) {
+	dir := t.TempDir()
+	fs := flag.NewFlagSet("writeback_test", flag.ContinueOnError)
+	dflag.DynString(fs, "some_dynstr", "a", "dynamic string for testing")
+	for _, wb := range []string{"", dir, filepath.Join(dir, "overrides")} {
+		u, err := configmap.New(fs, dir)
+		assert.NoError(t, err)
+		u.WithWriteBack(wb)
+		assert.Error(t, u.Persist("some_dynstr"), "write-back dir "+wb)
+		assert.Error(t, u.Initialize(), "write-back dir "+wb)
+		u.Close()
+	}
+	_, err := os.Stat(filepath.Join(dir, "some_dynstr"))
+	assert.True(t, os.IsNotExist(err), "nothing should be written in the watched directory")
+}
+
+func TestWriteBackWatched(t *testing.T) {
+	dir := t.TempDir()
+	overrides := filepath.Join(t.TempDir(), "overrides")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("42"), 0o644))
+	fs := flag.NewFlagSet("writeback_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	dynStr := dflag.DynString(fs, "some_dynstr", "a", "dynamic string for testing")
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	assert.NoError(t, u.WithWriteBack(overrides).Initialize())
+	assert.NoError(t, u.Start())
+	assert.NoError(t, fs.Set("some_dynint", "43"))
+	assert.NoError(t, u.Persist("some_dynint"))
+	// ConfigMap update of the persisted flag doesn't undo the runtime change.
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynint"), []byte("50"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some_dynstr"), []byte("b"), 0o644))
+	eventually(t, 1*time.Second, assert.ObjectsAreEqualValues, "b",
+		func() interface{} { return dynStr.Get() }, "some_dynstr should change")
+	assert.Equal(t, int64(43), dynInt.Get(), "persisted value should win")
+	// removing the override goes back to the ConfigMap value.
+	assert.NoError(t, os.Remove(filepath.Join(overrides, "some_dynint")))
+	eventually(t, 1*time.Second, assert.ObjectsAreEqualValues, int64(50),
+		func() interface{} { return dynInt.Get() }, "some_dynint should fall back to the base value")
+	assert.Equal(t, 0, u.Errors())
+}
+
+func TestSensitiveFlags(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte("s3cr3t"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "pin"), []byte("12x4"), 0o644))
+	fs := flag.NewFlagSet("writeback_test", flag.ContinueOnError)
+	token := dflag.DynString(fs, "token", "", "api token").Sensitive()
+	dflag.DynInt64(fs, "pin", 1, "pin").Sensitive()
+	logger := &dflagtest.Logger{}
+	u, err := configmap.New(fs, dir)
+	assert.NoError(t, err)
+	defer u.Close()
+	u.WithLogger(logger).WithWriteBack(t.TempDir()).WithInitPolicy(configmap.InitApplyValid)
+	assert.NoError(t, u.Initialize())
+	assert.Equal(t, "s3cr3t", token.Get())
+	assert.Error(t, u.Persist("token"), "sensitive flags aren't written back")
+	out := fmt.Sprintf("%v %v", logger.Entries(""), u.Events())
+	for _, secret := range []string{"s3cr3t", "12x4"} {
+		assert.False(t, strings.Contains(out, secret), secret+" leaked in "+out)
+	}
+}
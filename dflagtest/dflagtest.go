@@ -0,0 +1,293 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Package dflagtest has helpers to test how applications react to flag changes without sleeps
+// nor leaking values between tests: setting flags restored at the end of the test, waiting for
+// changes and notifiers, a FakeSource whose updates the test pushes, and a Logger and Metrics recording
+// what the library reports.
+package dflagtest
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"sync"
+	"testing"
+	"time"
+
+	"fortio.org/dflag"
+	"fortio.org/dflag/source"
+)
+
+// Timeout is how long the Wait helpers wait before failing the test.
+var Timeout = 5 * time.Second
+
+// Set sets the named flag of flagSet for the duration of the test: its previous value is set
+// back during the test's cleanup. The test fails if either set does.
+func Set(t testing.TB, flagSet *flag.FlagSet, name, value string) {
+	t.Helper()
+	f := flagSet.Lookup(name)
+	if f == nil {
+		t.Fatalf("flag %q not found", name)
+		return
+	}
+	prev := f.Value.String()
+	if err := flagSet.Set(name, value); err != nil {
+		t.Fatalf("setting flag %q to %q: %v", name, value, err)
+	}
+	t.Cleanup(func() {
+		if err := flagSet.Set(name, prev); err != nil {
+			t.Errorf("restoring flag %q to %q: %v", name, prev, err)
+		}
+	})
+}
+
+// SetV is Set for a dynamic value (e.g. one not bound to a flag yet).
+func SetV[T any](t testing.TB, d *dflag.DynValue[T], value T) {
+	t.Helper()
+	prev := d.Get()
+	if err := d.SetV(value); err != nil {
+		t.Fatalf("setting dynamic value to %v: %v", value, err)
+	}
+	t.Cleanup(func() {
+		if err := d.SetV(prev); err != nil {
+			t.Errorf("restoring dynamic value to %v: %v", prev, err)
+		}
+	})
+}
+
+// WaitGeneration waits until the flag was successfully set at least generation times (see
+// dflag.DynamicFlagWatcher), e.g. by an updater, failing the test after Timeout.
+func WaitGeneration(t testing.TB, w dflag.DynamicFlagWatcher, generation uint64) {
+	t.Helper()
+	timer := time.NewTimer(Timeout)
+	defer timer.Stop()
+	for {
+		changed := w.Changed()
+		if w.Generation() >= generation {
+			return
+		}
+		select {
+		case <-changed:
+		case <-timer.C:
+			t.Fatalf("timeout waiting for generation %d, at %d", generation, w.Generation())
+			return
+		}
+	}
+}
+
+// Notifier counts the calls of a notifier so tests can wait for the asynchronous ones, e.g.
+// d.WithNotifier(n.Func()) then n.Wait(t, 1) after setting d.
+type Notifier[T any] struct {
+	fn    func(oldValue, newValue T)
+	mu    sync.Mutex
+	cond  *sync.Cond
+	calls int
+}
+
+// NewNotifier returns a Notifier calling fn (which can be nil).
+func NewNotifier[T any](fn func(oldValue, newValue T)) *Notifier[T] {
+	n := &Notifier[T]{fn: fn}
+	n.cond = sync.NewCond(&n.mu)
+	return n
+}
+
+// Func returns the notifier function to pass to WithNotifier or WithSyncNotifier.
+func (n *Notifier[T]) Func() func(oldValue, newValue T) {
+	return func(oldValue, newValue T) {
+		if n.fn != nil {
+			n.fn(oldValue, newValue)
+		}
+		n.mu.Lock()
+		n.calls++
+		n.mu.Unlock()
+		n.cond.Broadcast()
+	}
+}
+
+// Calls returns the number of completed calls.
+func (n *Notifier[T]) Calls() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.calls
+}
+
+// Wait waits until the notifier completed at least calls calls, failing the test after Timeout.
+func (n *Notifier[T]) Wait(t testing.TB, calls int) {
+	t.Helper()
+	timer := time.AfterFunc(Timeout, n.cond.Broadcast)
+	defer timer.Stop()
+	deadline := time.Now().Add(Timeout)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for n.calls < calls {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %d notifier calls, got %d", calls, n.calls)
+			return
+		}
+		n.cond.Wait()
+	}
+}
+
+// ErrNotWatching is returned by FakeSource.Push before Watch is called or after Stop.
+var ErrNotWatching = errors.New("dflagtest: source not watching")
+
+// FakeSource is a source.Source whose initial values are given and updates are pushed by the test.
+type FakeSource struct {
+	initial map[string][]byte
+	mu      sync.Mutex
+	ctx     context.Context
+	updates chan<- source.Update
+}
+
+// NewFakeSource returns a FakeSource initialized with the values.
+func NewFakeSource(initial map[string]string) *FakeSource {
+	values := make(map[string][]byte, len(initial))
+	for name, value := range initial {
+		values[name] = []byte(value)
+	}
+	return &FakeSource{initial: values}
+}
+
+// Initialize returns the initial values.
+func (f *FakeSource) Initialize(_ context.Context) (map[string][]byte, error) {
+	return f.initial, nil
+}
+
+// Watch records the updates channel used by Push.
+func (f *FakeSource) Watch(ctx context.Context, updates chan<- source.Update) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ctx, f.updates = ctx, updates
+	return nil
+}
+
+// Stop stops accepting pushes.
+func (f *FakeSource) Stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updates = nil
+}
+
+// Push sends an update of the named flag to the watching Applier. Use WaitGeneration or a Notifier
+// to wait for it to be applied.
+func (f *FakeSource) Push(name, value string) error {
+	return f.send(source.Update{Name: name, Value: []byte(value)})
+}
+
+// Delete sends the removal of the named flag from the source.
+func (f *FakeSource) Delete(name string) error {
+	return f.send(source.Update{Name: name, Deleted: true})
+}
+
+func (f *FakeSource) send(u source.Update) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.updates == nil {
+		return ErrNotWatching
+	}
+	select {
+	case f.updates <- u:
+		return nil
+	case <-f.ctx.Done():
+		return f.ctx.Err()
+	}
+}
+
+// LogEntry is one call to a Logger.
+type LogEntry struct {
+	Level string // "debug", "info", "warn" or "error".
+	Msg   string
+	Args  []any
+}
+
+// Attr returns the value of the key in the entry's key value args, nil if absent.
+func (e LogEntry) Attr(key string) any {
+	for i := 0; i+1 < len(e.Args); i += 2 {
+		if e.Args[i] == key {
+			return e.Args[i+1]
+		}
+	}
+	return nil
+}
+
+// Logger is a dflag.Logger recording the logs, e.g. for the WithLogger of the configmap Updater and
+// the endpoint. The zero value is ready to use.
+type Logger struct {
+	mu      sync.Mutex
+	entries []LogEntry
+}
+
+func (l *Logger) Debug(msg string, args ...any) { l.add("debug", msg, args) }
+func (l *Logger) Info(msg string, args ...any)  { l.add("info", msg, args) }
+func (l *Logger) Warn(msg string, args ...any)  { l.add("warn", msg, args) }
+func (l *Logger) Error(msg string, args ...any) { l.add("error", msg, args) }
+
+func (l *Logger) add(level, msg string, args []any) {
+	l.mu.Lock()
+	l.entries = append(l.entries, LogEntry{Level: level, Msg: msg, Args: args})
+	l.mu.Unlock()
+}
+
+// Entries returns the entries logged so far at level (all of them when empty), oldest first.
+func (l *Logger) Entries(level string) []LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var res []LogEntry
+	for _, e := range l.entries {
+		if level == "" || e.Level == level {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// Metrics is a dflag.Metrics counting the calls per flag name. The zero value is ready to use.
+type Metrics struct {
+	mu        sync.Mutex
+	successes map[string]int
+	errors    map[string]int
+	notifies  map[string][]time.Duration
+}
+
+func (m *Metrics) IncSetSuccess(name string) { m.inc(&m.successes, name) }
+func (m *Metrics) IncSetError(name string)   { m.inc(&m.errors, name) }
+
+func (m *Metrics) ObserveNotifyLatency(name string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.notifies == nil {
+		m.notifies = make(map[string][]time.Duration)
+	}
+	m.notifies[name] = append(m.notifies[name], d)
+}
+
+func (m *Metrics) inc(counts *map[string]int, name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if *counts == nil {
+		*counts = make(map[string]int)
+	}
+	(*counts)[name]++
+}
+
+// Successes returns how many successful sets of the flag were reported.
+func (m *Metrics) Successes(name string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.successes[name]
+}
+
+// Errors returns how many failed sets of the flag were reported.
+func (m *Metrics) Errors(name string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.errors[name]
+}
+
+// NotifyLatencies returns the notifier latencies reported for the flag, oldest first.
+func (m *Metrics) NotifyLatencies(name string) []time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]time.Duration(nil), m.notifies[name]...)
+}
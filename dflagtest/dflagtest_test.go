@@ -0,0 +1,88 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflagtest
+
+import (
+	"context"
+	"flag"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/source"
+)
+
+func TestSet(t *testing.T) {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	dynStr := dflag.DynString(set, "some-string", "a", "")
+	static := set.Int("some-int", 1, "")
+	t.Run("set", func(t *testing.T) {
+		Set(t, set, "some-string", "b")
+		Set(t, set, "some-int", "2")
+		SetV(t, dynStr, "c")
+		assert.Equal(t, "c", dynStr.Get())
+		assert.Equal(t, 2, *static)
+	})
+	assert.Equal(t, "a", dynStr.Get(), "restored")
+	assert.Equal(t, 1, *static, "restored")
+}
+
+func TestWaitAndNotifier(t *testing.T) {
+	d := dflag.New(int64(1), "")
+	var last int64
+	n := NewNotifier(func(_, newValue int64) { last = newValue })
+	d.WithNotifier(n.Func())
+	assert.NoError(t, d.SetV(2))
+	WaitGeneration(t, d, 1)
+	n.Wait(t, 1)
+	assert.Equal(t, int64(2), last)
+	assert.Equal(t, 1, n.Calls())
+}
+
+func TestFakeSource(t *testing.T) {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(set, "some-int", 1, "")
+	src := NewFakeSource(map[string]string{"some-int": "5"})
+	assert.Equal(t, ErrNotWatching, src.Push("some-int", "6"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a, err := source.Apply(ctx, set, src)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), dynInt.Get())
+	assert.NoError(t, src.Push("some-int", "7"))
+	WaitGeneration(t, dynInt, 2)
+	assert.Equal(t, int64(7), dynInt.Get())
+	assert.NoError(t, src.Delete("some-int"))
+	a.Stop()
+	assert.Equal(t, ErrNotWatching, src.Push("some-int", "8"))
+}
+
+func TestLogger(t *testing.T) {
+	var l dflag.Logger = &Logger{}
+	l.Info("started", "dir", "/etc/config", "count", 2)
+	l.Error("failed", "flag")
+	rec := l.(*Logger)
+	assert.Equal(t, 2, len(rec.Entries("")))
+	infos := rec.Entries("info")
+	assert.Equal(t, 1, len(infos))
+	assert.Equal(t, "started", infos[0].Msg)
+	assert.Equal(t, "/etc/config", infos[0].Attr("dir"))
+	assert.Equal(t, 2, infos[0].Attr("count"))
+	assert.Equal(t, nil, rec.Entries("error")[0].Attr("flag"), "key without value")
+	assert.Equal(t, 0, len(rec.Entries("debug")))
+}
+
+func TestMetrics(t *testing.T) {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(set, "some-int", 1, "").WithSyncNotifier(func(_, _ int64) {})
+	m := &Metrics{}
+	dflag.SetMetrics(set, m)
+	assert.NoError(t, set.Set("some-int", "2"))
+	assert.Error(t, set.Set("some-int", "x"))
+	assert.NoError(t, dynInt.SetV(3))
+	assert.Equal(t, 2, m.Successes("some-int"))
+	assert.Equal(t, 1, m.Errors("some-int"))
+	assert.Equal(t, 2, len(m.NotifyLatencies("some-int")))
+	assert.Equal(t, 0, m.Successes("other"))
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// DumpFormat is an output format of Dump.
+type DumpFormat string
+
+const (
+	// DumpJSON is a JSON array of FlagInfo.
+	DumpJSON DumpFormat = "json"
+	// DumpYAML is a YAML list of FlagInfo.
+	DumpYAML DumpFormat = "yaml"
+	// DumpEnv is a shell sourceable NAME='value' line per flag, named like FlagEnvName (without prefix).
+	DumpEnv DumpFormat = "env"
+)
+
+// FlagInfo describes a flag's effective configuration.
+type FlagInfo struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Default string `json:"default"`
+	Dynamic bool   `json:"dynamic"`
+	Changed bool   `json:"changed"`
+	// Source is who or what last set the value, when recorded (see WithHistory and SetFlagFrom).
+	Source string `json:"source,omitempty"`
+}
+
+// Describe returns the FlagInfo of all the flags of flagSet, sorted by name.
+func Describe(flagSet *flag.FlagSet) []FlagInfo {
+	var res []FlagInfo
+	flagSet.VisitAll(func(f *flag.Flag) {
+		info := FlagInfo{
+			Name:    f.Name,
+			Value:   f.Value.String(),
+			Default: f.DefValue,
+			Dynamic: IsFlagDynamic(f),
+		}
+		info.Changed = IsChanged(f)
+		if h, ok := f.Value.(DynamicFlagHistory); ok {
+			if entries := h.History(); len(entries) > 0 {
+				info.Source = entries[len(entries)-1].Source
+			}
+		}
+		res = append(res, info)
+	})
+	return res
+}
+
+// Dump returns the effective configuration of all the flags of flagSet in the format, e.g. for a startup
+// log line or the endpoint's export.
+func Dump(flagSet *flag.FlagSet, format DumpFormat) (string, error) {
+	infos := Describe(flagSet)
+	var sb strings.Builder
+	switch format {
+	case DumpJSON:
+		if infos == nil {
+			infos = []FlagInfo{}
+		}
+		b, err := json.MarshalIndent(infos, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		sb.Write(b)
+		sb.WriteRune('\n')
+	case DumpYAML:
+		if len(infos) == 0 {
+			return "[]\n", nil
+		}
+		for _, info := range infos {
+			fmt.Fprintf(&sb, "- name: %s\n  value: %s\n  default: %s\n  dynamic: %t\n  changed: %t\n",
+				yamlQuote(info.Name), yamlQuote(info.Value), yamlQuote(info.Default), info.Dynamic, info.Changed)
+			if info.Source != "" {
+				fmt.Fprintf(&sb, "  source: %s\n", yamlQuote(info.Source))
+			}
+		}
+	case DumpEnv:
+		for _, info := range infos {
+			sb.WriteString(FlagEnvName("", info.Name) + "='" + strings.ReplaceAll(info.Value, "'", `'\''`) + "'\n")
+		}
+	default:
+		return "", fmt.Errorf("dflag: unknown dump format %q, expecting json, yaml or env", format)
+	}
+	return sb.String(), nil
+}
+
+// yamlQuote returns s as a YAML double quoted scalar (JSON strings are valid ones).
+func yamlQuote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"encoding/json"
+	"flag"
+	"testing"
+
+	"fortio.org/assert"
+)
+
+func dumpFlagSet(t *testing.T) *flag.FlagSet {
+	t.Helper()
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	DynString(set, "some-str", "a", "").WithHistory(2)
+	DynInt64(set, "some_int", 1, "")
+	set.Bool("static.bool", false, "")
+	assert.NoError(t, SetFlagFrom(set, "some-str", "it's", "admin"))
+	assert.NoError(t, set.Set("static.bool", "true"))
+	return set
+}
+
+func TestDescribe(t *testing.T) {
+	assert.Equal(t, []FlagInfo{
+		{Name: "some-str", Value: "it's", Default: "a", Dynamic: true, Changed: true, Source: "admin"},
+		{Name: "some_int", Value: "1", Default: "1", Dynamic: true},
+		{Name: "static.bool", Value: "true", Default: "false", Changed: true},
+	}, Describe(dumpFlagSet(t)))
+}
+
+func TestDump(t *testing.T) {
+	set := dumpFlagSet(t)
+	out, err := Dump(set, DumpJSON)
+	assert.NoError(t, err)
+	var infos []FlagInfo
+	assert.NoError(t, json.Unmarshal([]byte(out), &infos))
+	assert.Equal(t, Describe(set), infos)
+	out, err = Dump(set, DumpYAML)
+	assert.NoError(t, err)
+	assert.Equal(t, `- name: "some-str"
+  value: "it's"
+  default: "a"
+  dynamic: true
+  changed: true
+  source: "admin"
+- name: "some_int"
+  value: "1"
+  default: "1"
+  dynamic: true
+  changed: false
+- name: "static.bool"
+  value: "true"
+  default: "false"
+  dynamic: false
+  changed: true
+`, out)
+	out, err = Dump(set, DumpEnv)
+	assert.NoError(t, err)
+	assert.Equal(t, "SOME_STR='it'\\''s'\nSOME_INT='1'\nSTATIC_BOOL='true'\n", out)
+	_, err = Dump(set, "xml")
+	assert.Error(t, err)
+	empty := flag.NewFlagSet("empty", flag.ContinueOnError)
+	out, _ = Dump(empty, DumpJSON)
+	assert.Equal(t, "[]\n", out)
+	out, _ = Dump(empty, DumpYAML)
+	assert.Equal(t, "[]\n", out)
+}
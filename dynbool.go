@@ -5,43 +5,28 @@ package dflag
 
 import (
 	"flag"
-	"fmt"
 )
 
-// Would use just
-// type DynBoolValue = DynValue[bool]
-// but that doesn't work with IsBoolFlag
-// https://github.com/golang/go/issues/53473
-// only fixed in go 1.20
-// so we extend this type as special, the only one with that method.
+// DynBoolValue is the dynamic bool flag, like all the DynValue[bool] ones (e.g. from Dyn()) a bool flag
+// for the flag package: `-name` alone sets it to true.
+type DynBoolValue = DynValue[bool]
 
+// NewBool is New for bools, kept for backward compatibility.
 func NewBool(value bool, usage string) *DynBoolValue {
-	dynValue := DynBoolValue{}
-	dynInit(&dynValue.DynValue, value, usage)
-	return &dynValue
+	return New(value, usage)
 }
 
+// FlagBool is Flag for bools, kept for backward compatibility.
 func FlagBool(name string, o *DynBoolValue) *DynBoolValue {
-	return FlagSetBool(flag.CommandLine, name, o)
+	return FlagSet(flag.CommandLine, name, o)
 }
 
+// FlagSetBool is FlagSet for bools, kept for backward compatibility.
 func FlagSetBool(flagSet *flag.FlagSet, name string, dynValue *DynBoolValue) *DynBoolValue {
-	// we inline/repeat code of FlagSet(flagSet, name, &dynValue.DynValue)
-	// because we need to set this specific type for the IsBoolFlag to work
-	dynValue.flagSet = flagSet
-	dynValue.flagName = name
-	flagSet.Var(dynValue, name, dynValue.usage)
-	flagSet.Lookup(name).DefValue = fmt.Sprintf("%v", dynValue.av.Load())
-	return dynValue
+	return FlagSet(flagSet, name, dynValue)
 }
 
 // DynBool creates a `Flag` that represents `bool` which is safe to change dynamically at runtime.
 func DynBool(flagSet *flag.FlagSet, name string, value bool, usage string) *DynBoolValue {
-	return FlagSetBool(flagSet, name, NewBool(value, usage))
-}
-
-// DynStringSetValue implements a dynamic set of strings.
-type DynBoolValue struct {
-	DynamicBoolValueTag
-	DynValue[bool]
+	return FlagSet(flagSet, name, New(value, usage))
 }
@@ -73,6 +73,18 @@ func TestDynBool_SyncNotifier(t *testing.T) {
 	assert.True(t, called, "called")
 }
 
+func TestDynBool_NoValueSyntax(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	b1 := DynBool(set, "b1", false, "")
+	b2 := Dyn(set, "b2", false, "generic constructor")
+	b3 := FlagSet(set, "b3", New(false, "2 steps"))
+	s := Dyn(set, "s", "x", "")
+	assert.False(t, s.IsBoolFlag(), "only bools are bool flags")
+	assert.NoError(t, set.Parse([]string{"-b1", "-b2", "-b3", "-s", "-b4"}))
+	assert.True(t, b1.Get() && b2.Get() && b3.Get(), "all set without value")
+	assert.Equal(t, "-b4", s.Get(), "non bool flags still take the next argument")
+}
+
 func Benchmark_Bool_Dyn_Get(b *testing.B) {
 	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
 	value := DynBool(set, "some_bool_1", true, "Use it or lose it")
@@ -0,0 +1,65 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"flag"
+	"sync"
+)
+
+// DynFuncValue is a dynamic flag calling a function with each new value, see DynFunc.
+type DynFuncValue[T any] struct {
+	DynValue[T]
+	mu sync.Mutex // serializes the calls to fn with the value changes.
+	fn func(T) error
+}
+
+// DynFunc is the dynamic equivalent of flag.Func: it creates a `Flag` whose sets call fn with the new
+// (parsed, mutated and validated) value, e.g. to bridge to a setter style API, and that is only kept
+// (and the notifier called) when fn doesn't return an error. Get and String return the last good value;
+// fn isn't called with the default value nor by ValidateInput.
+func DynFunc[T DynValueTypes](flagSet *flag.FlagSet, name string, value T, usage string,
+	fn func(T) error,
+) *DynFuncValue[T] {
+	dynValue := &DynFuncValue[T]{fn: fn}
+	dynInit(&dynValue.DynValue, value, usage)
+	dynValue.flagSet = flagSet
+	dynValue.flagName = name
+	flagSet.Var(dynValue, name, usage) // use our Set()
+	flagSet.Lookup(name).DefValue = dynValue.String()
+	return dynValue
+}
+
+// Set parses the input and sets the value with SetV.
+func (d *DynFuncValue[T]) Set(rawInput string) error {
+	val, err := d.parseInput(rawInput)
+	if err != nil {
+		return d.setError(err)
+	}
+	return d.SetV(val)
+}
+
+// SetV calls the function with the mutated and validated value, and keeps it if it succeeds.
+func (d *DynFuncValue[T]) SetV(val T) error {
+	if d.mutator != nil {
+		val = d.mutator(val)
+	}
+	if d.validator != nil {
+		if err := d.validator(val); err != nil {
+			return d.setError(d.redactValueError(err, val))
+		}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.fn(val); err != nil {
+		return d.setError(d.redactValueError(err, val))
+	}
+	d.setNoMutate(val)
+	return nil
+}
+
+// Reset sets the flag back to its default value, calling the function.
+func (d *DynFuncValue[T]) Reset() error {
+	return d.SetV(d.defaultValue)
+}
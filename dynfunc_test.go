@@ -0,0 +1,79 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+
+	"fortio.org/assert"
+)
+
+func TestDynFunc(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	var calls []int64
+	workers := DynFunc(set, "workers", 4, "number of workers", func(n int64) error {
+		if n > 100 {
+			return errors.New("too many workers")
+		}
+		calls = append(calls, n)
+		return nil
+	})
+	workers.WithValidator(ValidateRange[int64](1, 1000))
+	var _ DynamicFlagValidator = workers
+	assert.True(t, IsFlagDynamic(set.Lookup("workers")), "DynFunc is dynamic")
+	assert.Equal(t, "4", set.Lookup("workers").DefValue)
+	assert.Equal(t, 0, len(calls), "not called with the default value")
+	assert.NoError(t, set.Set("workers", " 8 "))
+	assert.Equal(t, int64(8), workers.Get())
+	assert.Equal(t, "8", workers.String())
+	assert.Error(t, set.Set("workers", "x"))
+	assert.Error(t, set.Set("workers", "0"), "validator")
+	assert.Error(t, set.Set("workers", "200"), "function error")
+	assert.Equal(t, int64(8), workers.Get(), "last good value kept")
+	assert.Equal(t, uint64(1), workers.Generation())
+	assert.NoError(t, workers.ValidateInput("200"), "function not called by ValidateInput")
+	assert.NoError(t, workers.Reset())
+	assert.Equal(t, []int64{8, 4}, calls)
+}
+
+func TestDynFuncMutatorAppliedOnce(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	var calls []int64
+	f := DynFunc(set, "f", 1, "doubled", func(n int64) error {
+		calls = append(calls, n)
+		return nil
+	})
+	f.WithValueMutator(func(n int64) int64 { return 2 * n })
+	f.WithValidator(func(n int64) error {
+		if n%2 != 0 {
+			return errors.New("not mutated")
+		}
+		return nil
+	})
+	assert.NoError(t, set.Set("f", "3"))
+	assert.Equal(t, int64(6), f.Get())
+	assert.NoError(t, f.SetV(5))
+	assert.Equal(t, int64(10), f.Get())
+	assert.Equal(t, []int64{6, 10}, calls)
+}
+
+func TestDynFuncSensitive(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	f := DynFunc(set, "token", "", "api token", func(s string) error {
+		if s == "bad-secret" {
+			return errors.New("rejected bad-secret")
+		}
+		return nil
+	})
+	f.Sensitive()
+	assert.NoError(t, set.Set("token", "s3cret"))
+	assert.True(t, errors.Is(set.Set("token", Redacted), ErrRedactedValue))
+	err := set.Set("token", "bad-secret")
+	assert.Error(t, err)
+	assert.False(t, strings.Contains(err.Error(), "bad-secret"), err.Error())
+	assert.Equal(t, "s3cret", f.Get())
+}
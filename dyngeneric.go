@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -20,6 +21,32 @@ type DynamicFlagValue interface {
 	IsDynamicFlag() bool
 }
 
+// DynamicFlagValidator is implemented by dynamic flags to check a value without setting it.
+type DynamicFlagValidator interface {
+	ValidateInput(rawInput string) error
+}
+
+// DynamicFlagResetter is implemented by dynamic flags that can be reset to their default value.
+type DynamicFlagResetter interface {
+	Reset() error
+}
+
+// DynamicFlagWatcher is implemented by dynamic flags to wait for changes: Generation counts the
+// successful sets, the Changed channel is closed at the next one and LastModified is the time of
+// the last one (zero if never set).
+type DynamicFlagWatcher interface {
+	Generation() uint64
+	Changed() <-chan struct{}
+	LastModified() time.Time
+}
+
+// DynamicFlagHints is implemented by dynamic flags to describe their acceptable values, e.g. for UIs:
+// the Choices set by WithOneOf (nil if any value is accepted) and the range set by WithRange (ok false if none).
+type DynamicFlagHints interface {
+	Choices() []string
+	Range() (fromInclusive, toInclusive string, ok bool)
+}
+
 // DynamicJSONFlagValue is a tag interface for JSON dynamic flags.
 type DynamicJSONFlagValue interface {
 	IsJSON() bool
@@ -53,6 +80,8 @@ func IsBinary(f *flag.Flag) *DynValue[[]byte] {
 	return nil
 }
 
+// DynamicBoolValueTag makes the flag values embedding it bool flags (e.g. the wrapped standard bool flags,
+// see Wrap), the DynValue[bool] ones already are.
 type DynamicBoolValueTag struct{}
 
 func (*DynamicBoolValueTag) IsBoolFlag() bool {
@@ -90,6 +119,7 @@ type DynValueTypes interface {
 type DynValue[T any] struct {
 	DynamicFlagValueTag
 	av           atomic.Value
+	defaultValue T
 	flagName     string
 	flagSet      *flag.FlagSet
 	ready        bool
@@ -99,6 +129,39 @@ type DynValue[T any] struct {
 	mutator      func(inp T) T
 	inpMutator   func(inp string) string
 	usage        string
+	// hints of the acceptable values, see DynamicFlagHints.
+	choices            []string
+	hasRange           bool
+	rangeFrom, rangeTo string
+	// generation and changed channel, see DynamicFlagWatcher.
+	generation atomic.Uint64
+	lastSet    atomic.Int64 // unix nanoseconds.
+	changedMu  sync.Mutex
+	changed    chan struct{}
+	// recent values, see WithHistory.
+	history *valueHistory
+	// metrics reported, nil for none, see WithMetrics.
+	metrics Metrics
+	// str caches the String() of the current generation.
+	str atomic.Pointer[stringCache]
+	// copier, when set, returns copies of the values given out, see WithGetPolicy.
+	copier func(T) T
+	// sensitive values are redacted in outputs, see Sensitive.
+	sensitive bool
+	// per tenant overlay values, see GetFor.
+	tenantsMu sync.Mutex
+	tenants   atomic.Pointer[map[string]T]
+	// flags to apply before this one in bulk updates, see WithApplyAfter.
+	applyAfter []string
+	// onSwap, when set, gets each new value right after it's stored (before the notifiers), e.g.
+	// for DynTypedJSONValue's pointer.
+	onSwap func(T)
+}
+
+// stringCache is the string representation of the value of a generation.
+type stringCache struct {
+	generation uint64
+	s          string
 }
 
 // New allows to define a dynamic flag in 2 steps. With the default value and other
@@ -140,27 +203,21 @@ func Dyn[T DynValueTypes](flagSet *flag.FlagSet, name string, value T, usage str
 
 func dynInit[T any](dynValue *DynValue[T], value T, usage string) {
 	dynValue.av.Store(value)
+	dynValue.defaultValue = value
 	dynValue.inpMutator = strings.TrimSpace // default so parsing of numbers etc works well
 	dynValue.usage = usage
+	dynValue.copier = defaultCopier[T]()
 	dynValue.ready = true
 }
 
-// Unfortunately IsBoolFlag isn't called, just presence is needed
-// https://github.com/golang/go/issues/53473
-// fixed in 1.20 only
-
-/*
-// lets the flag parsing know that -flagname is enough to turn to true.
+// IsBoolFlag lets the flag parsing know that -flagname is enough to turn to true, for the bool flags.
+// (Go 1.20+ flag package calls it instead of only checking the method is present, see
+// https://github.com/golang/go/issues/53473).
 func (d *DynValue[T]) IsBoolFlag() bool {
 	var v T
-	switch any(v).(type) {
-	case bool:
-		return true
-	default:
-		return false
-	}
+	_, isBool := any(v).(bool)
+	return isBool
 }
-*/
 
 // Get retrieves the value in a thread-safe manner.
 func (d *DynValue[T]) Get() T {
@@ -170,6 +227,19 @@ func (d *DynValue[T]) Get() T {
 		// which happens in error case (and is tested in nildptr_test.go)
 		return zero
 	}
+	v := d.av.Load().(T)
+	if d.copier != nil {
+		return d.copier(v)
+	}
+	return v
+}
+
+// load returns the stored value (not a copy, see GetPolicy), for read only uses.
+func (d *DynValue[T]) load() T {
+	var zero T
+	if !d.ready {
+		return zero
+	}
 	return d.av.Load().(T)
 }
 
@@ -189,6 +259,32 @@ func Parse[T DynValueTypes](input string) (val T, err error) {
 	return parse[T](input)
 }
 
+// ParseAny is Parse for the type named typeName: as returned by the dynamic flags' Type() without its
+// dyn_ prefix (bool, int64, float64, time.Duration, string, []string, sets.Set[string] and []uint8
+// or []byte), e.g. for fuzzing or generic tooling.
+func ParseAny(typeName, input string) (interface{}, error) {
+	switch typeName {
+	case "bool":
+		return parse[bool](input)
+	case "int64":
+		return parse[int64](input)
+	case "float64":
+		return parse[float64](input)
+	case "time.Duration":
+		return parse[time.Duration](input)
+	case "string":
+		return parse[string](input)
+	case "[]string":
+		return parse[[]string](input)
+	case "sets.Set[string]":
+		return parse[sets.Set[string]](input)
+	case "[]uint8", "[]byte":
+		return parse[[]byte](input)
+	default:
+		return nil, fmt.Errorf("unexpected type %q", typeName)
+	}
+}
+
 func parse[T any](input string) (val T, err error) {
 	switch v := any(&val).(type) {
 	case *bool:
@@ -206,7 +302,7 @@ func parse[T any](input string) (val T, err error) {
 	case *[]string:
 		*v = CommaStringToSlice(input)
 	case *sets.Set[string]:
-		*v = sets.FromSlice(CommaStringToSlice(input))
+		*v = commaStringToSet(input)
 	default:
 		// JSON Set() and thus Parse() is handled in dynjson.go
 		err = fmt.Errorf("unexpected type %T", val)
@@ -219,15 +315,50 @@ func parse[T any](input string) (val T, err error) {
 // optional validator.
 // If a notifier is set on the value, it will be invoked in a separate go-routine.
 func (d *DynValue[T]) Set(rawInput string) error {
+	val, err := d.parseInput(rawInput)
+	if err != nil {
+		return d.setError(err)
+	}
+	return d.SetV(val)
+}
+
+// parseInput applies the input mutator and parses the result, refusing the Redacted placeholder
+// for the sensitive flags (whose parsing errors are redacted).
+func (d *DynValue[T]) parseInput(rawInput string) (T, error) {
 	input := rawInput
 	if d.inpMutator != nil {
 		input = d.inpMutator(rawInput)
 	}
+	if d.sensitive && input == Redacted {
+		var zero T
+		return zero, ErrRedactedValue
+	}
 	val, err := parse[T](input)
+	if err != nil {
+		return val, d.redactError(err, rawInput, input)
+	}
+	return val, nil
+}
+
+// ValidateInput checks, without changing the value or calling notifiers, whether
+// Set(rawInput) would succeed: input mutation, parsing, value mutation and validation are applied.
+func (d *DynValue[T]) ValidateInput(rawInput string) error {
+	val, err := d.parseInput(rawInput)
 	if err != nil {
 		return err
 	}
-	return d.SetV(val)
+	return d.ValidateV(val)
+}
+
+// ValidateV checks, without changing the value or calling notifiers, whether SetV(val) would succeed.
+func (d *DynValue[T]) ValidateV(val T) error {
+	if d.mutator != nil {
+		val = d.mutator(val)
+	}
+	if d.validator != nil {
+		return d.redactValueError(d.validator(val), val)
+	}
+	return nil
 }
 
 // SetV is for when the value is already parsed/of the correct type.
@@ -240,18 +371,91 @@ func (d *DynValue[T]) SetV(val T) error {
 	}
 	if d.validator != nil {
 		if err := d.validator(val); err != nil {
-			return err
+			return d.setError(d.redactValueError(err, val))
 		}
 	}
+	d.setNoMutate(val)
+	return nil
+}
+
+// setNoMutate stores the already mutated and validated val, then notifies of the change.
+func (d *DynValue[T]) setNoMutate(val T) {
 	oldVal := d.av.Swap(val).(T)
+	if d.onSwap != nil {
+		d.onSwap(val)
+	}
+	d.lastSet.Store(time.Now().UnixNano())
+	d.recordHistory(d.generation.Add(1), val)
+	d.changedMu.Lock()
+	if d.changed != nil {
+		close(d.changed)
+		d.changed = nil
+	}
+	d.changedMu.Unlock()
+	if d.metrics != nil {
+		d.metrics.IncSetSuccess(d.flagName)
+	}
 	if d.notifier != nil {
+		if d.copier != nil {
+			oldVal, val = d.copier(oldVal), d.copier(val)
+		}
 		if d.syncNotifier {
-			d.notifier(oldVal, val)
+			d.notify(oldVal, val)
 		} else {
-			go d.notifier(oldVal, val)
+			go d.notify(oldVal, val)
 		}
 	}
-	return nil
+}
+
+// Generation returns the number of times the value was successfully set.
+func (d *DynValue[T]) Generation() uint64 {
+	return d.generation.Load()
+}
+
+// LastModified returns when the value was last set, zero if it never was.
+func (d *DynValue[T]) LastModified() time.Time {
+	ns := d.lastSet.Load()
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
+
+// Changed returns a channel closed when the value is next set. Check Generation after getting it to
+// not miss a change happening in between.
+func (d *DynValue[T]) Changed() <-chan struct{} {
+	d.changedMu.Lock()
+	defer d.changedMu.Unlock()
+	if d.changed == nil {
+		d.changed = make(chan struct{})
+	}
+	return d.changed
+}
+
+// Default returns the default value the flag was created with.
+func (d *DynValue[T]) Default() T {
+	if d.copier != nil {
+		return d.copier(d.defaultValue)
+	}
+	return d.defaultValue
+}
+
+// Reset sets the flag back to its default value (validators and notifiers are triggered like for SetV).
+func (d *DynValue[T]) Reset() error {
+	return d.SetV(d.defaultValue)
+}
+
+// ResetFlag resets the named flag to its default value: using Reset() for dynamic flags and
+// setting the flag's DefValue string for other flags.
+func ResetFlag(flagSet *flag.FlagSet, name string) error {
+	f := flagSet.Lookup(name)
+	if f == nil {
+		return fmt.Errorf("flag %q not found", name)
+	}
+	if r, ok := f.Value.(DynamicFlagResetter); ok {
+		return r.Reset()
+	}
+	return flagSet.Set(name, f.DefValue)
 }
 
 // WithValidator adds a function that checks values before they're set.
@@ -284,7 +488,33 @@ func (d *DynValue[T]) Type() string {
 
 // String returns the canonical string representation of the type.
 func (d *DynValue[T]) String() string {
-	switch v := any(d.Get()).(type) {
+	if d.sensitive {
+		return Redacted
+	}
+	return d.cachedString(&d.str, valueString[T])
+}
+
+// cachedString returns render(d.load()), rendered once per generation and kept in cache: String() is called
+// for each listing and PrintDefaults, which is costly for large values (e.g. base64 of megabytes of []byte).
+// Consumers modifying the value returned by Get() in place get a stale String().
+func (d *DynValue[T]) cachedString(cache *atomic.Pointer[stringCache], render func(T) string) string {
+	if !d.ready {
+		return render(d.load())
+	}
+	generation := d.generation.Load()
+	if c := cache.Load(); c != nil && c.generation == generation {
+		return c.s
+	}
+	s := render(d.load())
+	// Not cached when set meanwhile: s could be the previous generation's.
+	if d.generation.Load() == generation {
+		cache.Store(&stringCache{generation: generation, s: s})
+	}
+	return s
+}
+
+func valueString[T any](value T) string {
+	switch v := any(value).(type) {
 	case []string:
 		return strings.Join(v, ",")
 	case []byte:
@@ -316,6 +546,70 @@ func (d *DynValue[T]) WithFileFlag(defaultPath string) (*DynValue[T], *FileReadV
 	return d, FileReadFlag(d.flagSet, d.flagName, defaultPath)
 }
 
+// ValidateOneOf returns a validator that checks if the value is one of the given values.
+func ValidateOneOf[T comparable](values ...T) func(T) error {
+	return func(value T) error {
+		for _, v := range values {
+			if value == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("value %v not one of %v", value, values)
+	}
+}
+
+// WithOneOf sets a validator only accepting the given values (compared by their string representation,
+// so it also works for slices and sets) and records them as the flag's Choices, e.g. for a dropdown in the
+// endpoint's UI.
+func (d *DynValue[T]) WithOneOf(values ...T) *DynValue[T] {
+	d.choices = make([]string, len(values))
+	for i, v := range values {
+		d.choices[i] = valueString(v)
+	}
+	validator := ValidateOneOf(d.choices...)
+	d.validator = func(value T) error {
+		return validator(valueString(value))
+	}
+	return d
+}
+
+// WithRange sets a ValidateRange validator and records the range as the flag's Range, e.g. for the
+// bounds of a number input in the endpoint's UI. T must be ordered (int64, float64, time.Duration
+// or string), it panics otherwise.
+func (d *DynValue[T]) WithRange(fromInclusive, toInclusive T) *DynValue[T] {
+	switch from := any(fromInclusive).(type) {
+	case int64:
+		d.validator = rangeValidator[T](from, any(toInclusive).(int64))
+	case float64:
+		d.validator = rangeValidator[T](from, any(toInclusive).(float64))
+	case time.Duration:
+		d.validator = rangeValidator[T](from, any(toInclusive).(time.Duration))
+	case string:
+		d.validator = rangeValidator[T](from, any(toInclusive).(string))
+	default:
+		panic(fmt.Sprintf("dflag: WithRange on non ordered type %T", fromInclusive))
+	}
+	d.hasRange, d.rangeFrom, d.rangeTo = true, valueString(fromInclusive), valueString(toInclusive)
+	return d
+}
+
+func rangeValidator[T any, O constraints.Ordered](fromInclusive, toInclusive O) func(T) error {
+	validator := ValidateRange(fromInclusive, toInclusive)
+	return func(value T) error {
+		return validator(any(value).(O))
+	}
+}
+
+// Choices returns the values set by WithOneOf, nil if none.
+func (d *DynValue[T]) Choices() []string {
+	return d.choices
+}
+
+// Range returns the bounds set by WithRange, ok is false if none.
+func (d *DynValue[T]) Range() (fromInclusive, toInclusive string, ok bool) {
+	return d.rangeFrom, d.rangeTo, d.hasRange
+}
+
 // ValidateRange returns a validator that checks if the value is in the given range.
 func ValidateRange[T constraints.Ordered](fromInclusive T, toInclusive T) func(T) error {
 	return func(value T) error {
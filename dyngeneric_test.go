@@ -4,8 +4,10 @@
 package dflag
 
 import (
+	"encoding/base64"
 	"flag"
 	"testing"
+	"time"
 
 	"fortio.org/assert"
 	"fortio.org/sets"
@@ -93,3 +95,142 @@ func TestBinary(t *testing.T) {
 		t.Errorf("flag %v isn't binary yet it should", flag)
 	}
 }
+
+func TestReset(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := DynInt64(set, "some_dynint", 5, "some dyn int")
+	dynJSON := DynJSON(set, "some_json", &outerJSON{FieldString: "x"}, "some json")
+	staticInt := set.Int("some_int", 3, "some static int")
+	notified := int64(0)
+	dynInt.WithSyncNotifier(func(_, newV int64) { notified = newV })
+	assert.NoError(t, set.Set("some_dynint", "42"))
+	assert.NoError(t, set.Set("some_json", `{"string": "y"}`))
+	assert.NoError(t, set.Set("some_int", "7"))
+	assert.Equal(t, int64(5), dynInt.Default())
+	assert.NoError(t, ResetFlag(set, "some_dynint"))
+	assert.Equal(t, int64(5), dynInt.Get())
+	assert.Equal(t, int64(5), notified, "notifier should be called on reset")
+	assert.NoError(t, ResetFlag(set, "some_json"))
+	assert.Equal(t, "x", dynJSON.Get().(*outerJSON).FieldString)
+	assert.NoError(t, ResetFlag(set, "some_int"))
+	assert.Equal(t, 3, *staticInt)
+	assert.Error(t, ResetFlag(set, "no_such_flag"))
+}
+
+func TestGenerationAndChanged(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := DynInt64(set, "some_dynint", 5, "some dyn int")
+	dynJSON := DynJSON(set, "some_json", &outerJSON{FieldString: "x"}, "some json")
+	var _ DynamicFlagWatcher = dynJSON
+	assert.Equal(t, uint64(0), dynInt.Generation())
+	assert.True(t, dynInt.LastModified().IsZero(), "never set")
+	changed := dynInt.Changed()
+	assert.Error(t, set.Set("some_dynint", "x"))
+	select {
+	case <-changed:
+		t.Fatal("changed channel should not be closed by a failed set")
+	default:
+	}
+	assert.NoError(t, set.Set("some_dynint", "42"))
+	<-changed
+	assert.Equal(t, uint64(1), dynInt.Generation())
+	assert.True(t, time.Since(dynInt.LastModified()) < time.Minute, "just set")
+	assert.True(t, dynInt.Changed() != changed, "a new channel should be used for the next change")
+	assert.NoError(t, set.Set("some_json", `{"string": "y"}`))
+	assert.Equal(t, uint64(1), dynJSON.Generation())
+}
+
+func TestOneOfAndRange(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	mode := DynString(set, "mode", "fast", "some dyn string").WithOneOf("fast", "safe")
+	slice := DynStringSlice(set, "slice", []string{"a"}, "some dyn slice").WithOneOf([]string{"a"}, []string{"a", "b"})
+	size := DynInt64(set, "size", 5, "some dyn int").WithRange(1, 10)
+	ratio := DynFloat64(set, "ratio", 0.5, "some dyn float")
+	var _ DynamicFlagHints = mode
+	assert.Equal(t, []string{"fast", "safe"}, mode.Choices())
+	assert.NoError(t, set.Set("mode", "safe"))
+	assert.Error(t, set.Set("mode", "other"))
+	assert.Equal(t, "safe", mode.Get())
+	assert.NoError(t, set.Set("slice", "a,b"))
+	assert.Error(t, set.Set("slice", "b"))
+	assert.Equal(t, []string{"a", "a,b"}, slice.Choices())
+	from, to, ok := size.Range()
+	assert.True(t, ok, "range set")
+	assert.Equal(t, "1", from)
+	assert.Equal(t, "10", to)
+	assert.Error(t, set.Set("size", "11"))
+	assert.NoError(t, set.Set("size", "10"))
+	_, _, ok = ratio.Range()
+	assert.False(t, ok, "no range")
+	assert.Equal(t, 0, len(ratio.Choices()))
+	ratio.WithRange(0, 1)
+	assert.Error(t, set.Set("ratio", "1.5"))
+	assert.True(t, ValidateOneOf(1, 2)(3) != nil, "not one of")
+	defer func() {
+		assert.True(t, recover() != nil, "WithRange should panic on non ordered types")
+	}()
+	slice.WithRange(nil, nil)
+}
+
+func TestStringCache(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	bin := Dyn(set, "some_bin", []byte{1}, "")
+	assert.Equal(t, "AQ==", bin.String())
+	cached := bin.str.Load()
+	assert.Equal(t, "AQ==", bin.String())
+	assert.True(t, cached == bin.str.Load(), "not rendered again")
+	assert.NoError(t, bin.SetV([]byte{2}))
+	assert.Equal(t, "Ag==", bin.String(), "invalidated by the set")
+	js := DynJSON(set, "some_json", &struct{ A int }{1}, "")
+	assert.Equal(t, `{"A":1}`, js.String())
+	assert.NoError(t, set.Set("some_json", `{"A": 2}`))
+	assert.Equal(t, `{"A":2}`, js.String())
+	assert.Equal(t, "&{2}", js.DynValue.String(), "separate caches")
+}
+
+func BenchmarkString_LargeBinary(b *testing.B) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	bin := Dyn(set, "some_bin", make([]byte, 1<<20), "")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = bin.String()
+	}
+}
+
+func BenchmarkSet_Int64(b *testing.B) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	Dyn(set, "some_int", int64(0), "")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = set.Set("some_int", " 12345 ")
+	}
+}
+
+func BenchmarkSet_StringSlice(b *testing.B) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	Dyn(set, "some_slice", []string{}, "")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = set.Set("some_slice", "a,b,c,d,e,f,g,h")
+	}
+}
+
+func BenchmarkSet_StringSet(b *testing.B) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	Dyn(set, "some_set", sets.New[string](), "")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = set.Set("some_set", "a,b,c,d,e,f,g,h")
+	}
+}
+
+func BenchmarkSet_Binary(b *testing.B) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	Dyn(set, "some_bin", []byte{}, "")
+	input := base64.StdEncoding.EncodeToString(make([]byte, 64<<10))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = set.Set("some_bin", input)
+	}
+}
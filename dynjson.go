@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"flag"
 	"reflect"
+	"sync/atomic"
 )
 
 // JSON is the only/most kudlgy type, not playing so well or reusing as much as the rest of the generic re-implementation.
@@ -16,6 +17,7 @@ import (
 // DynJSON creates a `Flag` that is backed by an arbitrary JSON which is safe to change dynamically at runtime.
 // The `value` must be a pointer to a struct that is JSON (un)marshallable.
 // New values based on the default constructor of `value` type will be created on each update.
+// YAML values are also accepted when a YAML decoder is registered, see RegisterYAML.
 func DynJSON(flagSet *flag.FlagSet, name string, value interface{}, usage string) *DynJSONValue {
 	reflectVal := reflect.ValueOf(value)
 
@@ -37,6 +39,7 @@ func DynJSON(flagSet *flag.FlagSet, name string, value interface{}, usage string
 type DynJSONValue struct {
 	DynValue[interface{}]
 	structType reflect.Type
+	jsonStr    atomic.Pointer[stringCache] // String() cache, the DynValue one is for its own String().
 }
 
 // IsJSON always return true (method is present for the DynamicJSONFlagValue interface tagging).
@@ -54,22 +57,40 @@ func (d *DynJSONValue) Set(rawInput string) error {
 		input = d.inpMutator(rawInput)
 	}
 	val := reflect.New(d.structType).Interface()
-	if err := json.Unmarshal([]byte(input), val); err != nil {
-		return err
+	if err := unmarshalValue(input, val); err != nil {
+		return d.setError(err)
 	}
 	return d.SetV(val)
 }
 
+// ValidateInput checks, without changing the value or calling notifiers, whether Set(rawInput) would succeed.
+func (d *DynJSONValue) ValidateInput(rawInput string) error {
+	input := rawInput
+	if d.inpMutator != nil {
+		input = d.inpMutator(rawInput)
+	}
+	val := reflect.New(d.structType).Interface()
+	if err := unmarshalValue(input, val); err != nil {
+		return err
+	}
+	return d.ValidateV(val)
+}
+
 // String returns the canonical string representation of the type.
 func (d *DynJSONValue) String() string {
 	if !d.ready {
 		return ""
 	}
-	out, err := json.Marshal(d.Get())
-	if err != nil {
-		return "ERR"
+	if d.sensitive {
+		return Redacted
 	}
-	return string(out)
+	return d.cachedString(&d.jsonStr, func(v interface{}) string {
+		out, err := json.Marshal(v)
+		if err != nil {
+			return "ERR"
+		}
+		return string(out)
+	})
 }
 
 func (d *DynJSONValue) usageString() string {
@@ -4,6 +4,7 @@
 package dflag
 
 import (
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -45,6 +46,15 @@ func TestDynJSON_SetAndGet(t *testing.T) {
 		"value must be set after update")
 }
 
+func TestDynJSON_YAMLNotRegistered(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	DynJSON(set, "some_json", &outerJSON{}, "Use it or lose it")
+	err := set.Set("some_json", "string: foo\n")
+	assert.Error(t, err, "YAML needs a registered decoder")
+	var syntaxErr *json.SyntaxError
+	assert.True(t, errors.As(err, &syntaxErr), "the JSON error is returned")
+}
+
 func TestDynJSON_IsMarkedDynamic(t *testing.T) {
 	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
 	DynJSON(set, "some_json_1", defaultJSON, "Use it or lose it")
@@ -0,0 +1,34 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"flag"
+	"sync/atomic"
+)
+
+// DynTypedJSONValue is a DynJSON flag whose values are also stored as a typed pointer, read with Load()
+// without the type assertion (and interface conversion) of Get(), e.g. for hot path config reads.
+// Like for Get(), the returned value must not be modified: each set stores a new one (read-copy-update).
+type DynTypedJSONValue[T any] struct {
+	*DynJSONValue
+	ptr atomic.Pointer[T]
+}
+
+// DynTypedJSON creates a DynJSON flag whose value is a *T, T being a JSON (un)marshallable struct or slice.
+func DynTypedJSON[T any](flagSet *flag.FlagSet, name string, value *T, usage string) *DynTypedJSONValue[T] {
+	d := &DynTypedJSONValue[T]{DynJSONValue: DynJSON(flagSet, name, value, usage)}
+	d.ptr.Store(value)
+	d.onSwap = func(v interface{}) {
+		if p, ok := v.(*T); ok { // always, unless SetV is misused with another type.
+			d.ptr.Store(p)
+		}
+	}
+	return d
+}
+
+// Load returns the current value.
+func (d *DynTypedJSONValue[T]) Load() *T {
+	return d.ptr.Load()
+}
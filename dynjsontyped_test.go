@@ -0,0 +1,64 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"flag"
+	"testing"
+
+	"fortio.org/assert"
+)
+
+func TestDynTypedJSON(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	var notified *outerJSON
+	dynFlag := DynTypedJSON(set, "some_json", defaultJSON, "typed json")
+	dynFlag.WithSyncNotifier(func(_, newValue interface{}) {
+		notified = dynFlag.Load()
+		assert.True(t, newValue.(*outerJSON) == notified, "Load has the new value in notifiers")
+	})
+	assert.True(t, dynFlag.Load() == defaultJSON, "default value")
+	assert.True(t, IsFlagDynamic(set.Lookup("some_json")))
+	assert.NoError(t, set.Set("some_json", `{"ints": [42], "string": "new-value"}`))
+	assert.Equal(t, &outerJSON{FieldInts: []int{42}, FieldString: "new-value"}, dynFlag.Load())
+	assert.True(t, dynFlag.Get().(*outerJSON) == dynFlag.Load(), "same value as Get")
+	assert.True(t, notified == dynFlag.Load())
+	assert.Error(t, set.Set("some_json", `{"ints": "x"}`))
+	assert.Equal(t, "new-value", dynFlag.Load().FieldString, "unchanged on errors")
+	assert.NoError(t, dynFlag.Reset())
+	assert.True(t, dynFlag.Load() == defaultJSON)
+	arr := DynTypedJSON(set, "some_array", defaultJSONArray, "typed json array")
+	assert.NoError(t, set.Set("some_array", `[{"string": "a"}]`))
+	assert.Equal(t, "a", (*arr.Load())[0].FieldString)
+}
+
+func BenchmarkDynJSON_Get(b *testing.B) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynFlag := DynJSON(set, "some_json", defaultJSON, "")
+	var s string
+	for i := 0; i < b.N; i++ {
+		s = dynFlag.Get().(*outerJSON).FieldString
+	}
+	b.Logf("last s %v", s)
+}
+
+func BenchmarkDynTypedJSON_Load(b *testing.B) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynFlag := DynTypedJSON(set, "some_json", defaultJSON, "")
+	var s string
+	for i := 0; i < b.N; i++ {
+		s = dynFlag.Load().FieldString
+	}
+	b.Logf("last s %v", s)
+}
+
+func BenchmarkDynJSON_Set(b *testing.B) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	DynJSON(set, "some_json", defaultJSON, "")
+	input := `{"ints": [1, 2, 3], "string": "foo", "inner": {"bool": true}}`
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = set.Set("some_json", input)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dynloglevel
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"fortio.org/dflag"
+	"fortio.org/log"
+)
+
+var (
+	overridesDone bool
+	overrides     atomic.Pointer[map[string]log.Level]
+)
+
+// ParseOverrides parses per component log levels in the `component=level,...` format,
+// e.g. "http=debug,db=warning". An empty string means no overrides.
+func ParseOverrides(input string) (map[string]log.Level, error) {
+	res := make(map[string]log.Level)
+	for _, entry := range strings.Split(input, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		component, levelStr, found := strings.Cut(entry, "=")
+		component = strings.TrimSpace(component)
+		if !found || component == "" {
+			return nil, fmt.Errorf("invalid log level override %q, expecting component=level", entry)
+		}
+		level, err := log.ValidateLevel(strings.ToLower(strings.TrimSpace(levelStr)))
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", component, err)
+		}
+		res[component] = level
+	}
+	return res, nil
+}
+
+// OverridesFlagSetup sets up the `loglevel-overrides` dynamic flag (or another name if passed) setting
+// per component log levels (see ParseOverrides), so a subsystem can be made more verbose (or quieter)
+// at runtime without changing the level of the whole binary. The components use LevelFor, Log and S.
+func OverridesFlagSetup(optionalFlagName ...string) {
+	if overridesDone {
+		return
+	}
+	OverridesFlag(flag.CommandLine, optionalFlagName...)
+	overridesDone = true
+}
+
+// OverridesFlag is OverridesFlagSetup for the given FlagSet: each call defines the flag(s) and returns
+// the dynamic value bound to them. The overrides themselves are global like the log level.
+func OverridesFlag(flagSet *flag.FlagSet, optionalFlagName ...string) *dflag.DynValue[string] {
+	usage := "per component log levels overrides, e.g. `http=debug,db=warning`"
+	flag := dflag.New("", usage).WithValidator(
+		func(newStr string) error {
+			_, err := ParseOverrides(newStr)
+			return err
+		}).WithSyncNotifier(
+		func(_, newStr string) {
+			m, _ := ParseOverrides(newStr) // will succeed as we just validated it first
+			overrides.Store(&m)
+		})
+	if len(optionalFlagName) == 0 {
+		optionalFlagName = []string{"loglevel-overrides"}
+	}
+	for _, name := range optionalFlagName {
+		dflag.FlagSet(flagSet, name, flag)
+	}
+	return flag
+}
+
+// LevelFor returns the log level of component: its override if any, the global log level otherwise.
+func LevelFor(component string) log.Level {
+	if m := overrides.Load(); m != nil {
+		if level, found := (*m)[component]; found {
+			return level
+		}
+	}
+	return log.GetLogLevel()
+}
+
+// Log returns true if messages of the given level are logged for component.
+func Log(component string, lvl log.Level) bool {
+	return lvl >= LevelFor(component)
+}
+
+// S logs a message of the given level for component (added as a "component" attribute) when Log returns
+// true. Messages for a component made more verbose than the global level are logged at the global level,
+// as fortio.org/log would otherwise filter them.
+func S(component string, lvl log.Level, msg string, attrs ...log.KeyVal) {
+	if !Log(component, lvl) {
+		return
+	}
+	if global := log.GetLogLevel(); lvl < global {
+		lvl = global
+	}
+	log.S(lvl, msg, append([]log.KeyVal{log.Str("component", component)}, attrs...)...)
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dynloglevel
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"fortio.org/log"
+)
+
+func TestParseOverrides(t *testing.T) {
+	m, err := ParseOverrides(" http=DEBUG, db = warning,")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(m) != 2 || m["http"] != log.Debug || m["db"] != log.Warning {
+		t.Errorf("unexpected overrides %v", m)
+	}
+	m, err = ParseOverrides("")
+	if err != nil || len(m) != 0 {
+		t.Errorf("unexpected result for empty overrides %v %v", m, err)
+	}
+	for _, bad := range []string{"http", "=debug", "http=bogus"} {
+		if _, err = ParseOverrides(bad); err == nil {
+			t.Errorf("didn't get an error for %q", bad)
+		}
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	OverridesFlagSetup()
+	OverridesFlagSetup() // no harm in calling it twice
+	prev := log.SetLogLevel(log.Info)
+	defer log.SetLogLevel(prev)
+	if LevelFor("http") != log.Info {
+		t.Errorf("expected the global level without overrides, got %v", LevelFor("http"))
+	}
+	if err := flag.CommandLine.Set("loglevel-overrides", "http=debug,db=error"); err != nil {
+		t.Fatalf("unexpected error for valid overrides %v", err)
+	}
+	if err := flag.CommandLine.Set("loglevel-overrides", "http=bogus"); err == nil {
+		t.Errorf("didn't get an error setting bogus overrides")
+	}
+	if LevelFor("http") != log.Debug || LevelFor("db") != log.Error || LevelFor("other") != log.Info {
+		t.Errorf("unexpected levels %v %v %v", LevelFor("http"), LevelFor("db"), LevelFor("other"))
+	}
+	if !Log("http", log.Debug) || Log("db", log.Warning) || Log("other", log.Debug) {
+		t.Errorf("unexpected Log() results")
+	}
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	S("http", log.Debug, "http debug")
+	S("db", log.Warning, "db warning")
+	out := buf.String()
+	if !strings.Contains(out, "http debug") || !strings.Contains(out, "component") {
+		t.Errorf("missing the http debug message: %q", out)
+	}
+	if strings.Contains(out, "db warning") {
+		t.Errorf("db warning should have been filtered: %q", out)
+	}
+	if err := flag.CommandLine.Set("loglevel-overrides", ""); err != nil || LevelFor("http") != log.Info {
+		t.Errorf("overrides not cleared %v %v", err, LevelFor("http"))
+	}
+}
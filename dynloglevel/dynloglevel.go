@@ -22,6 +22,8 @@ import (
 	"flag"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"fortio.org/dflag"
 	"fortio.org/log"
@@ -29,34 +31,102 @@ import (
 
 var done = false
 
+// levelBoost tracks the pending revert of a level set with a duration.
+type levelBoost struct {
+	mu         sync.Mutex
+	timer      *time.Timer
+	generation int
+	revertTo   string // level before the first of consecutive boosts.
+}
+
+// parseLevelTTL splits and validates a `level[:duration]` value.
+func parseLevelTTL(input string) (string, time.Duration, error) {
+	level, ttlStr, hasTTL := strings.Cut(input, ":")
+	if _, err := log.ValidateLevel(level); err != nil {
+		return "", 0, err
+	}
+	if !hasTTL {
+		return level, 0, nil
+	}
+	ttl, err := time.ParseDuration(ttlStr)
+	if err != nil {
+		return "", 0, err
+	}
+	if ttl <= 0 {
+		return "", 0, fmt.Errorf("log level duration %v must be positive", ttl)
+	}
+	return level, ttl, nil
+}
+
+// set changes the log level and schedules the revert when newStr has a duration.
+func (b *levelBoost) set(flag *dflag.DynValue[string], oldStr, newStr string) {
+	level, ttl, _ := parseLevelTTL(newStr) // will succeed as we just validated it first
+	_ = log.SetLogLevelStr(level)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.generation++
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if ttl == 0 {
+		b.revertTo = ""
+		return
+	}
+	if b.revertTo == "" {
+		b.revertTo, _, _ = parseLevelTTL(oldStr)
+	}
+	generation, revertTo := b.generation, b.revertTo
+	b.timer = time.AfterFunc(ttl, func() {
+		b.mu.Lock()
+		current := generation == b.generation
+		b.mu.Unlock()
+		if current {
+			log.S(log.Info, "Reverting temporary log level", log.Str("level", revertTo))
+			_ = flag.Set(revertTo) // through the flag for its value to reflect the level.
+		}
+	})
+}
+
 // LoggerFlagSetup sets up the `loglevel` flag as a dynamic flag
 // (or another name if desired/passed).
+// A duration can be appended to the level, e.g. `debug:10m`, for the previous level
+// to be restored after that time (so debug logging isn't left on by mistake).
 func LoggerFlagSetup(optionalFlagName ...string) {
 	if done {
 		return // avoid redefining flag/make it ok for multiple function to init this.
 	}
+	LogLevelFlag(flag.CommandLine, optionalFlagName...)
+	done = true
+}
+
+// LogLevelFlag is LoggerFlagSetup for the given FlagSet, without global state: each call defines
+// the flag(s) (so only call it once per FlagSet) and returns the dynamic value bound to them.
+func LogLevelFlag(flagSet *flag.FlagSet, optionalFlagName ...string) *dflag.DynValue[string] {
 	// virtual dynLevel flag that maps back to actual level
 	defVal := log.GetLogLevel().String()
-	usage := fmt.Sprintf("log `level`, one of %v", log.LevelToStrA)
-	flag := dflag.New(defVal, usage).WithInputMutator(
+	usage := fmt.Sprintf("log `level`, one of %v, optionally followed by :duration to revert after it", log.LevelToStrA)
+	boost := &levelBoost{}
+	var flag *dflag.DynValue[string]
+	flag = dflag.New(defVal, usage).WithInputMutator(
 		func(inp string) string {
 			// The validation map has full lowercase and capitalized first letter version
 			return strings.ToLower(strings.TrimSpace(inp))
 		}).WithValidator(
 		func(newStr string) error {
-			_, err := log.ValidateLevel(newStr)
+			_, _, err := parseLevelTTL(newStr)
 			return err
 		}).WithSyncNotifier(
-		func(_, newStr string) {
-			_ = log.SetLogLevelStr(newStr) // will succeed as we just validated it first
+		func(oldStr, newStr string) {
+			boost.set(flag, oldStr, newStr)
 		})
 	if len(optionalFlagName) == 0 {
 		optionalFlagName = []string{"loglevel"}
 	}
 	for _, name := range optionalFlagName {
-		dflag.Flag(name, flag)
+		dflag.FlagSet(flagSet, name, flag)
 	}
-	done = true
+	return flag
 }
 
 // ChangeFlagsDefault sets some flags to a different default.
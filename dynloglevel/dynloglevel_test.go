@@ -17,6 +17,7 @@ package dynloglevel
 import (
 	"flag"
 	"testing"
+	"time"
 
 	"fortio.org/log"
 )
@@ -63,6 +64,61 @@ func TestMultipleFlagNames(t *testing.T) {
 	LoggerFlagSetup()
 }
 
+func TestLogLevelFlag(t *testing.T) {
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	dyn := LogLevelFlag(flagSet)
+	if flagSet.Lookup("loglevel") == nil {
+		t.Fatalf("flag not defined in the FlagSet")
+	}
+	_ = log.SetLogLevel(log.Info)
+	if err := flagSet.Parse([]string{"-loglevel", "ERROR"}); err != nil {
+		t.Fatalf("unexpected error for valid level %v", err)
+	}
+	if dyn.Get() != "error" || log.GetLogLevel() != log.Error {
+		t.Errorf("unexpected value %q / level %v", dyn.Get(), log.GetLogLevel())
+	}
+	_ = log.SetLogLevel(log.Info)
+	// no global state: another FlagSet gets its own flag.
+	other := flag.NewFlagSet("other", flag.ContinueOnError)
+	if LogLevelFlag(other, "lvl") == dyn || other.Lookup("lvl") == nil {
+		t.Errorf("expected a new flag for the other FlagSet")
+	}
+}
+
+func TestTemporaryLevel(t *testing.T) {
+	done = false
+	LoggerFlagSetup("ttl-level")
+	_ = log.SetLogLevel(log.Info)
+	if err := flag.CommandLine.Set("ttl-level", "info"); err != nil {
+		t.Fatalf("unexpected error for valid level %v", err)
+	}
+	for _, bad := range []string{"debug:bogus", "debug:-1s", "bogus:1s"} {
+		if err := flag.CommandLine.Set("ttl-level", bad); err == nil {
+			t.Errorf("Didn't get an error setting %q", bad)
+		}
+	}
+	if err := flag.CommandLine.Set("ttl-level", "warning:1h"); err != nil {
+		t.Fatalf("unexpected error for valid level with duration %v", err)
+	}
+	if log.GetLogLevel() != log.Warning {
+		t.Errorf("unexpected level %v", log.GetLogLevel())
+	}
+	// a second boost replaces the first one and still reverts to the level before both.
+	if err := flag.CommandLine.Set("ttl-level", "Debug:50ms"); err != nil {
+		t.Fatalf("unexpected error for valid level with duration %v", err)
+	}
+	if log.GetLogLevel() != log.Debug {
+		t.Errorf("unexpected level %v", log.GetLogLevel())
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for flag.Lookup("ttl-level").Value.String() != "info" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if v := flag.Lookup("ttl-level").Value.String(); v != "info" || log.GetLogLevel() != log.Info {
+		t.Errorf("level not reverted: flag %q level %v", v, log.GetLogLevel())
+	}
+}
+
 func TestChangeFlagsDefaultErrCase1(t *testing.T) {
 	defer func() {
 		if r := recover(); r == nil {
@@ -0,0 +1,68 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dynloglevel
+
+import (
+	"flag"
+	"strings"
+
+	"fortio.org/dflag"
+	"fortio.org/log"
+)
+
+// Log formats and color modes accepted by the flags of FormatFlags.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+
+	ColorAuto   = "auto"   // color when logging to a console (log.Config.ConsoleColor).
+	ColorAlways = "always" // log.Config.ForceColor.
+	ColorNever  = "never"
+)
+
+var formatDone = false
+
+// FormatFlagSetup sets up the `logformat` and `logcolor` dynamic flags on the command line, see FormatFlags.
+func FormatFlagSetup() {
+	if formatDone {
+		return
+	}
+	FormatFlags(flag.CommandLine)
+	formatDone = true
+}
+
+// FormatFlags defines in flagSet the `logformat` (json or text) and `logcolor` (auto, always or never)
+// dynamic flags, defaulting to the current fortio.org/log configuration, so the output can be switched
+// at runtime between machine ingestion and human debugging. Note that fortio.org/log reads its Config
+// without synchronization, so messages logged while switching may use either format.
+func FormatFlags(flagSet *flag.FlagSet) (format, color *dflag.DynValue[string]) {
+	lower := func(inp string) string { return strings.ToLower(strings.TrimSpace(inp)) }
+	defFormat := FormatText
+	if log.Config.JSON {
+		defFormat = FormatJSON
+	}
+	format = dflag.New(defFormat, "log `format`, json or text (color mode, when enabled, takes precedence)").
+		WithInputMutator(lower).WithOneOf(FormatJSON, FormatText).WithSyncNotifier(
+		func(_, newStr string) {
+			log.Config.JSON = newStr == FormatJSON
+			log.SetColorMode()
+		})
+	defColor := ColorNever
+	switch {
+	case log.Config.ForceColor:
+		defColor = ColorAlways
+	case log.Config.ConsoleColor:
+		defColor = ColorAuto
+	}
+	color = dflag.New(defColor, "log `color` mode, auto (when logging to a console), always or never").
+		WithInputMutator(lower).WithOneOf(ColorAuto, ColorAlways, ColorNever).WithSyncNotifier(
+		func(_, newStr string) {
+			log.Config.ConsoleColor = newStr == ColorAuto
+			log.Config.ForceColor = newStr == ColorAlways
+			log.SetColorMode()
+		})
+	dflag.FlagSet(flagSet, "logformat", format)
+	dflag.FlagSet(flagSet, "logcolor", color)
+	return format, color
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dynloglevel
+
+import (
+	"flag"
+	"testing"
+
+	"fortio.org/log"
+)
+
+func TestFormatFlags(t *testing.T) {
+	prev := *log.Config
+	defer func() {
+		*log.Config = prev
+		log.SetColorMode()
+	}()
+	log.Config.JSON = true
+	log.Config.ForceColor = false
+	log.Config.ConsoleColor = true
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	format, color := FormatFlags(flagSet)
+	if format.Get() != FormatJSON || color.Get() != ColorAuto {
+		t.Errorf("unexpected defaults %q %q", format.Get(), color.Get())
+	}
+	if err := flagSet.Parse([]string{"-logformat", " Text ", "-logcolor", "always"}); err != nil {
+		t.Fatalf("unexpected error for valid values %v", err)
+	}
+	if log.Config.JSON || !log.Config.ForceColor || log.Config.ConsoleColor || !log.Color {
+		t.Errorf("unexpected config %+v color %v", log.Config, log.Color)
+	}
+	if err := flagSet.Set("logcolor", "never"); err != nil {
+		t.Fatalf("unexpected error for valid value %v", err)
+	}
+	if log.Config.ForceColor || log.Config.ConsoleColor || log.Color {
+		t.Errorf("unexpected config %+v color %v", log.Config, log.Color)
+	}
+	if err := flagSet.Set("logformat", "xml"); err == nil {
+		t.Errorf("Didn't get an error setting bogus format")
+	}
+	if err := flagSet.Set("logcolor", "sometimes"); err == nil {
+		t.Errorf("Didn't get an error setting bogus color mode")
+	}
+	FormatFlagSetup()
+	FormatFlagSetup() // no harm in calling it twice
+	if flag.Lookup("logformat") == nil || flag.Lookup("logcolor") == nil {
+		t.Errorf("flags not defined on the command line")
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dynloglevel
+
+import (
+	"fmt"
+
+	"fortio.org/dflag"
+	"fortio.org/log"
+)
+
+// fortioLogger is the dflag.Logger logging through fortio.org/log.
+type fortioLogger struct{}
+
+// FortioLogger returns the dflag.Logger logging through fortio.org/log, the default of the configmap
+// Updater and the endpoint.
+func FortioLogger() dflag.Logger {
+	return fortioLogger{}
+}
+
+func (fortioLogger) Debug(msg string, args ...any) { logS(log.Debug, msg, args) }
+func (fortioLogger) Info(msg string, args ...any)  { logS(log.Info, msg, args) }
+func (fortioLogger) Warn(msg string, args ...any)  { logS(log.Warning, msg, args) }
+func (fortioLogger) Error(msg string, args ...any) { logS(log.Error, msg, args) }
+
+// logS converts the slog style alternating keys and values to attributes: like slog, a key that isn't
+// a string or is missing its value becomes a "!BADKEY" attribute.
+func logS(lvl log.Level, msg string, args []any) {
+	if !log.Log(lvl) {
+		return
+	}
+	attrs := make([]log.KeyVal, 0, (len(args)+1)/2)
+	for len(args) > 0 {
+		key, ok := args[0].(string)
+		if !ok || len(args) == 1 {
+			attrs = append(attrs, log.Any("!BADKEY", args[0]))
+			args = args[1:]
+			continue
+		}
+		attrs = append(attrs, attr(key, args[1]))
+		args = args[2:]
+	}
+	log.S(lvl, msg, attrs...)
+}
+
+// attr logs the Stringers (e.g. durations) as their string rather than as JSON.
+func attr(key string, value any) log.KeyVal {
+	if _, isErr := value.(error); !isErr {
+		if s, ok := value.(fmt.Stringer); ok {
+			return log.Str(key, s.String())
+		}
+	}
+	return log.Any(key, value)
+}
@@ -0,0 +1,37 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dynloglevel
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"fortio.org/log"
+)
+
+func TestFortioLogger(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	prev := log.SetLogLevelQuiet(log.Info)
+	defer log.SetLogLevelQuiet(prev)
+	l := FortioLogger()
+	l.Debug("not logged", "a", 1)
+	l.Info("some info", "dir", "/etc/config", "count", 2, "retry_in", time.Second)
+	l.Error("some error", "error", errors.New("boom"), 42)
+	out := buf.String()
+	if strings.Contains(out, "not logged") {
+		t.Errorf("debug should have been filtered: %q", out)
+	}
+	for _, expected := range []string{"some info", `"dir":"/etc/config"`, `"count":2`, `"retry_in":"1s"`,
+		"some error", `"error":"boom"`, `"!BADKEY":42`} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("missing %s in %q", expected, out)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Package dynruntime sets up dynamic flags tuning the Go runtime (GOGC, GOMEMLIMIT, GOMAXPROCS),
+// so it can be adjusted without restarts, e.g. from a ConfigMap or the endpoint.
+package dynruntime
+
+import (
+	"flag"
+	"math"
+	"runtime"
+	"runtime/debug"
+
+	"fortio.org/dflag"
+	"fortio.org/log"
+)
+
+var done = false
+
+// FlagSetup sets up the `gogc`, `gomemlimit` and `gomaxprocs` dynamic flags on the command line,
+// see RuntimeFlags.
+func FlagSetup() {
+	if done {
+		return
+	}
+	RuntimeFlags(flag.CommandLine)
+	done = true
+}
+
+// RuntimeFlags defines in flagSet the dynamic flags:
+//   - `gogc`: debug.SetGCPercent (a negative value disables the GC)
+//   - `gomemlimit`: debug.SetMemoryLimit, in bytes (math.MaxInt64 for no limit)
+//   - `gomaxprocs`: runtime.GOMAXPROCS
+//
+// Their defaults are the current settings (e.g. from the GOGC, GOMEMLIMIT and GOMAXPROCS environment variables).
+func RuntimeFlags(flagSet *flag.FlagSet) (gogc, memLimit, maxProcs *dflag.DynValue[int64]) {
+	current := debug.SetGCPercent(100)
+	debug.SetGCPercent(current)
+	gogc = dflag.New(int64(current), "garbage collection target `percentage`, negative to disable it").
+		WithSyncNotifier(func(_, newValue int64) {
+			prev := debug.SetGCPercent(int(newValue))
+			log.S(log.Info, "GC percent changed", log.Int("from", prev), log.Int64("to", newValue))
+		})
+	memLimit = dflag.New(debug.SetMemoryLimit(-1), "runtime soft memory limit in `bytes` (max int64 for none)").
+		WithRange(0, math.MaxInt64).WithSyncNotifier(func(_, newValue int64) {
+		prev := debug.SetMemoryLimit(newValue)
+		log.S(log.Info, "Memory limit changed", log.Int64("from", prev), log.Int64("to", newValue))
+	})
+	maxProcs = dflag.New(int64(runtime.GOMAXPROCS(0)), "`number` of CPUs executing Go code simultaneously").
+		WithRange(1, math.MaxInt32).WithSyncNotifier(func(_, newValue int64) {
+		prev := runtime.GOMAXPROCS(int(newValue))
+		log.S(log.Info, "GOMAXPROCS changed", log.Int("from", prev), log.Int64("to", newValue))
+	})
+	dflag.FlagSet(flagSet, "gogc", gogc)
+	dflag.FlagSet(flagSet, "gomemlimit", memLimit)
+	dflag.FlagSet(flagSet, "gomaxprocs", maxProcs)
+	return gogc, memLimit, maxProcs
+}
@@ -0,0 +1,39 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dynruntime
+
+import (
+	"flag"
+	"runtime"
+	"runtime/debug"
+	"testing"
+
+	"fortio.org/assert"
+)
+
+func TestRuntimeFlags(t *testing.T) {
+	prevGC := debug.SetGCPercent(100)
+	prevLimit := debug.SetMemoryLimit(-1)
+	prevProcs := runtime.GOMAXPROCS(0)
+	defer func() {
+		debug.SetGCPercent(prevGC)
+		debug.SetMemoryLimit(prevLimit)
+		runtime.GOMAXPROCS(prevProcs)
+	}()
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	gogc, memLimit, maxProcs := RuntimeFlags(flagSet)
+	assert.Equal(t, int64(100), gogc.Get())
+	assert.Equal(t, prevLimit, memLimit.Get())
+	assert.Equal(t, int64(prevProcs), maxProcs.Get())
+	assert.NoError(t, flagSet.Parse([]string{"-gogc=50", "-gomemlimit=1073741824", "-gomaxprocs=1"}))
+	assert.Equal(t, 50, debug.SetGCPercent(50))
+	assert.Equal(t, int64(1<<30), debug.SetMemoryLimit(-1))
+	assert.Equal(t, 1, runtime.GOMAXPROCS(0))
+	assert.Error(t, flagSet.Set("gomaxprocs", "0"))
+	assert.Error(t, flagSet.Set("gomemlimit", "-1"))
+	assert.Equal(t, 1, runtime.GOMAXPROCS(0), "unchanged by invalid values")
+	FlagSetup()
+	FlagSetup() // no harm in calling it twice
+	assert.True(t, flag.Lookup("gogc") != nil, "flag defined on the command line")
+}
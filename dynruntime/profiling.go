@@ -0,0 +1,48 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dynruntime
+
+import (
+	"flag"
+	"math"
+	"runtime"
+
+	"fortio.org/dflag"
+	"fortio.org/log"
+)
+
+var profilingDone = false
+
+// ProfilingFlagSetup sets up the `blockprofilerate` and `mutexprofilefraction` dynamic flags on the
+// command line, see ProfilingFlags.
+func ProfilingFlagSetup() {
+	if profilingDone {
+		return
+	}
+	ProfilingFlags(flag.CommandLine)
+	profilingDone = true
+}
+
+// ProfilingFlags defines in flagSet the dynamic flags:
+//   - `blockprofilerate`: runtime.SetBlockProfileRate, in nanoseconds blocked per sampled event (0 is off,
+//     1 records every blocking event)
+//   - `mutexprofilefraction`: runtime.SetMutexProfileFraction, 1/rate of the contention events reported (0 is off)
+//
+// so the block and mutex pprof profiles can be turned on temporarily, e.g. during an incident, as they
+// slow down the program. The block rate defaults to 0 as the runtime doesn't expose its current value.
+func ProfilingFlags(flagSet *flag.FlagSet) (blockRate, mutexFraction *dflag.DynValue[int64]) {
+	blockRate = dflag.New(int64(0), "block profile `rate` in nanoseconds, 0 to disable").
+		WithRange(0, math.MaxInt32).WithSyncNotifier(func(_, newValue int64) {
+		runtime.SetBlockProfileRate(int(newValue))
+		log.S(log.Info, "Block profile rate changed", log.Int64("rate", newValue))
+	})
+	mutexFraction = dflag.New(int64(runtime.SetMutexProfileFraction(-1)), "mutex profile `fraction`, 0 to disable").
+		WithRange(0, math.MaxInt32).WithSyncNotifier(func(_, newValue int64) {
+		prev := runtime.SetMutexProfileFraction(int(newValue))
+		log.S(log.Info, "Mutex profile fraction changed", log.Int("from", prev), log.Int64("to", newValue))
+	})
+	dflag.FlagSet(flagSet, "blockprofilerate", blockRate)
+	dflag.FlagSet(flagSet, "mutexprofilefraction", mutexFraction)
+	return blockRate, mutexFraction
+}
@@ -0,0 +1,34 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dynruntime
+
+import (
+	"flag"
+	"runtime"
+	"testing"
+
+	"fortio.org/assert"
+)
+
+func TestProfilingFlags(t *testing.T) {
+	prevMutex := runtime.SetMutexProfileFraction(-1)
+	defer func() {
+		runtime.SetBlockProfileRate(0)
+		runtime.SetMutexProfileFraction(prevMutex)
+	}()
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	blockRate, mutexFraction := ProfilingFlags(flagSet)
+	assert.Equal(t, int64(0), blockRate.Get())
+	assert.Equal(t, int64(prevMutex), mutexFraction.Get())
+	assert.NoError(t, flagSet.Parse([]string{"-blockprofilerate=1", "-mutexprofilefraction=5"}))
+	assert.Equal(t, int64(1), blockRate.Get())
+	assert.Equal(t, 5, runtime.SetMutexProfileFraction(-1))
+	assert.Error(t, flagSet.Set("mutexprofilefraction", "-1"))
+	assert.Error(t, flagSet.Set("blockprofilerate", "-5"))
+	assert.NoError(t, flagSet.Set("mutexprofilefraction", "0"))
+	assert.Equal(t, 0, runtime.SetMutexProfileFraction(-1))
+	ProfilingFlagSetup()
+	ProfilingFlagSetup() // no harm in calling it twice
+	assert.True(t, flag.Lookup("blockprofilerate") != nil, "flag defined on the command line")
+}
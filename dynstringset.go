@@ -26,14 +26,17 @@ type DynStringSetValue struct {
 
 // Contains returns whether the specified string is in the flag.
 func (d *DynStringSetValue) Contains(val string) bool {
-	v := d.Get()
+	v := d.load()
 	_, ok := v[val]
 	return ok
 }
 
 // String represents the canonical representation of the type.
 func (d *DynStringSetValue) String() string {
-	v := d.Get()
+	if d.sensitive {
+		return Redacted
+	}
+	v := d.load()
 	arr := make([]string, 0, len(v))
 	for k := range v {
 		arr = append(arr, k)
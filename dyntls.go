@@ -0,0 +1,115 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrNoCertificate is returned by GetCertificate (and GetClientCertificate) until a valid
+// certificate and key pair has been loaded.
+var ErrNoCertificate = errors.New("dflag: no tls certificate loaded yet")
+
+// DynCertificate maintains a current *tls.Certificate from a pair of dynamic binary flags
+// holding the PEM encoded certificate (chain) and private key. Use it as tls.Config's
+// GetCertificate so servers pick up rotated certificates without a restart, for instance
+// when a configmap.Updater watches a mounted Kubernetes tls Secret (tls.crt and tls.key).
+type DynCertificate struct {
+	certFlag *DynValue[[]byte]
+	keyFlag  *DynValue[[]byte]
+	mu       sync.Mutex // serializes reloads and protects err.
+	err      error
+	cert     atomic.Pointer[tls.Certificate]
+	notifier func(*tls.Certificate)
+}
+
+// DynTLSCertificate creates the certName and keyName binary flags and returns the
+// DynCertificate tracking them. The pair is reloaded every time either flag changes;
+// a new certificate that doesn't match the current key (e.g. the certificate file was
+// updated but not yet the key) is not an error for the flag, the previous pair keeps
+// being served until both halves match, see Err().
+func DynTLSCertificate(flagSet *flag.FlagSet, certName, keyName, usage string) *DynCertificate {
+	d := &DynCertificate{}
+	d.certFlag = Dyn(flagSet, certName, []byte{}, usage+" (PEM certificate)").
+		WithValidator(validatePEM).WithSyncNotifier(d.onChange)
+	d.keyFlag = Dyn(flagSet, keyName, []byte{}, usage+" (PEM private key)").
+		WithValidator(validatePEM).WithSyncNotifier(d.onChange)
+	return d
+}
+
+// validatePEM accepts empty values (no certificate yet) or ones containing at least one PEM block.
+func validatePEM(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if block, _ := pem.Decode(data); block == nil {
+		return errors.New("no PEM data found")
+	}
+	return nil
+}
+
+// WithNotifier adds a function called synchronously every time a new certificate is loaded.
+func (d *DynCertificate) WithNotifier(notifier func(cert *tls.Certificate)) *DynCertificate {
+	d.mu.Lock()
+	d.notifier = notifier
+	d.mu.Unlock()
+	return d
+}
+
+func (d *DynCertificate) onChange(_, _ []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	certPEM, keyPEM := d.certFlag.load(), d.keyFlag.load()
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		d.err = ErrNoCertificate
+		return
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		d.err = fmt.Errorf("dflag: loading tls certificate: %w", err)
+		return
+	}
+	if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+		d.err = fmt.Errorf("dflag: parsing tls certificate: %w", err)
+		return
+	}
+	d.err = nil
+	d.cert.Store(&cert)
+	if d.notifier != nil {
+		d.notifier(&cert)
+	}
+}
+
+// Certificate returns the current certificate, nil if none was loaded yet.
+func (d *DynCertificate) Certificate() *tls.Certificate {
+	return d.cert.Load()
+}
+
+// Err returns the error from the last reload attempt, nil if the flags' current
+// values are the certificate being served.
+func (d *DynCertificate) Err() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.err
+}
+
+// GetCertificate can be used as tls.Config's GetCertificate.
+func (d *DynCertificate) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if cert := d.cert.Load(); cert != nil {
+		return cert, nil
+	}
+	return nil, ErrNoCertificate
+}
+
+// GetClientCertificate can be used as tls.Config's GetClientCertificate for mTLS clients.
+func (d *DynCertificate) GetClientCertificate(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return d.GetCertificate(nil)
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"math/big"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+)
+
+func genCertPEM(t *testing.T, cn string) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer})
+}
+
+func TestDynTLSCertificate(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	var notified []string
+	d := DynTLSCertificate(set, "tls.crt", "tls.key", "server").WithNotifier(func(c *tls.Certificate) {
+		notified = append(notified, c.Leaf.Subject.CommonName)
+	})
+	_, err := d.GetCertificate(nil)
+	assert.True(t, errors.Is(err, ErrNoCertificate), "no certificate error expected")
+	assert.True(t, d.Certificate() == nil, "no certificate yet")
+	assert.Error(t, set.Set("tls.crt", base64.StdEncoding.EncodeToString([]byte("not pem"))), "non PEM should be rejected")
+	cert1, key1 := genCertPEM(t, "one")
+	cert2, key2 := genCertPEM(t, "two")
+	assert.NoError(t, set.Set("tls.crt", base64.StdEncoding.EncodeToString(cert1)))
+	assert.True(t, errors.Is(d.Err(), ErrNoCertificate), "key still missing")
+	assert.NoError(t, set.Set("tls.key", base64.StdEncoding.EncodeToString(key1)))
+	assert.NoError(t, d.Err())
+	c, err := d.GetClientCertificate(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "one", c.Leaf.Subject.CommonName)
+	// rotation: certificate first, mismatched with the current key, keeps serving the old pair.
+	assert.NoError(t, IsBinary(set.Lookup("tls.crt")).SetV(cert2))
+	assert.Error(t, d.Err(), "mismatched pair")
+	assert.Equal(t, "one", d.Certificate().Leaf.Subject.CommonName)
+	assert.NoError(t, IsBinary(set.Lookup("tls.key")).SetV(key2))
+	assert.NoError(t, d.Err())
+	c, err = d.GetCertificate(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "two", c.Leaf.Subject.CommonName)
+	assert.Equal(t, []string{"one", "two"}, notified)
+}
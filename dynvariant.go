@@ -0,0 +1,122 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"sync"
+)
+
+// Variant is one of the weighted variants of an Experiment.
+type Variant struct {
+	Name string `json:"name"`
+	// Weight is relative to the other variants' (e.g. 90 and 10, or 1 and 1 for an even split).
+	Weight int `json:"weight"`
+}
+
+// Experiment is the configuration of a DynVariant flag, e.g.
+//
+//	{"salt": "v2", "variants": [{"name": "control", "weight": 90}, {"name": "new_ui", "weight": 10}]}
+type Experiment struct {
+	// Salt changes all the assignments when changed (e.g. to restart an experiment with new groups).
+	Salt     string    `json:"salt,omitempty"`
+	Variants []Variant `json:"variants"`
+}
+
+// DynVariantValue is a DynTypedJSON flag assigning keys (user ids, sessions,...) to the weighted variants
+// of an Experiment, see Variant.
+type DynVariantValue struct {
+	*DynTypedJSONValue[Experiment]
+	exposureMu sync.Mutex
+	exposures  []func(key, variant string)
+}
+
+// DynVariant creates an A/B experiment flag whose value is the JSON of an Experiment.
+// The values (including the default) must have at least one variant, distinct names and positive weights.
+func DynVariant(flagSet *flag.FlagSet, name string, value *Experiment, usage string) *DynVariantValue {
+	if err := validateExperiment(value); err != nil {
+		panic(err)
+	}
+	d := &DynVariantValue{DynTypedJSONValue: DynTypedJSON(flagSet, name, value, usage)}
+	d.WithValidator(func(v interface{}) error {
+		return validateExperiment(v.(*Experiment))
+	})
+	return d
+}
+
+func validateExperiment(e *Experiment) error {
+	if e == nil || len(e.Variants) == 0 {
+		return errors.New("dflag: experiment has no variants")
+	}
+	seen := make(map[string]bool, len(e.Variants))
+	for _, v := range e.Variants {
+		if v.Weight <= 0 {
+			return fmt.Errorf("dflag: variant %q weight %d must be positive", v.Name, v.Weight)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("dflag: duplicate variant %q", v.Name)
+		}
+		seen[v.Name] = true
+	}
+	return nil
+}
+
+// WithExposure adds a function called (synchronously) with each assignment returned by Variant, e.g. to
+// log the exposures for the experiment's analysis.
+func (d *DynVariantValue) WithExposure(fn func(key, variant string)) *DynVariantValue {
+	d.exposureMu.Lock()
+	d.exposures = append(d.exposures, fn)
+	d.exposureMu.Unlock()
+	return d
+}
+
+// Variant returns the name of the variant the key is assigned to: the same for a given key as long as the
+// experiment's salt and variants are unchanged (and the flag's name, which is part of the hash so experiments
+// are independent). Changing the weights (in the same variants order) only moves the keys needed from one
+// variant to another, e.g. going from 90/10 to 80/20 keeps the 10% already in the second one. The exposure
+// functions are called.
+func (d *DynVariantValue) Variant(key string) string {
+	variant := d.assign(key)
+	d.exposureMu.Lock()
+	exposures := d.exposures
+	d.exposureMu.Unlock()
+	for _, fn := range exposures {
+		fn(key, variant)
+	}
+	return variant
+}
+
+// assign returns the variant of key in the current experiment.
+func (d *DynVariantValue) assign(key string) string {
+	e := d.Load()
+	total := 0
+	for _, v := range e.Variants {
+		total += v.Weight
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(d.flagName + "\x00" + e.Salt + "\x00" + key))
+	// position of the key in [0, total), the same fraction of the total for any weights.
+	pos := float64(mix64(h.Sum64())>>11) / (1 << 53) * float64(total)
+	for _, v := range e.Variants {
+		if pos < float64(v.Weight) {
+			return v.Name
+		}
+		pos -= float64(v.Weight)
+	}
+	return e.Variants[len(e.Variants)-1].Name // rounding.
+}
+
+// mix64 is murmur3's finalizer: FNV's high bits are too similar for keys differing only at the end
+// (e.g. sequential ids).
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
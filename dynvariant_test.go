@@ -0,0 +1,83 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"fortio.org/assert"
+)
+
+func TestDynVariant(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	exp := DynVariant(set, "new_ui", &Experiment{Variants: []Variant{{Name: "control", Weight: 1}}}, "new UI experiment")
+	var exposed []string
+	exp.WithExposure(func(key, variant string) { exposed = append(exposed, key+"="+variant) })
+	assert.Equal(t, "control", exp.Variant("user1"))
+	assert.Equal(t, []string{"user1=control"}, exposed)
+	assert.NoError(t, set.Set("new_ui", `{"variants": [{"name": "control", "weight": 90}, {"name": "new", "weight": 10}]}`))
+	counts := map[string]int{}
+	before := map[string]string{}
+	for i := 0; i < 10000; i++ {
+		key := "user" + strconv.Itoa(i)
+		v := exp.Variant(key)
+		assert.Equal(t, v, exp.Variant(key), "stable")
+		counts[v]++
+		before[key] = v
+	}
+	assert.True(t, counts["new"] > 800 && counts["new"] < 1200, fmt.Sprintf("~10%%: %v", counts))
+	assert.NoError(t, set.Set("new_ui", `{"variants": [{"name": "control", "weight": 80}, {"name": "new", "weight": 20}]}`))
+	moved := 0
+	for key, v := range before {
+		now := exp.assign(key)
+		if v == "new" {
+			assert.Equal(t, "new", now, "already exposed keys stay")
+		}
+		if now != v {
+			moved++
+		}
+	}
+	assert.True(t, moved > 800 && moved < 1200, fmt.Sprintf("only ~10%% moved: %d", moved))
+	assert.NoError(t, set.Set("new_ui", `{"salt": "v2", "variants": [{"name": "control", "weight": 80}, {"name": "new", "weight": 20}]}`))
+	changed := 0
+	for key, v := range before {
+		if exp.assign(key) != v {
+			changed++
+		}
+	}
+	assert.True(t, changed > 1000, fmt.Sprintf("new salt, new groups: %d", changed))
+	other := DynVariant(set, "other_exp", &Experiment{Variants: []Variant{{Name: "a", Weight: 1}, {Name: "b", Weight: 1}}}, "")
+	same := 0
+	for i := 0; i < 1000; i++ {
+		key := "user" + strconv.Itoa(i)
+		if (other.assign(key) == "a") == (exp.assign(key) == "control") {
+			same++
+		}
+	}
+	assert.True(t, same > 400 && same < 600, fmt.Sprintf("independent experiments: %d", same))
+	for _, bad := range []string{
+		`{"variants": []}`,
+		`{"variants": [{"name": "a", "weight": 0}]}`,
+		`{"variants": [{"name": "a", "weight": 1}, {"name": "a", "weight": 2}]}`,
+	} {
+		assert.Error(t, set.Set("new_ui", bad), bad)
+	}
+	assert.Equal(t, "v2", exp.Load().Salt, "unchanged by the invalid sets")
+	defer func() {
+		assert.True(t, recover() != nil, "invalid default should panic")
+	}()
+	DynVariant(set, "bad", &Experiment{}, "")
+}
+
+func BenchmarkDynVariant(b *testing.B) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	exp := DynVariant(set, "exp", &Experiment{Variants: []Variant{{Name: "a", Weight: 50}, {Name: "b", Weight: 50}}}, "")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = exp.Variant("some-user-id")
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"fortio.org/dflag"
+	"fortio.org/dflag/dynloglevel"
+)
+
+// AuditEntry records one attempt at changing a flag through SetFlag.
+type AuditEntry struct {
+	Time       time.Time `json:"time"`
+	RemoteAddr string    `json:"remote_addr"`
+	// User is the authenticated user, see WithAuditUser.
+	User string `json:"user,omitempty"`
+	Flag string `json:"flag"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+	// Success is whether the flag was set, Error the reason if it wasn't.
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// AuditFunc receives the audit entries, see WithAudit.
+type AuditFunc func(entry AuditEntry)
+
+// LogAudit is the default AuditFunc, logging the entries (to the endpoint's logger, see WithLogger).
+func LogAudit(entry AuditEntry) {
+	logAudit(dynloglevel.FortioLogger(), entry)
+}
+
+func logAudit(logger dflag.Logger, entry AuditEntry) {
+	logf := logger.Info
+	if !entry.Success {
+		logf = logger.Warn
+	}
+	logf("dflag audit", "flag", entry.Flag, "old", entry.Old, "new", entry.New, "success", entry.Success,
+		"error", entry.Error, "user", entry.User, "remote_addr", entry.RemoteAddr)
+}
+
+// WithAudit replaces the default LogAudit sink of the set attempts' audit entries (e.g. to send them
+// to an external audit system).
+func (e *FlagsEndpoint) WithAudit(sink AuditFunc) *FlagsEndpoint {
+	e.auditSink = sink
+	return e
+}
+
+// WithAuditUser sets how the user recorded in the audit entries is found from the request, for instance
+// from the same header or certificate the WithSetAuth check uses. Defaults to the basic auth user, if any.
+func (e *FlagsEndpoint) WithAuditUser(user func(req *http.Request) string) *FlagsEndpoint {
+	e.auditUser = user
+	return e
+}
+
+// WithAuditHistory keeps the last size audit entries in memory, served by the AuditLog handler.
+func (e *FlagsEndpoint) WithAuditHistory(size int) *FlagsEndpoint {
+	e.auditHistory = &auditHistory{entries: make([]AuditEntry, 0, size), size: size}
+	return e
+}
+
+// auditHistory is a fixed size ring of the most recent entries.
+type auditHistory struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+	next    int // where the next entry goes once full.
+	size    int
+}
+
+func (h *auditHistory) add(entry AuditEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.size <= 0 {
+		return
+	}
+	if len(h.entries) < h.size {
+		h.entries = append(h.entries, entry)
+		return
+	}
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % h.size
+}
+
+// recent returns the entries, most recent first.
+func (h *auditHistory) recent() []AuditEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	res := make([]AuditEntry, 0, len(h.entries))
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		res = append(res, h.entries[(h.next+i)%len(h.entries)])
+	}
+	return res
+}
+
+// user returns the user of the request, see WithAuditUser.
+func (e *FlagsEndpoint) user(req *http.Request) string {
+	if e.auditUser != nil {
+		return e.auditUser(req)
+	}
+	user, _, _ := req.BasicAuth()
+	return user
+}
+
+// audit records the outcome of setting the flag, from oldValue to newValue, with err nil on success.
+func (e *FlagsEndpoint) audit(req *http.Request, name, oldValue, newValue string, err error) {
+	f := e.flags().Lookup(name)
+	entry := AuditEntry{
+		Time:       time.Now(),
+		RemoteAddr: req.RemoteAddr,
+		Flag:       name,
+		Old:        dflag.Redact(f, oldValue),
+		New:        dflag.Redact(f, newValue),
+		Success:    err == nil,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	entry.User = e.user(req)
+	if e.auditSink != nil {
+		e.auditSink(entry)
+	} else {
+		logAudit(e.logger(), entry)
+	}
+	if e.auditHistory != nil {
+		e.auditHistory.add(entry)
+	}
+}
+
+// AuditLog returns the recent audit entries (most recent first) as JSON, when enabled with
+// WithAuditHistory (e.g. registered as `/debug/flags/audit`).
+func (e *FlagsEndpoint) AuditLog(resp http.ResponseWriter, req *http.Request) {
+	e.logRequest(req, "AuditLog")
+	if !e.authorized(resp, req, false) {
+		return
+	}
+	if e.auditHistory == nil {
+		e.httpErrf(resp, http.StatusNotFound, "audit history is not enabled")
+		return
+	}
+	out, err := json.MarshalIndent(e.auditHistory.recent(), "", "  ")
+	if err != nil {
+		resp.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	_, _ = resp.Write(out)
+}
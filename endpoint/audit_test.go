@@ -0,0 +1,106 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestAudit(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	set.Int("some_int", 1, "static int for testing")
+	var entries []AuditEntry
+	e := NewFlagsEndpoint(set, "/set").WithAudit(func(entry AuditEntry) {
+		entries = append(entries, entry)
+	})
+	audit := func() []AuditEntry {
+		resp := httptest.NewRecorder()
+		e.AuditLog(resp, httptest.NewRequest(http.MethodGet, "/debug/flags/audit", nil))
+		if resp.Code != http.StatusOK {
+			return nil
+		}
+		var res []AuditEntry
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &res))
+		return res
+	}
+	assert.Equal(t, 0, len(audit()), "history not enabled")
+	e.WithAuditHistory(2)
+	set1 := func(query string) {
+		req := httptest.NewRequest(http.MethodGet, "/set?"+query, nil)
+		req.SetBasicAuth("alice", "pass")
+		e.SetFlag(httptest.NewRecorder(), req)
+	}
+	set1("name=some_dynint&value=2")
+	set1("name=some_dynint&value=x")
+	set1("name=some_int&value=3")
+	assert.Equal(t, 3, len(entries))
+	assert.Equal(t, "alice", entries[0].User)
+	assert.Equal(t, "192.0.2.1:1234", entries[0].RemoteAddr)
+	assert.Equal(t, "1", entries[0].Old)
+	assert.Equal(t, "2", entries[0].New)
+	assert.True(t, entries[0].Success, "valid set")
+	assert.False(t, entries[1].Success, "invalid value")
+	assert.Contains(t, entries[1].Error, "invalid syntax")
+	assert.Equal(t, "not dynamic", entries[2].Error)
+	recent := audit()
+	assert.Equal(t, 2, len(recent), "limited to the history size")
+	assert.Equal(t, "some_int", recent[0].Flag, "most recent first")
+	assert.Equal(t, "x", recent[1].New)
+	// bulk sets record each flag, including the valid ones not applied because of the others.
+	e.WithAuditUser(func(req *http.Request) string { return req.Header.Get("X-User") })
+	req := httptest.NewRequest(http.MethodPost, "/set", strings.NewReader(`{"some_dynint": 5, "some_int": 6}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User", "bob")
+	e.SetFlag(httptest.NewRecorder(), req)
+	assert.Equal(t, 5, len(entries))
+	assert.Equal(t, "bob", entries[3].User)
+	assert.Equal(t, "some_dynint", entries[3].Flag)
+	assert.Equal(t, errBulkNotApplied.Error(), entries[3].Error)
+	assert.Contains(t, entries[4].Error, "not dynamic")
+}
+
+func TestSensitiveRedaction(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	token := dflag.DynString(set, "token", "s3cr3t", "api token").Sensitive()
+	dflag.DynInt64(set, "pin", 7351, "pin").Sensitive()
+	var entries []AuditEntry
+	e := NewFlagsEndpoint(set, "/set").WithAudit(func(entry AuditEntry) {
+		entries = append(entries, entry)
+	})
+	resp := httptest.NewRecorder()
+	e.SetFlag(resp, httptest.NewRequest(http.MethodGet, "/set?name=token&value=n3w", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `Success "token" -> "<redacted>"`, resp.Body.String())
+	assert.Equal(t, "n3w", token.Get())
+	resp = httptest.NewRecorder()
+	e.SetFlag(resp, httptest.NewRequest(http.MethodGet, "/set?name=pin&value=12x4", nil))
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+	req := httptest.NewRequest(http.MethodPost, "/set", strings.NewReader(`{"token": "b0th", "pin": 8642}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp2 := httptest.NewRecorder()
+	e.SetFlag(resp2, req)
+	assert.Equal(t, http.StatusOK, resp2.Code)
+	assert.Equal(t, "b0th", token.Get())
+	resp3 := httptest.NewRecorder()
+	e.ListFlags(resp3, httptest.NewRequest(http.MethodGet, "/debug/flags?format=json", nil))
+	resp4 := httptest.NewRecorder()
+	e.ExportConfigMap(resp4, httptest.NewRequest(http.MethodGet, "/debug/flags/export?all=true", nil))
+	all, _ := json.Marshal(entries)
+	outputs := string(all) + resp.Body.String() + resp2.Body.String() + resp3.Body.String() + resp4.Body.String()
+	for _, secret := range []string{"s3cr3t", "n3w", "12x4", "b0th", "7351", "8642"} {
+		assert.False(t, strings.Contains(outputs, secret), secret+" leaked in "+outputs)
+	}
+	assert.Equal(t, 4, len(entries))
+	assert.Equal(t, dflag.Redacted, entries[0].New)
+	assert.Contains(t, resp3.Body.String(), `"is_changed": true`)
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrUnauthorized returned (possibly wrapped) by an AuthFunc results in a 401 Unauthorized reply,
+// other errors in a 403 Forbidden one.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// AuthFunc checks whether the request is allowed, returning an error to deny it.
+type AuthFunc func(req *http.Request) error
+
+// challengeError is an ErrUnauthorized with the WWW-Authenticate challenge to return.
+type challengeError struct {
+	challenge string
+}
+
+func (c *challengeError) Error() string {
+	return ErrUnauthorized.Error()
+}
+
+func (c *challengeError) Unwrap() error {
+	return ErrUnauthorized
+}
+
+// WithAuth sets the check for all the requests (list, get and set unless WithSetAuth is also used).
+func (e *FlagsEndpoint) WithAuth(auth AuthFunc) *FlagsEndpoint {
+	e.auth = auth
+	return e
+}
+
+// WithSetAuth sets the check for the requests changing flags (SetFlag), allowing it to require different
+// permissions than listing. When not set, the WithAuth one (if any) applies.
+func (e *FlagsEndpoint) WithSetAuth(auth AuthFunc) *FlagsEndpoint {
+	e.setAuth = auth
+	return e
+}
+
+// BearerToken returns an AuthFunc requiring the `Authorization: Bearer <token>` header.
+func BearerToken(token string) AuthFunc {
+	return func(req *http.Request) error {
+		header := req.Header.Get("Authorization")
+		got := strings.TrimPrefix(header, "Bearer ")
+		if got == header || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			return &challengeError{challenge: `Bearer realm="dflag"`}
+		}
+		return nil
+	}
+}
+
+// BasicAuth returns an AuthFunc requiring HTTP basic authentication with the user and password.
+func BasicAuth(user, password string) AuthFunc {
+	return func(req *http.Request) error {
+		u, p, ok := req.BasicAuth()
+		// evaluate both to not leak which one is wrong through timing.
+		userOk := subtle.ConstantTimeCompare([]byte(u), []byte(user)) == 1
+		passOk := subtle.ConstantTimeCompare([]byte(p), []byte(password)) == 1
+		if !ok || !userOk || !passOk {
+			return &challengeError{challenge: `Basic realm="dflag"`}
+		}
+		return nil
+	}
+}
+
+// authorized runs the (set, if mutation and set) auth check and replies with the error if denied.
+func (e *FlagsEndpoint) authorized(resp http.ResponseWriter, req *http.Request, mutation bool) bool {
+	auth := e.auth
+	if mutation && e.setAuth != nil {
+		auth = e.setAuth
+	}
+	if auth == nil {
+		return true
+	}
+	err := auth(req)
+	if err == nil {
+		return true
+	}
+	var ce *challengeError
+	if errors.As(err, &ce) {
+		resp.Header().Set("WWW-Authenticate", ce.challenge)
+	}
+	if errors.Is(err, ErrUnauthorized) {
+		e.httpErrf(resp, http.StatusUnauthorized, "Unauthorized %s %s: %v", req.Method, req.URL.Path, err)
+	} else {
+		e.httpErrf(resp, http.StatusForbidden, "Forbidden %s %s: %v", req.Method, req.URL.Path, err)
+	}
+	return false
+}
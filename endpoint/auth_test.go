@@ -0,0 +1,65 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestAuth(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	e := NewFlagsEndpoint(set, "/set").WithAuth(BasicAuth("admin", "pass")).WithSetAuth(BearerToken("s3cr3t"))
+	do := func(handler http.HandlerFunc, url string, setup func(r *http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		if setup != nil {
+			setup(req)
+		}
+		resp := httptest.NewRecorder()
+		handler(resp, req)
+		return resp
+	}
+	basic := func(user, pass string) func(r *http.Request) {
+		return func(r *http.Request) { r.SetBasicAuth(user, pass) }
+	}
+	bearer := func(token string) func(r *http.Request) {
+		return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }
+	}
+	resp := do(e.ListFlags, "/debug/flags", nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Equal(t, `Basic realm="dflag"`, resp.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, http.StatusUnauthorized, do(e.ListFlags, "/debug/flags", basic("admin", "bad")).Code)
+	assert.Equal(t, http.StatusOK, do(e.ListFlags, "/debug/flags", basic("admin", "pass")).Code)
+	assert.Equal(t, http.StatusOK, do(e.GetFlag, "/debug/flags/get?name=some_dynint", basic("admin", "pass")).Code)
+	assert.Equal(t, http.StatusUnauthorized, do(e.GetFlag, "/debug/flags/get?name=some_dynint", bearer("s3cr3t")).Code)
+	// set requires the token, basic auth (read permission) isn't enough.
+	resp = do(e.SetFlag, "/set?name=some_dynint&value=42", basic("admin", "pass"))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Equal(t, `Bearer realm="dflag"`, resp.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, http.StatusUnauthorized, do(e.SetFlag, "/set?name=some_dynint&value=42", bearer("wrong")).Code)
+	assert.Equal(t, int64(1), dynInt.Get())
+	assert.Equal(t, http.StatusOK, do(e.SetFlag, "/set?name=some_dynint&value=42", bearer("s3cr3t")).Code)
+	assert.Equal(t, int64(42), dynInt.Get())
+	// custom check, without set specific one.
+	e = NewFlagsEndpoint(set, "/set").WithAuth(func(r *http.Request) error {
+		if r.Header.Get("X-Role") != "ops" {
+			return errors.New("ops role required")
+		}
+		return nil
+	})
+	resp = do(e.SetFlag, "/set?name=some_dynint&value=43", nil)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	assert.Contains(t, resp.Body.String(), "ops role required")
+	assert.Equal(t, "", resp.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, http.StatusOK, do(e.SetFlag, "/set?name=some_dynint&value=43",
+		func(r *http.Request) { r.Header.Set("X-Role", "ops") }).Code)
+	assert.Equal(t, int64(43), dynInt.Get())
+}
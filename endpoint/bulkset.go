@@ -0,0 +1,212 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"fortio.org/dflag"
+)
+
+// MaxBulkSetBodySize is the maximum size of the JSON body accepted for bulk sets.
+const MaxBulkSetBodySize = 1 << 20
+
+// SetResult is the outcome for one flag of a bulk set.
+type SetResult struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkSetResponse is the JSON reply to a bulk set: Applied is true if all the values were applied,
+// and false if none were because at least one is invalid (see the Results' errors). Flags whose values
+// can't be validated without setting them (see dflag.ValidateFlag) are refused, so a bulk set never
+// partially applies because of an invalid value.
+type BulkSetResponse struct {
+	Applied bool        `json:"applied"`
+	Results []SetResult `json:"results"`
+	// DryRun is true when the values were only validated, see SetFlag.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// isDryRun returns whether the set request only validates the values, with `dryrun=1` (or true).
+func isDryRun(req *http.Request) bool {
+	dryRun, _ := strconv.ParseBool(req.FormValue("dryrun"))
+	return dryRun
+}
+
+// dryRun handles single flag SetFlag requests with `dryrun=1`: the value is validated without being set
+// and the reply is a JSON SetResult, with the Error and the same status as the actual set would have.
+func (e *FlagsEndpoint) dryRun(resp http.ResponseWriter, name, value string) {
+	res := SetResult{Name: name, Value: dflag.Redact(e.flags().Lookup(name), value)}
+	status := http.StatusOK
+	if err := e.validate(name, value); err != nil {
+		res.Error = err.Error()
+		switch {
+		case errors.Is(err, errNotFound), errors.Is(err, errNotMutable):
+			status = http.StatusForbidden
+		case errors.Is(err, errNotDynamic), errors.Is(err, dflag.ErrNotValidatable):
+			status = http.StatusBadRequest
+		default:
+			status = http.StatusNotAcceptable
+		}
+	}
+	e.logger().Info("dflag: dry run set", "flag", name, "value", res.Value, "status", status)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(status)
+	_ = json.NewEncoder(resp).Encode(res)
+}
+
+func isJSONRequest(req *http.Request) bool {
+	if req.Method != http.MethodPost {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+// jsonToInput converts a JSON value to the string input for flag.Set: strings are used as is,
+// other values (numbers, booleans, objects for JSON flags...) as their JSON text.
+func jsonToInput(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// bulkSet handles a POST of a JSON object of flag name to value: all the values are validated
+// first and only if they are all valid are they applied. The status is 403 if some flags can't
+// be changed through this endpoint, 400 if some can't be validated and 406 for invalid values.
+func (e *FlagsEndpoint) bulkSet(resp http.ResponseWriter, req *http.Request) {
+	values := map[string]json.RawMessage{}
+	dec := json.NewDecoder(http.MaxBytesReader(resp, req.Body, MaxBulkSetBodySize))
+	if err := dec.Decode(&values); err != nil {
+		e.httpErrf(resp, http.StatusBadRequest, "Invalid JSON body, expecting an object of flag name to value: %v", err)
+		return
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	res := BulkSetResponse{Results: make([]SetResult, len(names))}
+	oldValues := make([]string, len(names))
+	valid, forbidden, notValidatable := true, false, false
+	for i, name := range names {
+		res.Results[i] = SetResult{Name: name, Value: jsonToInput(values[name])}
+		if f := e.flags().Lookup(name); f != nil {
+			oldValues[i] = f.Value.String()
+		}
+		if err := e.validate(name, res.Results[i].Value); err != nil {
+			res.Results[i].Error = err.Error()
+			valid = false
+			forbidden = forbidden || errors.Is(err, errNotMutable)
+			notValidatable = notValidatable || errors.Is(err, dflag.ErrNotValidatable)
+		}
+	}
+	status := http.StatusNotAcceptable
+	switch {
+	case forbidden:
+		status = http.StatusForbidden
+	case notValidatable:
+		status = http.StatusBadRequest
+	}
+	res.DryRun = isDryRun(req)
+	if valid {
+		status = http.StatusOK
+	}
+	if valid && !res.DryRun {
+		res.Applied = true
+		for _, i := range applyOrder(names, e.flags()) {
+			r := &res.Results[i]
+			// can still fail if the flag was changed (e.g. JSON flag) or its validator replaced concurrently.
+			if err := dflag.SetFlagFrom(e.flags(), r.Name, r.Value, e.setSource(req)); err != nil {
+				r.Error = err.Error()
+				res.Applied = false
+				status = http.StatusMultiStatus
+				continue
+			}
+			r.Applied = true
+			for _, hook := range e.setHooks {
+				hook(r.Name)
+			}
+		}
+	}
+	for i, r := range res.Results {
+		if res.DryRun {
+			continue // nothing attempted.
+		}
+		var err error
+		switch {
+		case r.Error != "":
+			err = errors.New(r.Error)
+		case !r.Applied:
+			err = errBulkNotApplied
+		}
+		e.audit(req, r.Name, oldValues[i], r.Value, err)
+	}
+	e.logger().Info("dflag: bulk set", "flags", len(names), "applied", res.Applied, "dry_run", res.DryRun)
+	e.redactResults(res.Results)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(status)
+	_ = json.NewEncoder(resp).Encode(res)
+}
+
+var (
+	errNotFound       = errors.New("not found")
+	errNotDynamic     = errors.New("is not dynamic")
+	errNotMutable     = errors.New("can't be changed through this endpoint")
+	errBulkNotApplied = errors.New("not applied as other values of the bulk set are invalid")
+)
+
+// validate checks whether the flag exists, is dynamic, allowed to be set and would accept the value
+// (flags that can't be validated without setting them are refused with dflag.ErrNotValidatable).
+func (e *FlagsEndpoint) validate(name, value string) error {
+	f := e.flags().Lookup(name)
+	if f == nil {
+		return fmt.Errorf("flag %q %w", name, errNotFound)
+	}
+	if !dflag.IsFlagDynamic(f) {
+		return fmt.Errorf("flag %q %w", name, errNotDynamic)
+	}
+	if !e.Mutable(f) {
+		return fmt.Errorf("flag %q %w", name, errNotMutable)
+	}
+	if err := dflag.ValidateFlag(f, value); err != nil {
+		if errors.Is(err, dflag.ErrNotValidatable) {
+			return fmt.Errorf("flag %q: %w, set it individually", name, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// applyOrder returns the indexes of the (sorted) names in the order to set them, see dflag.ApplyOrder.
+func applyOrder(names []string, flagSet *flag.FlagSet) []int {
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		index[name] = i
+	}
+	order := make([]int, 0, len(names))
+	for _, name := range dflag.ApplyOrder(names, flagSet.Lookup) {
+		order = append(order, index[name])
+	}
+	return order
+}
+
+// redactResults replaces the values of the sensitive flags in results, before they're returned.
+func (e *FlagsEndpoint) redactResults(results []SetResult) {
+	for i := range results {
+		results[i].Value = dflag.Redact(e.flags().Lookup(results[i].Name), results[i].Value)
+	}
+}
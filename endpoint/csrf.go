@@ -0,0 +1,119 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// CSRFTokenValidity is how long tokens are accepted after their generation (when the listing page is served).
+	CSRFTokenValidity = 12 * time.Hour
+	// CSRFFormField is the form field (or CSRFHeader the header) carrying the token for set requests.
+	CSRFFormField = "csrf_token"
+	CSRFHeader    = "X-CSRF-Token"
+	// CSRFCookie is the cookie holding the random session id the tokens are bound to.
+	CSRFCookie = "dflag_csrf"
+)
+
+var errCSRF = errors.New("missing or invalid CSRF token")
+
+// WithCSRFProtection makes SetFlag require a POST with a valid token (generated in the listing page's forms,
+// and in the JSON listing, or through CSRFToken()) in the `csrf_token` form field or the `X-CSRF-Token` header,
+// so a malicious page can't change flags through an operator's browser. Note that GET set requests, which
+// work without it, then get a 405 Method Not Allowed reply. Tokens are bound to a random session id set in
+// the `dflag_csrf` cookie (double submit), so a token obtained by someone else can't be replayed, and are
+// signed with secret (a random one is generated if nil, which is fine unless multiple replicas serve the same
+// address). JSON bulk sets are exempt as browsers don't send cross origin JSON requests without a CORS preflight.
+func (e *FlagsEndpoint) WithCSRFProtection(secret []byte) *FlagsEndpoint {
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			panic("dflag: unable to generate CSRF secret: " + err.Error())
+		}
+	}
+	e.csrfSecret = secret
+	return e
+}
+
+func (e *FlagsEndpoint) csrfSign(session, ts string) string {
+	mac := hmac.New(sha256.New, e.csrfSecret)
+	mac.Write([]byte(session + "|" + ts))
+	return ts + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// CSRFToken returns a new token to use for set requests made with the same cookies as req, setting the
+// session cookie on resp if req doesn't have one yet; or "" if CSRF protection isn't enabled.
+func (e *FlagsEndpoint) CSRFToken(resp http.ResponseWriter, req *http.Request) string {
+	if e.csrfSecret == nil {
+		return ""
+	}
+	session := csrfSession(req)
+	if session == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return ""
+		}
+		session = hex.EncodeToString(b)
+		http.SetCookie(resp, &http.Cookie{
+			Name: CSRFCookie, Value: session, Path: "/",
+			HttpOnly: true, Secure: req.TLS != nil, SameSite: http.SameSiteStrictMode,
+		})
+	}
+	return e.csrfSign(session, strconv.FormatInt(time.Now().Unix(), 10))
+}
+
+// csrfSession returns the session id from the request's cookie, "" if there is none.
+func csrfSession(req *http.Request) string {
+	c, err := req.Cookie(CSRFCookie)
+	if err != nil {
+		return ""
+	}
+	return c.Value
+}
+
+func (e *FlagsEndpoint) validCSRFToken(session, token string) bool {
+	ts, _, found := strings.Cut(token, ".")
+	if !found || session == "" {
+		return false
+	}
+	if !hmac.Equal([]byte(token), []byte(e.csrfSign(session, ts))) {
+		return false
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := time.Since(time.Unix(sec, 0))
+	return age < CSRFTokenValidity && age > -time.Minute
+}
+
+// csrfCheck replies with an error and returns false if CSRF protection is enabled and the set request fails it.
+func (e *FlagsEndpoint) csrfCheck(resp http.ResponseWriter, req *http.Request) bool {
+	if e.csrfSecret == nil || isJSONRequest(req) {
+		return true
+	}
+	if req.Method != http.MethodPost {
+		resp.Header().Set("Allow", http.MethodPost)
+		e.httpErrf(resp, http.StatusMethodNotAllowed, "Setting flags requires a POST")
+		return false
+	}
+	token := req.Header.Get(CSRFHeader)
+	if token == "" {
+		token = req.PostFormValue(CSRFFormField)
+	}
+	if !e.validCSRFToken(csrfSession(req), token) {
+		e.httpErrf(resp, http.StatusForbidden, "%v", errCSRF)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestCSRF(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	e := NewFlagsEndpoint(set, "/set")
+	assert.Equal(t, "", e.CSRFToken(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil)), "not enabled")
+	e.WithCSRFProtection(nil)
+	var session *http.Cookie
+	post := func(form url.Values, header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/set", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if session != nil {
+			req.AddCookie(session)
+		}
+		if header != "" {
+			req.Header.Set(CSRFHeader, header)
+		}
+		resp := httptest.NewRecorder()
+		e.SetFlag(resp, req)
+		return resp
+	}
+	// GET isn't allowed anymore.
+	resp := httptest.NewRecorder()
+	e.SetFlag(resp, httptest.NewRequest(http.MethodGet, "/set?name=some_dynint&value=42", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	// token from the listing.
+	resp = httptest.NewRecorder()
+	e.ListFlags(resp, httptest.NewRequest(http.MethodGet, "/debug/flags", nil))
+	list := &flagSetJSON{}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), list))
+	assert.NotEqual(t, "", list.CSRFToken)
+	cookies := resp.Result().Cookies()
+	assert.Equal(t, 1, len(cookies))
+	assert.Equal(t, CSRFCookie, cookies[0].Name)
+	assert.True(t, cookies[0].HttpOnly, "session cookie should be http only")
+	form := url.Values{"name": {"some_dynint"}, "value": {"42"}, CSRFFormField: {list.CSRFToken}}
+	assert.Equal(t, http.StatusForbidden, post(form, "").Code, "token without its session cookie")
+	session = cookies[0]
+	form.Del(CSRFFormField)
+	assert.Equal(t, http.StatusForbidden, post(form, "").Code, "missing token")
+	assert.Equal(t, http.StatusForbidden, post(form, list.CSRFToken+"0").Code, "bad token")
+	req := httptest.NewRequest(http.MethodGet, "/debug/flags", nil)
+	req.AddCookie(&http.Cookie{Name: CSRFCookie, Value: "someone else"})
+	assert.Equal(t, http.StatusForbidden, post(form, e.CSRFToken(httptest.NewRecorder(), req)).Code,
+		"token of another session")
+	other := NewFlagsEndpoint(set, "/set").WithCSRFProtection([]byte("other secret"))
+	req = httptest.NewRequest(http.MethodGet, "/debug/flags", nil)
+	req.AddCookie(session)
+	assert.Equal(t, http.StatusForbidden, post(form, other.CSRFToken(httptest.NewRecorder(), req)).Code,
+		"token signed with another secret")
+	expired := e.csrfSign(session.Value, strconv.FormatInt(time.Now().Add(-CSRFTokenValidity-time.Minute).Unix(), 10))
+	assert.Equal(t, http.StatusForbidden, post(form, expired).Code, "expired token")
+	assert.Equal(t, int64(1), dynInt.Get())
+	form.Set(CSRFFormField, list.CSRFToken)
+	assert.Equal(t, http.StatusOK, post(form, "").Code)
+	assert.Equal(t, int64(42), dynInt.Get())
+	form.Del(CSRFFormField)
+	form.Set("value", "43")
+	resp = httptest.NewRecorder()
+	token := e.CSRFToken(resp, req)
+	assert.Equal(t, 0, len(resp.Result().Cookies()), "existing session is kept")
+	assert.Equal(t, http.StatusOK, post(form, token).Code, "token in header")
+	assert.Equal(t, int64(43), dynInt.Get())
+	// JSON (bulk) sets are exempt, but only for exactly that media type.
+	req = httptest.NewRequest(http.MethodPost, "/set", strings.NewReader(`{"some_dynint": 44}`))
+	req.Header.Set("Content-Type", "application/jsonx")
+	resp = httptest.NewRecorder()
+	e.SetFlag(resp, req)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	req = httptest.NewRequest(http.MethodPost, "/set", strings.NewReader(`{"some_dynint": 44}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	resp = httptest.NewRecorder()
+	e.SetFlag(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, int64(44), dynInt.Get())
+	// the HTML forms carry the token and POST.
+	req = httptest.NewRequest(http.MethodGet, "/debug/flags", nil)
+	req.Header.Set("Accept", "text/html")
+	resp = httptest.NewRecorder()
+	e.ListFlags(resp, req)
+	assert.Contains(t, resp.Body.String(), `method="post"`)
+	assert.Contains(t, resp.Body.String(), `name="csrf_token"`)
+}
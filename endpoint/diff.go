@@ -0,0 +1,48 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+
+	"fortio.org/dflag"
+)
+
+// diffData is what the DiffFlags HTML template is executed with.
+type diffData struct {
+	Flags      []*flagJSON
+	StyleSheet string
+}
+
+// DiffFlags returns only the flags whose current value differs from their default, with both values
+// side by side (e.g. registered as `/debug/flags/diff`): as an HTML table for browsers, JSON otherwise
+// (or when `format=json` is passed). Like ListFlags, replies have an ETag.
+func (e *FlagsEndpoint) DiffFlags(resp http.ResponseWriter, req *http.Request) {
+	e.logRequest(req, "DiffFlags")
+	if !e.authorized(resp, req, false) {
+		return
+	}
+	if notModified(resp, req, e.listETag(req)) {
+		return
+	}
+	flags := []*flagJSON{}
+	e.flags().VisitAll(func(f *flag.Flag) {
+		if dflag.IsChanged(f) {
+			flags = append(flags, e.flagToJSON(f))
+		}
+	})
+	if requestIsBrowser(req) && req.URL.Query().Get("format") != "json" {
+		e.writeHTML(resp, e.diffTemplate, dflagDiffTemplate, &diffData{Flags: flags, StyleSheet: e.styleSheetOrDefault()})
+		return
+	}
+	out, err := json.MarshalIndent(flags, "", "  ")
+	if err != nil {
+		resp.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	_, _ = resp.Write(out)
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestDiffFlags(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	dflag.DynString(set, "some_dynstr", "a", "dynamic string for testing")
+	set.Int("some_int", 1, "static int for testing")
+	e := NewFlagsEndpoint(set, "")
+	diff := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/debug/flags/diff", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp := httptest.NewRecorder()
+		e.DiffFlags(resp, req)
+		return resp
+	}
+	assert.Equal(t, "[]", diff("").Body.String())
+	assert.Contains(t, diff("text/html").Body.String(), "All flags have their default value")
+	assert.NoError(t, set.Set("some_dynint", "2"))
+	assert.NoError(t, set.Set("some_int", "3"))
+	var flags []flagJSON
+	assert.NoError(t, json.Unmarshal(diff("").Body.Bytes(), &flags))
+	assert.Equal(t, 2, len(flags))
+	assert.Equal(t, "some_dynint", flags[0].Name)
+	assert.Equal(t, "1", flags[0].DefaultValue)
+	assert.Equal(t, "2", flags[0].CurrentValue)
+	assert.Equal(t, "some_int", flags[1].Name)
+	assert.False(t, flags[1].IsDynamic, "static")
+	html := diff("text/html").Body.String()
+	assert.Contains(t, html, "<code>some_dynint</code>")
+	assert.False(t, json.Valid([]byte(html)), "html")
+}
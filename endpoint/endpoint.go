@@ -8,11 +8,16 @@ import (
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"html/template"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"fortio.org/dflag"
 	"fortio.org/dflag/dynloglevel"
@@ -21,8 +26,37 @@ import (
 
 // FlagsEndpoint is a collection of `http.HandlerFunc` that serve debug pages about a given `FlagSet.
 type FlagsEndpoint struct {
-	flagSet *flag.FlagSet
-	setURL  string
+	flagSet  *flag.FlagSet
+	setURL   string
+	setHooks []func(name string)
+	auth     AuthFunc // see WithAuth.
+	setAuth  AuthFunc // see WithSetAuth.
+	// csrfSecret signs the CSRF tokens, nil when CSRF protection isn't enabled.
+	csrfSecret []byte
+	// flags SetFlag can change, see Mutable.
+	setAllow     []string
+	setDeny      []string
+	setPredicate func(f *flag.Flag) bool
+	// getPrefix is where GetFlag is mounted, see WithGetPrefix.
+	getPrefix string
+	// audit of the set attempts, see WithAudit, WithAuditUser and WithAuditHistory.
+	auditSink    AuditFunc
+	auditUser    func(req *http.Request) string
+	auditHistory *auditHistory
+	// HTML customization, see WithStyleSheet, WithListTemplate and WithDiffTemplate.
+	styleSheet   string
+	listTemplate *template.Template
+	diffTemplate *template.Template
+	// middlewares wrapping the mounted handlers, see WithMiddleware.
+	middlewares []Middleware
+	// other FlagSets, see WithFlagSet.
+	sections []flagSection
+	combined *combinedFlags
+	// rate limits and serialization of the mutating requests, see WithSetRateLimit.
+	setLimiter *setLimiter
+	setMu      sync.Mutex
+	// customLogger gets the endpoint's logs, nil for fortio.org/log, see WithLogger.
+	customLogger dflag.Logger
 }
 
 // NewFlagsEndpoint creates a new debug `http.HandlerFunc` collection for a given `FlagSet`
@@ -33,74 +67,269 @@ func NewFlagsEndpoint(flagSet *flag.FlagSet, setURL string) *FlagsEndpoint {
 	return &FlagsEndpoint{flagSet: flagSet, setURL: setURL}
 }
 
+// WithSetHook adds a function called with the flag name after each successful set through
+// the endpoint (e.g. configmap's Updater.Persist to write runtime changes back).
+func (e *FlagsEndpoint) WithSetHook(hook func(name string)) *FlagsEndpoint {
+	e.setHooks = append(e.setHooks, hook)
+	return e
+}
+
+// WithGetPrefix sets the path GetFlag is registered under (e.g. `/debug/flags/`) so the flag name
+// can also be given as the rest of the path, like `/debug/flags/{name}`.
+func (e *FlagsEndpoint) WithGetPrefix(prefix string) *FlagsEndpoint {
+	e.getPrefix = prefix
+	return e
+}
+
+// WithLogger sends the endpoint's logs, including the requests and the default audit entries, to logger
+// (e.g. a *slog.Logger) instead of fortio.org/log.
+func (e *FlagsEndpoint) WithLogger(logger dflag.Logger) *FlagsEndpoint {
+	e.customLogger = logger
+	return e
+}
+
+func (e *FlagsEndpoint) logger() dflag.Logger {
+	if e.customLogger == nil {
+		return dynloglevel.FortioLogger()
+	}
+	return e.customLogger
+}
+
+// logRequest logs the request to the handler named name.
+func (e *FlagsEndpoint) logRequest(req *http.Request, name string) {
+	req = e.redactRequest(req)
+	if e.customLogger == nil {
+		log.LogRequest(req, name)
+		return
+	}
+	e.customLogger.Info(name, "method", req.Method, "url", req.URL.String(), "proto", req.Proto,
+		"remote_addr", req.RemoteAddr, "host", req.Host)
+}
+
+// redactRequest returns req, or a copy without the value query parameter when it's for a sensitive flag.
+func (e *FlagsEndpoint) redactRequest(req *http.Request) *http.Request {
+	q := req.URL.Query()
+	if !q.Has("value") {
+		return req
+	}
+	if f := e.flags().Lookup(q.Get("name")); f == nil || !dflag.IsSensitive(f) {
+		return req
+	}
+	q.Set("value", dflag.Redacted)
+	u := *req.URL
+	u.RawQuery = q.Encode()
+	r := req.WithContext(req.Context())
+	r.URL = &u
+	return r
+}
+
 // HTTPErrf logs and returns an error on the response.
 func HTTPErrf(resp http.ResponseWriter, statusCode int, message string, rest ...interface{}) {
+	log.Errf(message, rest...)
+	writeError(resp, statusCode, fmt.Sprintf(message, rest...))
+}
+
+// httpErrf is HTTPErrf logging to the endpoint's logger.
+func (e *FlagsEndpoint) httpErrf(resp http.ResponseWriter, statusCode int, message string, rest ...interface{}) {
+	msg := fmt.Sprintf(message, rest...)
+	e.logger().Error(msg, "status", statusCode)
+	writeError(resp, statusCode, msg)
+}
+
+func writeError(resp http.ResponseWriter, statusCode int, msg string) {
 	resp.WriteHeader(statusCode)
 	resp.Header().Set("Content-Type", "text/plain; charset=UTF-8")
-	log.Errf(message, rest...)
-	_, _ = resp.Write([]byte(fmt.Sprintf(message, rest...)))
+	_, _ = resp.Write([]byte(msg))
 }
 
-// SetFlag updates a dynamic flag to a new value.
+// SetFlag updates a dynamic flag to a new value, from the `name` and `value` query (or form) parameters.
+// A POST with an `application/json` body of flag name to value object sets multiple flags at
+// once: all values are validated before any is applied and the reply is a JSON BulkSetResponse.
+// With `action=reset` the `name` flag, or all changed ones with `all=true`, are reset to their default
+// value once `confirm=true` is passed (see ResetResponse). With `dryrun=1` the value(s) are only parsed and
+// validated: the reply is a JSON SetResult (or BulkSetResponse) with the error or none if the set would succeed.
+// Failures to set a single flag are replied as application/problem+json, see Problem.
 func (e *FlagsEndpoint) SetFlag(resp http.ResponseWriter, req *http.Request) {
-	log.LogRequest(req, "SetFlag")
+	e.logRequest(req, "SetFlag")
 	if e.setURL == "" {
-		HTTPErrf(resp, http.StatusForbidden, "setting flags is not enabled")
+		e.httpErrf(resp, http.StatusForbidden, "setting flags is not enabled")
+		return
+	}
+	if !e.authorized(resp, req, true) {
+		return
+	}
+	if !e.csrfCheck(resp, req) {
+		return
+	}
+	release, ok := e.setGuard(resp, req)
+	if !ok {
+		return
+	}
+	defer release()
+	if isJSONRequest(req) {
+		e.bulkSet(resp, req)
 		return
 	}
-	name := req.URL.Query().Get("name")
-	value := req.URL.Query().Get("value")
-	f := e.flagSet.Lookup(name)
+	if req.FormValue("action") == "reset" {
+		e.resetFlags(resp, req)
+		return
+	}
+	name := req.FormValue("name")
+	value := req.FormValue("value")
+	if isDryRun(req) {
+		e.dryRun(resp, name, value)
+		return
+	}
+	f := e.flags().Lookup(name)
 	if f == nil {
-		HTTPErrf(resp, http.StatusForbidden, "Flag %q not found", name)
+		e.audit(req, name, "", value, errors.New("not found"))
+		e.writeProblem(resp, newProblem(http.StatusForbidden, "Flag not found", nil, name, value, nil))
 		return
 	}
+	oldValue := f.Value.String()
 	if !dflag.IsFlagDynamic(f) {
-		HTTPErrf(resp, http.StatusBadRequest, "Trying to set non dynamic flag %q", name)
+		e.audit(req, name, oldValue, value, errors.New("not dynamic"))
+		e.writeProblem(resp, newProblem(http.StatusBadRequest, "Flag is not dynamic", f, name, value, nil))
 		return
 	}
-	if err := e.flagSet.Set(name, value); err != nil {
-		HTTPErrf(resp, http.StatusNotAcceptable, "Error setting %q to %q: %v", name, value, err)
+	if !e.Mutable(f) {
+		e.audit(req, name, oldValue, value, errNotMutable)
+		e.writeProblem(resp, newProblem(http.StatusForbidden, "Flag can't be changed through this endpoint", f, name, value, nil))
 		return
 	}
+	if err := dflag.SetFlagFrom(e.flags(), name, value, e.setSource(req)); err != nil {
+		e.audit(req, name, oldValue, value, err)
+		e.writeProblem(resp, newProblem(http.StatusNotAcceptable, "Invalid flag value", f, name, value, err))
+		return
+	}
+	e.audit(req, name, oldValue, value, nil)
+	for _, hook := range e.setHooks {
+		hook(name)
+	}
 	resp.Header().Set("Content-Type", "text/plain; charset=UTF-8")
-	_, _ = resp.Write([]byte(fmt.Sprintf("Success %q -> %q", name, value)))
+	_, _ = resp.Write([]byte(fmt.Sprintf("Success %q -> %q", name, dflag.Redact(f, value))))
+}
+
+// flagFilter selects the flags listed based on the request's query parameters.
+type flagFilter struct {
+	changed   *bool // nil for all, otherwise only (un)changed flags.
+	dynamic   *bool // nil for all, otherwise only dynamic (or static) flags.
+	substring string
+	prefix    string
+}
+
+func parseBoolParam(q url.Values, name string) (*bool, error) {
+	v := q.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s=%q: %w", name, v, err)
+	}
+	return &b, nil
+}
+
+func newFlagFilter(q url.Values) (*flagFilter, error) {
+	ff := &flagFilter{substring: strings.ToLower(q.Get("filter")), prefix: q.Get("prefix")}
+	var err error
+	if ff.changed, err = parseBoolParam(q, "changed"); err != nil {
+		return nil, err
+	}
+	if ff.dynamic, err = parseBoolParam(q, "dynamic"); err != nil {
+		return nil, err
+	}
+	// older parameters.
+	if q.Get("only_changed") != "" {
+		t := true
+		ff.changed = &t
+	}
+	switch q.Get("type") {
+	case "dynamic":
+		t := true
+		ff.dynamic = &t
+	case "static":
+		f := false
+		ff.dynamic = &f
+	}
+	return ff, nil
+}
+
+func (ff *flagFilter) match(f *flag.Flag) bool {
+	// not exactly the same as "changed" (!)
+	if ff.changed != nil && *ff.changed != dflag.IsChanged(f) {
+		return false
+	}
+	if ff.dynamic != nil && *ff.dynamic != dflag.IsFlagDynamic(f) {
+		return false
+	}
+	if !strings.HasPrefix(f.Name, ff.prefix) {
+		return false
+	}
+	return ff.substring == "" || strings.Contains(strings.ToLower(f.Name), ff.substring) ||
+		strings.Contains(strings.ToLower(f.Usage), ff.substring)
 }
 
 // ListFlags provides an HTML and JSON `http.HandlerFunc` that lists all Flags of a `FlagSet`.
-// Additional URL query parameters can be used such as `type=[dynamic,static]` or `only_changed=true`.
+// Additional URL query parameters can be used to filter the flags: `dynamic=[true,false]` (or `type=[dynamic,static]`),
+// `changed=[true,false]` (or `only_changed=true`), `prefix=redis.` for flags whose name starts with the prefix and
+// `filter=substring` for flags whose name or description contain the (case-insensitive) substring.
+// `format=[json,txt,csv]` forces the output format (otherwise HTML for browsers and JSON for others).
+// `sort=[name,changed,modified]` orders the flags by name (default), changed ones first or most recently
+// set first; `offset=N` and `limit=M` return only a page of them (the JSON `total` is the number of matching flags).
+// Replies have an ETag, cheaply computed from the flags' generations, so polling with If-None-Match gets
+// 304 Not Modified replies while nothing changed (except when CSRF protection adds a token to the reply).
 func (e *FlagsEndpoint) ListFlags(resp http.ResponseWriter, req *http.Request) {
-	log.LogRequest(req, "ListFlags")
-
-	onlyChanged := req.URL.Query().Get("only_changed") != ""
-	onlyDynamic := req.URL.Query().Get("type") == "dynamic"
-	onlyStatic := req.URL.Query().Get("type") == "static"
+	e.logRequest(req, "ListFlags")
+	if !e.authorized(resp, req, false) {
+		return
+	}
+	if notModified(resp, req, e.listETag(req)) {
+		return
+	}
 
+	filter, err := newFlagFilter(req.URL.Query())
+	if err != nil {
+		e.httpErrf(resp, http.StatusBadRequest, "%v", err)
+		return
+	}
+	page, err := newListPage(req.URL.Query())
+	if err != nil {
+		e.httpErrf(resp, http.StatusBadRequest, "%v", err)
+		return
+	}
 	flagSetJSON := &flagSetJSON{}
-	e.flagSet.VisitAll(func(f *flag.Flag) {
-		if onlyChanged && f.Value.String() == f.DefValue { // not exactly the same as "changed" (!)
-			return
-		}
-		if onlyDynamic && !dflag.IsFlagDynamic(f) {
-			return
-		}
-		if onlyStatic && dflag.IsFlagDynamic(f) {
-			return
+	e.flags().VisitAll(func(f *flag.Flag) {
+		if filter.match(f) {
+			flagSetJSON.Flags = append(flagSetJSON.Flags, e.flagToJSON(f))
 		}
-		flagSetJSON.Flags = append(flagSetJSON.Flags, flagToJSON(f))
 	})
-	flagSetJSON.ChecksumDynamic = hex.EncodeToString(dflag.ChecksumFlagSet(e.flagSet, dflag.IsFlagDynamic))
-	flagSetJSON.ChecksumStatic = hex.EncodeToString(dflag.ChecksumFlagSet(e.flagSet,
+	flagSetJSON.Total = len(flagSetJSON.Flags)
+	flagSetJSON.Flags = page.apply(flagSetJSON.Flags)
+	prev := page.offset - page.limit
+	if prev < 0 {
+		prev = 0
+	}
+	flagSetJSON.PrevURL = page.pageURL(req.URL, prev, flagSetJSON.Total)
+	flagSetJSON.NextURL = page.pageURL(req.URL, page.offset+page.limit, flagSetJSON.Total)
+	flagSetJSON.ChecksumDynamic = hex.EncodeToString(dflag.ChecksumFlagSet(e.flags(), dflag.IsFlagDynamic))
+	flagSetJSON.ChecksumStatic = hex.EncodeToString(dflag.ChecksumFlagSet(e.flags(),
 		func(f *flag.Flag) bool { return !dflag.IsFlagDynamic(f) }))
 	flagSetJSON.FlagSetURL = e.setURL
+	if e.setURL != "" {
+		flagSetJSON.CSRFToken = e.CSRFToken(resp, req)
+	}
 
-	if requestIsBrowser(req) && req.URL.Query().Get("format") != "json" {
-		resp.WriteHeader(http.StatusOK)
-		resp.Header().Add("Content-Type", "text/html")
-		if err := dflagListTemplate.Execute(resp, flagSetJSON); err != nil {
-			log.Fatalf("Bad template evaluation: %v", err)
-		}
-	} else {
+	format := req.URL.Query().Get("format")
+	switch {
+	case format == "txt":
+		writeText(resp, flagSetJSON)
+	case format == "csv":
+		writeCSV(resp, flagSetJSON)
+	case requestIsBrowser(req) && format != "json":
+		flagSetJSON.StyleSheet = e.styleSheetOrDefault()
+		e.writeHTML(resp, e.listTemplate, dflagListTemplate, flagSetJSON)
+	default:
 		resp.Header().Add("Content-Type", "application/json")
 		out, err := json.MarshalIndent(&flagSetJSON, "", "  ")
 		if err != nil {
@@ -112,80 +341,121 @@ func (e *FlagsEndpoint) ListFlags(resp http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// MaxGetWait is the longest a GetFlag `wait` can be.
+const MaxGetWait = 5 * time.Minute
+
+// GenerationHeader is the response header carrying the flag's generation (number of sets) in GetFlag replies.
+const GenerationHeader = "X-Dflag-Generation"
+
+// GetFlag returns the current value of a single flag, named by the `name` query (or form) parameter or,
+// when a prefix is set with WithGetPrefix, the rest of the path (e.g. `/debug/flags/{name}`).
+// Dynamic flags' generation is returned in the X-Dflag-Generation header; passing a `wait` duration (e.g.
+// `?wait=30s&last_generation=N`) long polls: the reply comes as soon as the generation differs from
+// last_generation (defaults to the current one, to wait for the next change) or, with a 304 Not Modified
+// status, when the wait (at most MaxGetWait) expires.
+// The raw value is returned as plain text unless `format=json` is passed (or JSON is accepted) in which case
+// the same JSON object as in the ListFlags output is returned.
+func (e *FlagsEndpoint) GetFlag(resp http.ResponseWriter, req *http.Request) {
+	e.logRequest(req, "GetFlag")
+	if !e.authorized(resp, req, false) {
+		return
+	}
+	name := req.FormValue("name")
+	if name == "" && e.getPrefix != "" && strings.HasPrefix(req.URL.Path, e.getPrefix) {
+		name = strings.TrimPrefix(req.URL.Path, e.getPrefix)
+	}
+	if name == "" {
+		e.httpErrf(resp, http.StatusBadRequest, "Missing flag name")
+		return
+	}
+	f := e.flags().Lookup(name)
+	if f == nil {
+		e.httpErrf(resp, http.StatusNotFound, "Flag %q not found", name)
+		return
+	}
+	if !e.waitForChange(resp, req, f) {
+		return
+	}
+	if req.URL.Query().Get("format") == "json" || strings.Contains(req.Header.Get("Accept"), "application/json") {
+		resp.Header().Set("Content-Type", "application/json")
+		out, err := json.MarshalIndent(e.flagToJSON(f), "", "  ")
+		if err != nil {
+			resp.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = resp.Write(out)
+		return
+	}
+	resp.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	_, _ = resp.Write([]byte(f.Value.String()))
+}
+
+// waitForChange sets the generation header and, if requested, waits for the flag to change. Returns false
+// if the reply was already sent (error or wait expired).
+func (e *FlagsEndpoint) waitForChange(resp http.ResponseWriter, req *http.Request, f *flag.Flag) bool {
+	w, ok := f.Value.(dflag.DynamicFlagWatcher)
+	waitStr := req.FormValue("wait")
+	if waitStr == "" {
+		if ok {
+			resp.Header().Set(GenerationHeader, strconv.FormatUint(w.Generation(), 10))
+		}
+		return true
+	}
+	if !ok {
+		e.httpErrf(resp, http.StatusBadRequest, "Flag %q can't be waited on", f.Name)
+		return false
+	}
+	wait, err := time.ParseDuration(waitStr)
+	if err != nil || wait < 0 {
+		e.httpErrf(resp, http.StatusBadRequest, "Invalid wait %q", waitStr)
+		return false
+	}
+	if wait > MaxGetWait {
+		wait = MaxGetWait
+	}
+	last := w.Generation()
+	if lastStr := req.FormValue("last_generation"); lastStr != "" {
+		if last, err = strconv.ParseUint(lastStr, 10, 64); err != nil {
+			e.httpErrf(resp, http.StatusBadRequest, "Invalid last_generation %q", lastStr)
+			return false
+		}
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		changed := w.Changed()
+		if gen := w.Generation(); gen != last {
+			resp.Header().Set(GenerationHeader, strconv.FormatUint(gen, 10))
+			return true
+		}
+		select {
+		case <-changed:
+		case <-timer.C:
+			resp.Header().Set(GenerationHeader, strconv.FormatUint(last, 10))
+			resp.WriteHeader(http.StatusNotModified)
+			return false
+		case <-req.Context().Done():
+			return false
+		}
+	}
+}
+
 func requestIsBrowser(req *http.Request) bool {
 	return strings.Contains(req.Header.Get("Accept"), "html")
 }
 
-//nolint:lll
-var dflagListTemplate = template.Must(template.New("dflag_list").Parse(
-	`
-<html><head>
-<title>Flags List</title>
-<link href="https://maxcdn.bootstrapcdn.com/bootstrap/3.3.4/css/bootstrap.css" rel="stylesheet">
-
-</head>
-<body>
-<div class="container-fluid">
-<div class="col-md-10 col-md-offset-1">
-	<h1>Flags Debug View</h1>
-	<p>
-	This page presents the configuration flags of this server (<a href="?format=json">JSON</a>).
-	</p>
-	<p>
-	You can easily filter only <a href="?only_changed=true"><span class="label label-primary">changed</span> flag</a> or filter flags by type:
-	</p>
-	<ul>
-	  <li><a href="?type=dynamic"><span class="label label-success">dynamic</span></a> - flags tweakable dynamically - checksum <code>{{ .ChecksumDynamic }}</code></li>
-	  <li><a href="?type=static"><span class="label label-default">static</span></a> - initialization-time only flags - checksum <code>{{ .ChecksumStatic }}</code></li>
-	</ul>
-
-
-
-	{{range $flag := .Flags }}
-		<div class="panel panel-default">
-          <div class="panel-heading">
-            <code>{{ $flag.Name }}</code>
-            {{ if $flag.IsChanged }}<span class="label label-primary">changed</span>{{ end }}
-            {{ if $flag.IsDynamic }}
-                <span class="label label-success">dynamic</span>
-            {{ else }}
-                <span class="label label-default">static</span>
-            {{ end }}
-
-          </div>
-		  <div class="panel-body">
-		    <dl class="dl-horizontal" style="margin-bottom: 0px">
-			  <dt>Description</dt>
-			  <dd><small>{{ $flag.Description }}</small></dd>
-			  <dt>Default</dt>
-			  <dd><pre style="font-size: 8pt">{{ $flag.DefaultValue }}</pre></dd>
-			  <dt>Current</dt>
-			  {{ if and $flag.IsDynamic (ne $.FlagSetURL "") }}
-			  <form action="{{ $.FlagSetURL }}">
-			  <input type="hidden" name="name" value="{{ $flag.Name }}" />
-				  {{ if $flag.IsJSON }}
-					  <dd><pre class="success" style="font-size: 8pt"><textarea name="value">{{ $flag.CurrentValue }}</textarea></pre><input type="submit" value="Update"/></dd>
-				  {{ else }}
-					  <dd><pre class="success" style="font-size: 8pt"><input type="text" name="value" value="{{ $flag.CurrentValue }}" /></pre></dd>
-				  {{ end }}
-			  </form>
-			  {{ else }}
-			  <dd><pre class="success" style="font-size: 8pt">{{ $flag.CurrentValue }}</pre></dd>
-			  {{ end }}
-		    </dl>
-		  </div>
-		</div>
-	{{end}}
-</div></div>
-</body>
-</html>
-`))
-
 type flagSetJSON struct {
 	ChecksumStatic  string      `json:"checksum_static"`
 	ChecksumDynamic string      `json:"checksum_dynamic"`
 	FlagSetURL      string      `json:"set_url"`
+	CSRFToken       string      `json:"csrf_token,omitempty"`
+	StyleSheet      string      `json:"-"` // for the HTML templates.
 	Flags           []*flagJSON `json:"flags"`
+	// Total number of flags matching the filters, when Flags is a page of them.
+	Total int `json:"total"`
+	// links to the previous and next pages, for the HTML templates.
+	PrevURL string `json:"-"`
+	NextURL string `json:"-"`
 }
 
 type flagJSON struct {
@@ -197,6 +467,24 @@ type flagJSON struct {
 	IsChanged bool `json:"is_changed"`
 	IsDynamic bool `json:"is_dynamic"`
 	IsJSON    bool `json:"is_json"`
+	IsMutable bool `json:"is_mutable"` // can be changed through SetFlag.
+	// Section is the prefix of the FlagSet the flag comes from, see WithFlagSet.
+	Section string `json:"section,omitempty"`
+
+	// LastModified is when the dynamic flag was last set, empty if never.
+	LastModified string `json:"last_modified,omitempty"`
+	// Kind of value for the UI's input: bool, int, float, json or empty for text.
+	Kind string `json:"kind,omitempty"`
+	// Choices and Range (from, to) of acceptable values, see dflag.DynamicFlagHints.
+	Choices []string `json:"choices,omitempty"`
+	Range   []string `json:"range,omitempty"`
+}
+
+func (e *FlagsEndpoint) flagToJSON(f *flag.Flag) *flagJSON {
+	fj := flagToJSON(f)
+	fj.IsMutable = e.setURL != "" && e.Mutable(f)
+	fj.Section = e.section(f.Name)
+	return fj
 }
 
 func flagToJSON(f *flag.Flag) *flagJSON {
@@ -205,7 +493,7 @@ func flagToJSON(f *flag.Flag) *flagJSON {
 		Description:  f.Usage,
 		CurrentValue: f.Value.String(),
 		DefaultValue: f.DefValue,
-		IsChanged:    f.Value.String() != f.DefValue,
+		IsChanged:    dflag.IsChanged(f),
 		IsDynamic:    dflag.IsFlagDynamic(f),
 	}
 	if dj, ok := f.Value.(dflag.DynamicJSONFlagValue); ok {
@@ -213,14 +501,45 @@ func flagToJSON(f *flag.Flag) *flagJSON {
 		fj.CurrentValue = prettyPrintJSON(fj.CurrentValue)
 		fj.DefaultValue = prettyPrintJSON(fj.DefaultValue)
 	}
+	fj.Kind = valueKind(f.Value, fj.IsJSON)
+	if w, ok := f.Value.(dflag.DynamicFlagWatcher); ok && !w.LastModified().IsZero() {
+		fj.LastModified = w.LastModified().UTC().Format(lastModifiedFormat)
+	}
+	if h, ok := f.Value.(dflag.DynamicFlagHints); ok {
+		fj.Choices = h.Choices()
+		if from, to, ok := h.Range(); ok {
+			fj.Range = []string{from, to}
+		}
+	}
 	return fj
 }
 
+// valueKind returns the flagJSON Kind of the flag value.
+func valueKind(v flag.Value, isJSON bool) string {
+	if isJSON {
+		return "json"
+	}
+	if b, ok := v.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+		return "bool"
+	}
+	typed, ok := v.(interface{ Type() string })
+	if !ok {
+		return ""
+	}
+	switch typed.Type() {
+	case "dyn_int64":
+		return "int"
+	case "dyn_float64":
+		return "float"
+	default:
+		return ""
+	}
+}
+
 func prettyPrintJSON(input string) string {
 	out := &bytes.Buffer{}
 	if err := json.Indent(out, []byte(input), "", "  "); err != nil {
-		log.Errf("Error pretty printing json %q: %v", input, err)
-		return "PRETTY_ERROR"
+		return "PRETTY_ERROR" // not logged: the dynamic JSON flags only hold valid JSON.
 	}
 	return out.String()
 }
@@ -5,15 +5,20 @@ package endpoint
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"flag"
 	"net/http"
 	"net/http/httptest"
 	"sort"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"fortio.org/assert"
 	"fortio.org/dflag"
+	"fortio.org/dflag/dflagtest"
 )
 
 type endpointTestSuite struct {
@@ -77,6 +82,26 @@ func (s *endpointTestSuite) TestReturnsOnlyStaticAndChanged() {
 	s.assertListContainsOnly([]string{"some_static_string"}, list)
 }
 
+func (s *endpointTestSuite) TestFilters() {
+	for query, expected := range map[string][]string{
+		"dynamic=true":                 {"some_dyn_stringslice", "some_dyn_json"},
+		"dynamic=false&changed=false":  {"some_static_float"},
+		"changed=1":                    {"some_static_string", "some_dyn_stringslice"},
+		"prefix=some_static_":          {"some_static_string", "some_static_float"},
+		"filter=JSON":                  {"some_dyn_json"},
+		"filter=text&prefix=some_dyn_": {"some_dyn_stringslice", "some_dyn_json"},
+		"filter=static+int":            {"some_static_float"},
+	} {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "/debug/dflag?"+query, nil)
+		list := s.processFlagSetJSONResponse(req)
+		s.assertListContainsOnly(expected, list)
+	}
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "/debug/dflag?dynamic=maybe", nil)
+	resp := httptest.NewRecorder()
+	s.endpoint.ListFlags(resp, req)
+	assert.Equal(s.T(), http.StatusBadRequest, resp.Code)
+}
+
 func (s *endpointTestSuite) TestCorrectlyRepresentsResources() {
 	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "/debug/dflag", nil)
 	list := s.processFlagSetJSONResponse(req)
@@ -93,6 +118,9 @@ func (s *endpointTestSuite) TestCorrectlyRepresentsResources() {
 		findFlagInFlagSetJSON("some_static_float", list),
 		"must correctly represent a static unchanged flag",
 	)
+	dyn := findFlagInFlagSetJSON("some_dyn_stringslice", list)
+	assert.NotEqual(s.T(), "", dyn.LastModified, "changed dynamic flag must have a last modified time")
+	dyn.LastModified = ""
 	assert.Equal(s.T(),
 		&flagJSON{
 			Name:         "some_dyn_stringslice",
@@ -102,11 +130,31 @@ func (s *endpointTestSuite) TestCorrectlyRepresentsResources() {
 			IsChanged:    true,
 			IsDynamic:    true,
 		},
-		findFlagInFlagSetJSON("some_dyn_stringslice", list),
+		dyn,
 		"must correctly represent a dynamic changed flag",
 	)
 }
 
+func (s *endpointTestSuite) TestListFlagsPaging() {
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "/debug/dflag?sort=changed&limit=1", nil)
+	list := s.processFlagSetJSONResponse(req)
+	assert.Equal(s.T(), 1, len(list.Flags), "limit must bound the page")
+	assert.True(s.T(), list.Flags[0].IsChanged, "changed flags must sort first")
+	assert.True(s.T(), list.Total > 1, "total must count all the matching flags")
+
+	req, _ = http.NewRequestWithContext(context.Background(), http.MethodGet, "/debug/dflag?sort=name&offset=1&limit=2", nil)
+	all := s.processFlagSetJSONResponse(req)
+	assert.Equal(s.T(), 2, len(all.Flags))
+	assert.True(s.T(), all.Flags[0].Name < all.Flags[1].Name, "must sort by name")
+
+	for _, query := range []string{"sort=size", "offset=-1", "limit=many"} {
+		req, _ = http.NewRequestWithContext(context.Background(), http.MethodGet, "/debug/dflag?"+query, nil)
+		resp := httptest.NewRecorder()
+		s.endpoint.ListFlags(resp, req)
+		assert.Equal(s.T(), http.StatusBadRequest, resp.Code, query)
+	}
+}
+
 func (s *endpointTestSuite) TestServesHTML() {
 	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "/debug/dflag", nil)
 	req.Header.Add("Accept", "application/xhtml+xml")
@@ -120,6 +168,57 @@ func (s *endpointTestSuite) TestServesHTML() {
 	assert.Contains(s.T(), out, "some_dyn_stringslice")
 }
 
+func (s *endpointTestSuite) TestServesText() {
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "/debug/dflag?format=txt&type=dynamic", nil)
+	req.Header.Add("Accept", "text/html")
+	resp := httptest.NewRecorder()
+	s.endpoint.ListFlags(resp, req)
+	assert.Equal(s.T(), http.StatusOK, resp.Code)
+	assert.Contains(s.T(), resp.Header().Get("Content-Type"), "text/plain")
+	lines := strings.Split(resp.Body.String(), "\n")
+	assert.Equal(s.T(), []string{
+		`some_dyn_json={"string":"foo","json":1337}`,
+		"some_dyn_stringslice=car,star",
+		"",
+	}, lines[2:])
+	assert.True(s.T(), strings.HasPrefix(lines[0], "# checksum_dynamic: "), "checksum comment expected")
+}
+
+func TestServesTextMultiLine(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynString(set, "some_dynstr", "line1\nline2=x", "multi-line string for testing")
+	dflag.DynString(set, "some_quoted", `"q"`, "quoted string for testing")
+	dflag.DynString(set, "some_plain", `a "b" c\d`, "plain string for testing")
+	e := NewFlagsEndpoint(set, "")
+	resp := httptest.NewRecorder()
+	e.ListFlags(resp, httptest.NewRequest(http.MethodGet, "/debug/dflag?format=txt", nil))
+	lines := strings.Split(resp.Body.String(), "\n")
+	assert.Equal(t, []string{
+		`some_dynstr="line1\nline2=x"`,
+		`some_plain=a "b" c\d`,
+		`some_quoted="\"q\""`,
+		"",
+	}, lines[2:])
+	v, err := strconv.Unquote(strings.TrimPrefix(lines[2], "some_dynstr="))
+	assert.NoError(t, err)
+	assert.Equal(t, "line1\nline2=x", v)
+}
+
+func (s *endpointTestSuite) TestServesCSV() {
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "/debug/dflag?format=csv&only_changed=true", nil)
+	resp := httptest.NewRecorder()
+	s.endpoint.ListFlags(resp, req)
+	assert.Equal(s.T(), http.StatusOK, resp.Code)
+	assert.Contains(s.T(), resp.Header().Get("Content-Type"), "text/csv")
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), [][]string{
+		{"name", "current_value", "default_value", "is_changed", "is_dynamic", "description"},
+		{"some_dyn_stringslice", "car,star", "foo,bar", "true", "true", "Some dynamic slice text"},
+		{"some_static_string", "yolololo", "trolololo", "true", "false", "Some static string text"},
+	}, records)
+}
+
 func (s *endpointTestSuite) processFlagSetJSONResponse(req *http.Request) *flagSetJSON {
 	resp := httptest.NewRecorder()
 	s.endpoint.ListFlags(resp, req)
@@ -152,3 +251,261 @@ type testJSON struct {
 	SomeString string `json:"string"`
 	SomeInt    int32  `json:"json"`
 }
+
+func TestSetFlagWithHook(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	hooked := []string{}
+	e := NewFlagsEndpoint(set, "/set").WithSetHook(func(name string) { hooked = append(hooked, name) })
+	req := httptest.NewRequest(http.MethodGet, "/set?name=some_dynint&value=42", nil)
+	resp := httptest.NewRecorder()
+	e.SetFlag(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, int64(42), dynInt.Get())
+	req = httptest.NewRequest(http.MethodGet, "/set?name=some_dynint&value=abc", nil)
+	resp = httptest.NewRecorder()
+	e.SetFlag(resp, req)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+	assert.Equal(t, []string{"some_dynint"}, hooked, "hook only called on success")
+}
+
+func TestBulkSetApplyAfter(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	maxConns := dflag.DynInt64(set, "max_conns", 10, "")
+	seen := int64(0)
+	dflag.DynInt64(set, "a_pool_size", 5, "").WithApplyAfter("max_conns").WithSyncNotifier(func(_, _ int64) {
+		seen = maxConns.Get()
+	})
+	e := NewFlagsEndpoint(set, "/set")
+	req := httptest.NewRequest(http.MethodPost, "/set", strings.NewReader(`{"a_pool_size": 50, "max_conns": 100}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	e.SetFlag(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	res := &BulkSetResponse{}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), res))
+	assert.Equal(t, "a_pool_size", res.Results[0].Name, "results still in name order")
+	assert.Equal(t, int64(100), seen, "max_conns set before a_pool_size")
+}
+
+func TestBulkSet(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	dynStr := dflag.DynString(set, "some_dynstr", "a", "dynamic string for testing")
+	dynJSON := dflag.DynJSON(set, "some_dynjson", &testJSON{SomeString: "foo"}, "dynamic json for testing")
+	set.Int("some_int", 1, "static int for testing")
+	hooked := []string{}
+	e := NewFlagsEndpoint(set, "/set").WithSetHook(func(name string) { hooked = append(hooked, name) })
+	bulk := func(body string) (int, *BulkSetResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/set", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		e.SetFlag(resp, req)
+		res := &BulkSetResponse{}
+		if resp.Header().Get("Content-Type") == "application/json" {
+			assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), res))
+		}
+		return resp.Code, res
+	}
+	code, res := bulk(`{"some_dynint": 42, "some_dynstr": "b", "some_int": "3", "nope": 1}`)
+	assert.Equal(t, http.StatusNotAcceptable, code)
+	assert.False(t, res.Applied)
+	assert.Equal(t, 4, len(res.Results))
+	assert.Equal(t, SetResult{Name: "nope", Value: "1", Error: `flag "nope" not found`}, res.Results[0])
+	assert.Equal(t, SetResult{Name: "some_dynint", Value: "42"}, res.Results[1])
+	assert.Equal(t, `flag "some_int" is not dynamic`, res.Results[3].Error)
+	assert.Equal(t, int64(1), dynInt.Get(), "nothing applied when one is invalid")
+	assert.Equal(t, "a", dynStr.Get())
+	code, res = bulk(`{"some_dynint": "abc", "some_dynstr": "b"}`)
+	assert.Equal(t, http.StatusNotAcceptable, code)
+	assert.Contains(t, res.Results[0].Error, "invalid syntax")
+	assert.Equal(t, "a", dynStr.Get())
+	code, res = bulk(`{"some_dynint": 42, "some_dynstr": "b", "some_dynjson": {"string": "bar", "json": 3}}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, res.Applied)
+	assert.Equal(t, int64(42), dynInt.Get())
+	assert.Equal(t, "b", dynStr.Get())
+	assert.Equal(t, &testJSON{SomeString: "bar", SomeInt: 3}, dynJSON.Get())
+	assert.Equal(t, []string{"some_dynint", "some_dynjson", "some_dynstr"}, hooked)
+	code, _ = bulk(`["not", "an object"]`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	// dynamic flags that can't be validated are refused, so nothing is partially applied.
+	set.Var(&notValidatable{}, "some_custom", "dynamic flag without validation for testing")
+	code, res = bulk(`{"some_dynint": 43, "some_custom": "x"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.False(t, res.Applied)
+	assert.Contains(t, res.Results[0].Error, "can't be validated")
+	assert.Equal(t, int64(42), dynInt.Get(), "nothing applied when one can't be validated")
+}
+
+func TestDryRun(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	set.Int("some_int", 1, "static int for testing")
+	e := NewFlagsEndpoint(set, "/set")
+	dryRun := func(query string) (int, *SetResult) {
+		resp := httptest.NewRecorder()
+		e.SetFlag(resp, httptest.NewRequest(http.MethodGet, "/set?dryrun=1&"+query, nil))
+		res := &SetResult{}
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), res))
+		return resp.Code, res
+	}
+	code, res := dryRun("name=some_dynint&value=42")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, SetResult{Name: "some_dynint", Value: "42"}, *res, "would succeed")
+	assert.Equal(t, int64(1), dynInt.Get(), "not applied")
+	code, res = dryRun("name=some_dynint&value=x")
+	assert.Equal(t, http.StatusNotAcceptable, code)
+	assert.Contains(t, res.Error, "invalid syntax")
+	code, _ = dryRun("name=some_int&value=2")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = dryRun("name=nope&value=2")
+	assert.Equal(t, http.StatusForbidden, code)
+	req := httptest.NewRequest(http.MethodPost, "/set?dryrun=true", strings.NewReader(`{"some_dynint": 42}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	e.SetFlag(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	bulk := &BulkSetResponse{}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), bulk))
+	assert.True(t, bulk.DryRun, "dry run")
+	assert.False(t, bulk.Applied, "dry run")
+	assert.Equal(t, int64(1), dynInt.Get(), "not applied")
+}
+
+// notValidatable is a dynamic flag value that doesn't implement dflag.DynamicFlagValidator.
+type notValidatable struct {
+	value string
+}
+
+func (n *notValidatable) String() string      { return n.value }
+func (n *notValidatable) Set(v string) error  { n.value = v; return nil }
+func (n *notValidatable) IsDynamicFlag() bool { return true }
+
+func TestGetFlag(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 42, "dynamic int for testing")
+	dflag.DynJSON(set, "some_dynjson", &testJSON{SomeString: "foo"}, "dynamic json for testing")
+	e := NewFlagsEndpoint(set, "").WithGetPrefix("/debug/flags/")
+	get := func(url string, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp := httptest.NewRecorder()
+		e.GetFlag(resp, req)
+		return resp
+	}
+	resp := get("/debug/flags/get?name=some_dynint", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "42", resp.Body.String())
+	assert.Contains(t, resp.Header().Get("Content-Type"), "text/plain")
+	resp = get("/debug/flags/some_dynjson", "")
+	assert.Equal(t, `{"string":"foo","json":0}`, resp.Body.String())
+	resp = get("/debug/flags/some_dynint?format=json", "")
+	fj := &flagJSON{}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), fj))
+	assert.Equal(t, &flagJSON{
+		Name: "some_dynint", Description: "dynamic int for testing", CurrentValue: "42", DefaultValue: "42", IsDynamic: true,
+		Kind: "int",
+	}, fj)
+	resp = get("/debug/flags/some_dynint", "application/json")
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	resp = get("/debug/flags/nope", "")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	resp = get("/debug/flags/", "")
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "no name")
+	resp = get("/other/some_dynint", "")
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "outside of the prefix")
+}
+
+func TestGetFlagWait(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(set, "some_dynint", 42, "dynamic int for testing")
+	set.Int("some_int", 1, "static int for testing")
+	e := NewFlagsEndpoint(set, "")
+	get := func(query string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		e.GetFlag(resp, httptest.NewRequest(http.MethodGet, "/debug/flags/get?"+query, nil))
+		return resp
+	}
+	resp := get("name=some_dynint")
+	assert.Equal(t, "0", resp.Header().Get(GenerationHeader))
+	resp = get("name=some_dynint&wait=10ms")
+	assert.Equal(t, http.StatusNotModified, resp.Code, "no change")
+	assert.Equal(t, "", resp.Body.String())
+	assert.Equal(t, http.StatusBadRequest, get("name=some_int&wait=10ms").Code, "static flag")
+	assert.Equal(t, http.StatusBadRequest, get("name=some_dynint&wait=x").Code, "bad wait")
+	assert.Equal(t, http.StatusBadRequest, get("name=some_dynint&wait=1s&last_generation=x").Code, "bad generation")
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- get("name=some_dynint&wait=1m&last_generation=0") }()
+	time.Sleep(20 * time.Millisecond)
+	assert.NoError(t, dynInt.SetV(43))
+	resp = <-done
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "43", resp.Body.String())
+	assert.Equal(t, "1", resp.Header().Get(GenerationHeader))
+	resp = get("name=some_dynint&wait=1m&last_generation=0")
+	assert.Equal(t, "43", resp.Body.String(), "already changed since last_generation")
+}
+
+func TestListFlagsEditors(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynBool(set, "some_dynbool", true, "dynamic bool for testing")
+	dflag.DynString(set, "some_dynenum", "fast", "dynamic enum for testing").WithOneOf("fast", "safe")
+	dflag.DynInt64(set, "some_dynint", 5, "dynamic int for testing").WithRange(1, 10)
+	dflag.DynFloat64(set, "some_dynfloat", 0.5, "dynamic float for testing")
+	dflag.DynJSON(set, "some_dynjson", &testJSON{SomeString: "foo"}, "dynamic json for testing")
+	e := NewFlagsEndpoint(set, "/set")
+	req := httptest.NewRequest(http.MethodGet, "/debug/flags", nil)
+	req.Header.Set("Accept", "text/html")
+	resp := httptest.NewRecorder()
+	e.ListFlags(resp, req)
+	html := resp.Body.String()
+	assert.Contains(t, html, `<input type="checkbox" name="value" value="true" checked />`)
+	assert.Contains(t, html, `<option value="fast" selected>fast</option><option value="safe">safe</option>`)
+	assert.Contains(t, html, `<input type="number" name="value" value="5" step="1" min="1" max="10" />`)
+	assert.Contains(t, html, `<input type="number" name="value" value="0.5" step="any" />`)
+	assert.Contains(t, html, `onclick="dflagPretty(this.form)"`)
+	assert.Contains(t, html, `onsubmit="return dflagSubmit(this)"`)
+	resp = httptest.NewRecorder()
+	e.ListFlags(resp, httptest.NewRequest(http.MethodGet, "/debug/flags?format=json", nil))
+	list := &flagSetJSON{}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), list))
+	kinds := map[string]*flagJSON{}
+	for _, fj := range list.Flags {
+		kinds[fj.Name] = fj
+	}
+	assert.Equal(t, "bool", kinds["some_dynbool"].Kind)
+	assert.Equal(t, []string{"fast", "safe"}, kinds["some_dynenum"].Choices)
+	assert.Equal(t, []string{"1", "10"}, kinds["some_dynint"].Range)
+	assert.Equal(t, "json", kinds["some_dynjson"].Kind)
+}
+
+func TestWithLogger(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	logger := &dflagtest.Logger{}
+	e := NewFlagsEndpoint(set, "/set").WithLogger(logger)
+	for _, query := range []string{"name=some_dynint&value=2", "name=missing&value=3"} {
+		e.SetFlag(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/set?"+query, nil))
+	}
+	resp := httptest.NewRecorder()
+	e.GetFlag(resp, httptest.NewRequest(http.MethodGet, "/get", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	infos := logger.Entries("info")
+	assert.Equal(t, 4, len(infos), "3 requests and 1 audit entry")
+	assert.Equal(t, "SetFlag", infos[0].Msg)
+	assert.Equal(t, "/set?name=some_dynint&value=2", infos[0].Attr("url"))
+	assert.Equal(t, "dflag audit", infos[1].Msg)
+	assert.Equal(t, true, infos[1].Attr("success"))
+	assert.Equal(t, "GetFlag", infos[3].Msg)
+	warnings := logger.Entries("warn")
+	assert.Equal(t, 1, len(warnings))
+	assert.Equal(t, "missing", warnings[0].Attr("flag"))
+	errs := logger.Entries("error")
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, "Flag not found", errs[0].Msg)
+	assert.Equal(t, "Missing flag name", errs[1].Msg)
+	assert.Equal(t, http.StatusBadRequest, errs[1].Attr("status"))
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+
+	"fortio.org/dflag"
+)
+
+// listETag returns the weak ETag of a listing reply: the FlagSet's generation (see dflag.GenerationFlagSet), its
+// number of flags and of set ones (for new and static flags) and a hash of the query and representation.
+// Empty when the reply includes a CSRF token, which differs for each request.
+func (e *FlagsEndpoint) listETag(req *http.Request) string {
+	if e.setURL != "" && e.csrfSecret != nil {
+		return ""
+	}
+	numFlags := 0
+	e.flags().VisitAll(func(*flag.Flag) { numFlags++ })
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(req.URL.Path + "?" + req.URL.RawQuery))
+	if requestIsBrowser(req) {
+		_, _ = h.Write([]byte("|html"))
+	}
+	return fmt.Sprintf(`W/"%d.%d.%d-%x"`, dflag.GenerationFlagSet(e.flags()), numFlags, e.flags().NFlag(), h.Sum32())
+}
+
+// notModified sets the ETag header and, if the request's If-None-Match matches it, replies with
+// a 304 Not Modified and returns true.
+func notModified(resp http.ResponseWriter, req *http.Request, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	resp.Header().Set("ETag", etag)
+	resp.Header().Add("Vary", "Accept")
+	for _, candidate := range strings.Split(req.Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			resp.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestETag(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	set.Int("some_int", 1, "static int for testing")
+	e := NewFlagsEndpoint(set, "/set")
+	list := func(url, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		resp := httptest.NewRecorder()
+		e.ListFlags(resp, req)
+		return resp
+	}
+	resp := list("/debug/flags", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	etag := resp.Header().Get("ETag")
+	assert.NotEqual(t, "", etag)
+	resp = list("/debug/flags", etag)
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+	assert.Equal(t, "", resp.Body.String())
+	assert.Equal(t, http.StatusNotModified, list("/debug/flags", `"other", `+etag).Code, "one of the etags")
+	assert.Equal(t, http.StatusOK, list("/debug/flags?format=txt", etag).Code, "other representation")
+	assert.NoError(t, set.Set("some_dynint", "2"))
+	assert.Equal(t, http.StatusOK, list("/debug/flags", etag).Code, "dynamic flag changed")
+	etag = list("/debug/flags", "").Header().Get("ETag")
+	assert.NoError(t, set.Set("some_int", "2"))
+	assert.Equal(t, http.StatusOK, list("/debug/flags", etag).Code, "static flag set")
+	etag = list("/debug/flags", "").Header().Get("ETag")
+	dflag.DynString(set, "some_dynstr", "a", "dynamic string for testing")
+	assert.Equal(t, http.StatusOK, list("/debug/flags", etag).Code, "new flag")
+	resp = httptest.NewRecorder()
+	e.DiffFlags(resp, httptest.NewRequest(http.MethodGet, "/debug/flags/diff", nil))
+	assert.NotEqual(t, "", resp.Header().Get("ETag"))
+	e.WithCSRFProtection(nil)
+	assert.Equal(t, "", list("/debug/flags", "").Header().Get("ETag"), "replies have a CSRF token")
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+
+	"fortio.org/dflag"
+)
+
+// DefaultConfigMapName is the exported ConfigMap's name when not specified.
+const DefaultConfigMapName = "dflag"
+
+// yamlString returns s as a YAML double quoted scalar (JSON strings are valid ones).
+func yamlString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// ExportConfigMap returns a Kubernetes ConfigMap manifest, ready to `kubectl apply`, with one key per changed
+// dynamic flag (or all the dynamic flags with `all=true`), so runtime tuning can be captured back into the
+// declarative configuration read by the configmap package. The `name` (defaults to DefaultConfigMapName) and
+// `namespace` query parameters set the manifest's metadata. []byte flags are in `binaryData`.
+// With `format=json`, `yaml` or `env` the reply is instead the dflag.Dump of all the flags (value, default,
+// dynamic, changed and source).
+func (e *FlagsEndpoint) ExportConfigMap(resp http.ResponseWriter, req *http.Request) {
+	e.logRequest(req, "ExportConfigMap")
+	if !e.authorized(resp, req, false) {
+		return
+	}
+	q := req.URL.Query()
+	if format := dflag.DumpFormat(q.Get("format")); format != "" {
+		e.dump(resp, format)
+		return
+	}
+	all := q.Get("all") == "true"
+	name := q.Get("name")
+	if name == "" {
+		name = DefaultConfigMapName
+	}
+	data, binaryData := &bytes.Buffer{}, &bytes.Buffer{}
+	e.flags().VisitAll(func(f *flag.Flag) {
+		if !dflag.IsFlagDynamic(f) || (!all && !dflag.IsChanged(f)) {
+			return
+		}
+		out := data
+		if dflag.IsBinary(f) != nil {
+			out = binaryData // String() is already base64 encoded.
+		}
+		fmt.Fprintf(out, "  %s: %s\n", yamlString(f.Name), yamlString(f.Value.String()))
+	})
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\n", yamlString(name))
+	if ns := q.Get("namespace"); ns != "" {
+		fmt.Fprintf(buf, "  namespace: %s\n", yamlString(ns))
+	}
+	if data.Len() > 0 {
+		buf.WriteString("data:\n")
+		buf.Write(data.Bytes())
+	}
+	if binaryData.Len() > 0 {
+		buf.WriteString("binaryData:\n")
+		buf.Write(binaryData.Bytes())
+	}
+	resp.Header().Set("Content-Type", "application/yaml; charset=UTF-8")
+	_, _ = resp.Write(buf.Bytes())
+}
+
+// dumpContentTypes are the content types of the dflag.Dump formats.
+var dumpContentTypes = map[dflag.DumpFormat]string{
+	dflag.DumpJSON: "application/json",
+	dflag.DumpYAML: "application/yaml; charset=UTF-8",
+	dflag.DumpEnv:  "text/plain; charset=UTF-8",
+}
+
+// dump replies with the dflag.Dump of the flags in the format.
+func (e *FlagsEndpoint) dump(resp http.ResponseWriter, format dflag.DumpFormat) {
+	out, err := dflag.Dump(e.flags(), format)
+	if err != nil {
+		e.httpErrf(resp, http.StatusBadRequest, "%v", err)
+		return
+	}
+	resp.Header().Set("Content-Type", dumpContentTypes[format])
+	_, _ = resp.Write([]byte(out))
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestExportConfigMap(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	dflag.DynString(set, "some_dynstr", "a", "dynamic string for testing")
+	dflag.Dyn(set, "some_bin", []byte{}, "dynamic binary for testing")
+	set.Int("some_int", 1, "static int for testing")
+	assert.NoError(t, set.Set("some_dynstr", "line1\nline2"))
+	assert.NoError(t, set.Set("some_bin", "AAE="))
+	assert.NoError(t, set.Set("some_int", "2"))
+	e := NewFlagsEndpoint(set, "")
+	export := func(query string) string {
+		resp := httptest.NewRecorder()
+		e.ExportConfigMap(resp, httptest.NewRequest(http.MethodGet, "/debug/flags/export?"+query, nil))
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "application/yaml; charset=UTF-8", resp.Header().Get("Content-Type"))
+		return resp.Body.String()
+	}
+	assert.Equal(t, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: "dflag"
+data:
+  "some_dynstr": "line1\nline2"
+binaryData:
+  "some_bin": "AAE="
+`, export(""))
+	assert.Equal(t, `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: "app"
+  namespace: "prod"
+data:
+  "some_dynint": "1"
+  "some_dynstr": "line1\nline2"
+binaryData:
+  "some_bin": "AAE="
+`, export("all=true&name=app&namespace=prod"))
+}
+
+func TestExportDump(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	e := NewFlagsEndpoint(set, "")
+	export := func(query string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		e.ExportConfigMap(resp, httptest.NewRequest(http.MethodGet, "/debug/flags/export?"+query, nil))
+		return resp
+	}
+	resp := export("format=env")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/plain; charset=UTF-8", resp.Header().Get("Content-Type"))
+	assert.Equal(t, "SOME_DYNINT='1'\n", resp.Body.String())
+	resp = export("format=json")
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Body.String(), `"name": "some_dynint"`)
+	assert.Equal(t, http.StatusBadRequest, export("format=xml").Code)
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"flag"
+	"sync"
+)
+
+// flagSection is a FlagSet added with WithFlagSet.
+type flagSection struct {
+	prefix  string
+	flagSet *flag.FlagSet
+}
+
+// combinedFlags is the FlagSet of all the flags, with the sections' prefixes, when WithFlagSet is used.
+type combinedFlags struct {
+	mu       sync.Mutex
+	flagSet  *flag.FlagSet
+	count    int               // number of flags the flagSet was built from.
+	sections map[string]string // flag name to section prefix.
+}
+
+// WithFlagSet adds the flags of another FlagSet (e.g. of a library) to the endpoint, their names prefixed
+// by prefix (e.g. "grpc.") which is also the section they are listed under. The allow/deny lists,
+// exports and imports use the prefixed names. Flags set through the endpoint are not marked as set
+// (flag.Visit) in their own FlagSet, only their value changes.
+func (e *FlagsEndpoint) WithFlagSet(prefix string, flagSet *flag.FlagSet) *FlagsEndpoint {
+	e.sections = append(e.sections, flagSection{prefix: prefix, flagSet: flagSet})
+	e.combined = &combinedFlags{}
+	return e
+}
+
+// flags returns the FlagSet the handlers work on: the NewFlagsEndpoint one, or the combination of
+// all of them when WithFlagSet is used (rebuilt when flags are added to any of them).
+func (e *FlagsEndpoint) flags() *flag.FlagSet {
+	if e.combined == nil {
+		return e.flagSet
+	}
+	c := e.combined
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count := countFlags(e.flagSet)
+	for _, s := range e.sections {
+		count += countFlags(s.flagSet)
+	}
+	if c.flagSet != nil && count == c.count {
+		return c.flagSet
+	}
+	c.flagSet = flag.NewFlagSet("dflag endpoint", flag.ContinueOnError)
+	c.count = count
+	c.sections = make(map[string]string)
+	add := func(prefix string, f *flag.Flag) {
+		name := prefix + f.Name
+		if c.flagSet.Lookup(name) != nil {
+			e.logger().Warn("dflag: duplicate flag name in endpoint sections, ignored", "flag", name)
+			return
+		}
+		c.flagSet.Var(f.Value, name, f.Usage)
+		c.flagSet.Lookup(name).DefValue = f.DefValue
+		c.sections[name] = prefix
+	}
+	e.flagSet.VisitAll(func(f *flag.Flag) { add("", f) })
+	for _, s := range e.sections {
+		s.flagSet.VisitAll(func(f *flag.Flag) { add(s.prefix, f) })
+	}
+	return c.flagSet
+}
+
+// section returns the prefix of the section of the flag, empty for the main FlagSet.
+func (e *FlagsEndpoint) section(name string) string {
+	if e.combined == nil {
+		return ""
+	}
+	e.combined.mu.Lock()
+	defer e.combined.mu.Unlock()
+	return e.combined.sections[name]
+}
+
+func countFlags(flagSet *flag.FlagSet) int {
+	n := 0
+	flagSet.VisitAll(func(*flag.Flag) { n++ })
+	return n
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestWithFlagSet(t *testing.T) {
+	set := flag.NewFlagSet("main", flag.ContinueOnError)
+	dflag.DynInt64(set, "z_dynint", 1, "main dynamic int")
+	lib := flag.NewFlagSet("lib", flag.ContinueOnError)
+	libInt := dflag.DynInt64(lib, "timeout", 2, "library dynamic int")
+	e := NewFlagsEndpoint(set, "/debug/flags/set").WithFlagSet("lib.", lib)
+	list := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/debug/flags", nil)
+		req.Header.Set("Accept", accept)
+		resp := httptest.NewRecorder()
+		e.ListFlags(resp, req)
+		return resp
+	}
+	var res flagSetJSON
+	assert.NoError(t, json.Unmarshal(list("application/json").Body.Bytes(), &res))
+	names := []string{}
+	for _, f := range res.Flags {
+		names = append(names, f.Section+"|"+f.Name)
+	}
+	assert.Equal(t, "|z_dynint,lib.|lib.timeout", strings.Join(names, ","), "main section first")
+
+	resp := httptest.NewRecorder()
+	e.SetFlag(resp, httptest.NewRequest(http.MethodPost, "/debug/flags/set?name=lib.timeout&value=5", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, int64(5), libInt.Get())
+
+	// flags registered later show up too.
+	dflag.DynString(lib, "name", "x", "late library flag")
+	res = flagSetJSON{}
+	assert.NoError(t, json.Unmarshal(list("application/json").Body.Bytes(), &res))
+	assert.Equal(t, 3, len(res.Flags))
+	assert.Contains(t, list("text/html").Body.String(), "<h2><code>lib.</code> flags</h2>")
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// singleLine returns the value with JSON values compacted back to a single line (for txt and csv outputs).
+func singleLine(fj *flagJSON, value string) string {
+	if !fj.IsJSON {
+		return value
+	}
+	out := &bytes.Buffer{}
+	if err := json.Compact(out, []byte(value)); err != nil {
+		return value
+	}
+	return out.String()
+}
+
+// textValue returns the value as is, or Go quoted (strconv.Quote) when it would break the `name=value`
+// line format (newlines and other control characters) or starts with a quote, so quoted values are unambiguous.
+func textValue(value string) string {
+	if strings.HasPrefix(value, `"`) || strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// writeText outputs one `name=value` line per flag, preceded by `#` comment lines with the checksums,
+// for quick curl/grep workflows. Multi-line values are quoted, see textValue.
+func writeText(resp http.ResponseWriter, fsj *flagSetJSON) {
+	resp.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	resp.WriteHeader(http.StatusOK)
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "# checksum_dynamic: %s\n# checksum_static: %s\n", fsj.ChecksumDynamic, fsj.ChecksumStatic)
+	for _, fj := range fsj.Flags {
+		fmt.Fprintf(buf, "%s=%s\n", fj.Name, textValue(singleLine(fj, fj.CurrentValue)))
+	}
+	_, _ = resp.Write(buf.Bytes())
+}
+
+// writeCSV outputs the flags as CSV with a header row, e.g. for spreadsheet import during incident reviews.
+func writeCSV(resp http.ResponseWriter, fsj *flagSetJSON) {
+	resp.Header().Set("Content-Type", "text/csv; charset=UTF-8")
+	resp.WriteHeader(http.StatusOK)
+	w := csv.NewWriter(resp)
+	_ = w.Write([]string{"name", "current_value", "default_value", "is_changed", "is_dynamic", "description"})
+	for _, fj := range fsj.Flags {
+		_ = w.Write([]string{
+			fj.Name, singleLine(fj, fj.CurrentValue), singleLine(fj, fj.DefaultValue),
+			strconv.FormatBool(fj.IsChanged), strconv.FormatBool(fj.IsDynamic), fj.Description,
+		})
+	}
+	w.Flush()
+}
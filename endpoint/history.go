@@ -0,0 +1,67 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"fortio.org/dflag"
+)
+
+// historyJSON is the FlagHistory reply, and what its HTML template is executed with.
+type historyJSON struct {
+	Name         string               `json:"name"`
+	DefaultValue string               `json:"default_value"`
+	CurrentValue string               `json:"current_value"`
+	History      []dflag.HistoryEntry `json:"history"`
+	StyleSheet   string               `json:"-"`
+}
+
+// setSource is the source recorded in the history of the flags set by the request.
+func (e *FlagsEndpoint) setSource(req *http.Request) string {
+	if user := e.user(req); user != "" {
+		return "endpoint (" + user + ")"
+	}
+	return "endpoint"
+}
+
+// FlagHistory returns the values the `name` flag was set to, oldest first, with their time and source
+// (e.g. registered as `/debug/flags/history`), for flags using dflag's WithHistory: an HTML table for browsers,
+// JSON otherwise (or when `format=json` is passed).
+func (e *FlagsEndpoint) FlagHistory(resp http.ResponseWriter, req *http.Request) {
+	e.logRequest(req, "FlagHistory")
+	if !e.authorized(resp, req, false) {
+		return
+	}
+	name := req.FormValue("name")
+	f := e.flags().Lookup(name)
+	if f == nil {
+		e.httpErrf(resp, http.StatusNotFound, "Flag %q not found", name)
+		return
+	}
+	h, ok := f.Value.(dflag.DynamicFlagHistory)
+	if !ok || h.History() == nil {
+		e.httpErrf(resp, http.StatusNotFound, "History is not enabled for flag %q", name)
+		return
+	}
+	data := &historyJSON{
+		Name:         name,
+		DefaultValue: f.DefValue,
+		CurrentValue: f.Value.String(),
+		History:      h.History(),
+		StyleSheet:   e.styleSheetOrDefault(),
+	}
+	if requestIsBrowser(req) && req.URL.Query().Get("format") != "json" {
+		e.writeHTML(resp, nil, dflagHistoryTemplate, data)
+		return
+	}
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		resp.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	_, _ = resp.Write(out)
+}
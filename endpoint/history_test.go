@@ -0,0 +1,52 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestFlagHistory(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing").WithHistory(10)
+	dflag.DynString(set, "some_dynstr", "a", "dynamic string for testing")
+	e := NewFlagsEndpoint(set, "/debug/flags/set")
+	history := func(name, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/debug/flags/history?name="+name, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp := httptest.NewRecorder()
+		e.FlagHistory(resp, req)
+		return resp
+	}
+	assert.Equal(t, http.StatusNotFound, history("unknown", "").Code)
+	assert.Equal(t, http.StatusNotFound, history("some_dynstr", "").Code, "history not enabled")
+	assert.Contains(t, history("some_dynint", "text/html").Body.String(), "The flag wasn't changed yet")
+	assert.NoError(t, set.Set("some_dynint", "2"))
+	req := httptest.NewRequest(http.MethodPost, "/debug/flags/set?name=some_dynint&value=3", nil)
+	req.SetBasicAuth("alice", "secret")
+	resp := httptest.NewRecorder()
+	e.SetFlag(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var h historyJSON
+	assert.NoError(t, json.Unmarshal(history("some_dynint", "").Body.Bytes(), &h))
+	assert.Equal(t, "1", h.DefaultValue)
+	assert.Equal(t, "3", h.CurrentValue)
+	assert.Equal(t, 2, len(h.History))
+	assert.Equal(t, "2", h.History[0].Value)
+	assert.Equal(t, "", h.History[0].Source)
+	assert.Equal(t, "3", h.History[1].Value)
+	assert.Equal(t, "endpoint (alice)", h.History[1].Source)
+	html := history("some_dynint", "text/html").Body.String()
+	assert.Contains(t, html, "endpoint (alice)")
+	assert.False(t, json.Valid([]byte(html)), "html")
+}
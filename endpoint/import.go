@@ -0,0 +1,141 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"fortio.org/dflag"
+	"fortio.org/dflag/httppoll"
+	"fortio.org/dflag/source"
+)
+
+// configMapValues returns the flag values of a ConfigMap manifest (data, and base64 decoded binaryData),
+// or nil if values isn't one.
+func configMapValues(values map[string][]byte) (map[string][]byte, error) {
+	if string(values["kind"]) != "ConfigMap" {
+		return nil, nil
+	}
+	res := make(map[string][]byte)
+	var data, binaryData map[string]string
+	if d, found := values["data"]; found {
+		if err := json.Unmarshal(d, &data); err != nil {
+			return nil, fmt.Errorf("ConfigMap data: %w", err)
+		}
+	}
+	if d, found := values["binaryData"]; found {
+		if err := json.Unmarshal(d, &binaryData); err != nil {
+			return nil, fmt.Errorf("ConfigMap binaryData: %w", err)
+		}
+	}
+	for name, v := range data {
+		res[name] = []byte(v)
+	}
+	for name, v := range binaryData {
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("ConfigMap binaryData %v: %w", name, err)
+		}
+		res[name] = b
+	}
+	return res, nil
+}
+
+// ImportFlags applies a POSTed JSON map of flag name to value, or a ConfigMap manifest (e.g. from
+// ExportConfigMap or a config render to test against a staging instance) the same way the configmap
+// directory updater does (see source.SetFlag): dynamic flags only, each value validated. YAML, with an
+// `application/yaml` Content-Type, requires importing fortio.org/dflag/httppoll/yaml. It is a set
+// request (needs the setURL, WithSetAuth and flags allowed by the Mutable configuration) and the reply is
+// a JSON BulkSetResponse; flags are applied independently so some can fail while the others are set.
+func (e *FlagsEndpoint) ImportFlags(resp http.ResponseWriter, req *http.Request) {
+	e.logRequest(req, "ImportFlags")
+	if e.setURL == "" {
+		e.httpErrf(resp, http.StatusForbidden, "setting flags is not enabled")
+		return
+	}
+	if !e.authorized(resp, req, true) {
+		return
+	}
+	if req.Method != http.MethodPost {
+		resp.Header().Set("Allow", http.MethodPost)
+		e.httpErrf(resp, http.StatusMethodNotAllowed, "Importing flags requires a POST")
+		return
+	}
+	release, ok := e.setGuard(resp, req)
+	if !ok {
+		return
+	}
+	defer release()
+	// JSON and YAML aren't simple content types: browsers don't send them cross origin without a
+	// CORS preflight, so no CSRF check is needed.
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	isYAML := strings.HasSuffix(mediaType, "yaml")
+	if mediaType != "application/json" && !isYAML {
+		e.httpErrf(resp, http.StatusUnsupportedMediaType, "Expecting a JSON or YAML body, got %q", mediaType)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(resp, req.Body, MaxBulkSetBodySize))
+	if err != nil {
+		e.httpErrf(resp, http.StatusBadRequest, "Error reading body: %v", err)
+		return
+	}
+	values, err := httppoll.ParseValues(body, isYAML)
+	if err == nil {
+		var cm map[string][]byte
+		if cm, err = configMapValues(values); cm != nil {
+			values = cm
+		}
+	}
+	if err != nil {
+		e.httpErrf(resp, http.StatusBadRequest, "Invalid body, expecting a map of flag name to value or a ConfigMap: %v", err)
+		return
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	res := BulkSetResponse{Applied: true, Results: make([]SetResult, len(names))}
+	for _, i := range applyOrder(names, e.flags()) {
+		name := names[i]
+		r := &res.Results[i]
+		*r = SetResult{Name: name, Value: string(values[name])}
+		oldValue := ""
+		f := e.flags().Lookup(name)
+		if f != nil {
+			oldValue = f.Value.String()
+		}
+		if f != nil && dflag.IsFlagDynamic(f) && !e.Mutable(f) {
+			err = errNotMutable
+		} else {
+			err = source.SetFlagWithLogger(e.logger(), e.flags(), name, values[name], true /* dynamicOnly */)
+		}
+		e.audit(req, name, oldValue, r.Value, err)
+		if err != nil {
+			r.Error = err.Error()
+			res.Applied = false
+			continue
+		}
+		r.Applied = true
+		for _, hook := range e.setHooks {
+			hook(name)
+		}
+	}
+	e.logger().Info("dflag: import", "flags", len(names), "applied", res.Applied)
+	e.redactResults(res.Results)
+	status := http.StatusOK
+	if !res.Applied {
+		status = http.StatusMultiStatus
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(status)
+	_ = json.NewEncoder(resp).Encode(res)
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestImportFlags(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	dynStr := dflag.DynString(set, "some_dynstr", "a", "dynamic string for testing")
+	dynBin := dflag.Dyn(set, "some_bin", []byte{}, "dynamic binary for testing")
+	secret := dflag.DynString(set, "some_secret", "s", "dynamic string for testing")
+	staticInt := set.Int("some_int", 1, "static int for testing")
+	e, err := NewFlagsEndpoint(set, "/set").WithSetDenyList("*secret")
+	assert.NoError(t, err)
+	post := func(contentType, body string) (int, *BulkSetResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/debug/flags/import", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		resp := httptest.NewRecorder()
+		e.ImportFlags(resp, req)
+		res := &BulkSetResponse{}
+		if resp.Header().Get("Content-Type") == "application/json" {
+			assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), res))
+		}
+		return resp.Code, res
+	}
+	code, res := post("application/json", `{"some_dynint": 42, "some_dynstr": "b"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, res.Applied, "all applied")
+	assert.Equal(t, int64(42), dynInt.Get())
+	assert.Equal(t, "b", dynStr.Get())
+	code, res = post("application/json", `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "dflag"},
+		"data": {"some_dynint": "x", "some_dynstr": "c", "some_int": "2", "some_secret": "t", "nope": "1"},
+		"binaryData": {"some_bin": "AAE="}}`)
+	assert.Equal(t, http.StatusMultiStatus, code)
+	assert.False(t, res.Applied, "some failed")
+	results := map[string]SetResult{}
+	for _, r := range res.Results {
+		results[r.Name] = r
+	}
+	assert.Equal(t, 6, len(results))
+	assert.True(t, results["some_dynstr"].Applied, "valid value applied")
+	assert.True(t, results["some_bin"].Applied, "binary applied")
+	assert.Contains(t, results["some_dynint"].Error, "invalid syntax")
+	assert.Equal(t, "flag is not dynamic", results["some_int"].Error)
+	assert.Equal(t, errNotMutable.Error(), results["some_secret"].Error)
+	assert.Equal(t, "flag not found", results["nope"].Error)
+	assert.Equal(t, "c", dynStr.Get())
+	assert.Equal(t, []byte{0, 1}, dynBin.Get())
+	assert.Equal(t, int64(42), dynInt.Get())
+	assert.Equal(t, "s", secret.Get())
+	assert.Equal(t, 1, *staticInt)
+	code, _ = post("application/yaml", "some_dynint: 3")
+	assert.Equal(t, http.StatusBadRequest, code, "YAML not enabled")
+	code, _ = post("text/plain", "some_dynint=3")
+	assert.Equal(t, http.StatusUnsupportedMediaType, code)
+	resp := httptest.NewRecorder()
+	e.ImportFlags(resp, httptest.NewRequest(http.MethodGet, "/debug/flags/import", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+}
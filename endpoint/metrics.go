@@ -0,0 +1,69 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"fortio.org/dflag"
+)
+
+// MetricName is the name of the gauge, labeled by flag name, of the values served by Metrics.
+const MetricName = "dflag_value"
+
+// metricValue returns the gauge value of the numeric (durations in seconds) and bool dynamic flags.
+func metricValue(v flag.Value) (float64, bool) {
+	switch d := v.(type) {
+	case *dflag.DynValue[int64]:
+		return float64(d.Get()), true
+	case *dflag.DynValue[float64]:
+		return d.Get(), true
+	case *dflag.DynValue[time.Duration]:
+		return d.Get().Seconds(), true
+	case *dflag.DynValue[bool]:
+		return boolMetric(d.Get()), true
+	default:
+		return 0, false
+	}
+}
+
+func boolMetric(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Metrics exports the numeric and bool dynamic flags as a `dflag_value{flag="name"}` gauge in the
+// Prometheus text format (e.g. registered as `/debug/flags/metrics` and scraped, or proxied by the
+// service's own metrics handler), so deployed config values are visible alongside behavior metrics.
+// Bools are 0 or 1 and durations in seconds. The Sensitive flags aren't exported.
+func (e *FlagsEndpoint) Metrics(resp http.ResponseWriter, req *http.Request) {
+	e.logRequest(req, "Metrics")
+	if !e.authorized(resp, req, false) {
+		return
+	}
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "# HELP %s Current value of the numeric and bool dynamic flags.\n# TYPE %s gauge\n",
+		MetricName, MetricName)
+	e.flags().VisitAll(func(f *flag.Flag) {
+		if dflag.IsSensitive(f) {
+			return
+		}
+		if v, ok := metricValue(f.Value); ok {
+			fmt.Fprintf(buf, "%s{flag=\"%s\"} %s\n", MetricName, labelEscaper.Replace(f.Name),
+				strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	})
+	resp.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = resp.Write(buf.Bytes())
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestMetrics(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 42, "dynamic int for testing")
+	dflag.DynFloat64(set, "some_dynfloat", 1.5, "dynamic float for testing")
+	dflag.DynBool(set, "some_dynbool", true, "dynamic bool for testing")
+	dflag.DynDuration(set, "some_dyndur", 1500*time.Millisecond, "dynamic duration for testing")
+	dflag.DynString(set, "some_dynstr", "a", "dynamic string for testing")
+	set.Int("some_int", 1, "static int for testing")
+	e := NewFlagsEndpoint(set, "")
+	resp := httptest.NewRecorder()
+	e.Metrics(resp, httptest.NewRequest(http.MethodGet, "/debug/flags/metrics", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `# HELP dflag_value Current value of the numeric and bool dynamic flags.
+# TYPE dflag_value gauge
+dflag_value{flag="some_dynbool"} 1
+dflag_value{flag="some_dyndur"} 1.5
+dflag_value{flag="some_dynfloat"} 1.5
+dflag_value{flag="some_dynint"} 42
+`, resp.Body.String())
+}
+
+func TestMetricsSensitive(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 42, "dynamic int for testing")
+	dflag.DynInt64(set, "secret_int", 7351, "sensitive int for testing").Sensitive()
+	dflag.DynFloat64(set, "secret_float", 86.42, "sensitive float for testing").Sensitive()
+	dflag.DynBool(set, "secret_bool", true, "sensitive bool for testing").Sensitive()
+	dflag.DynDuration(set, "secret_dur", time.Hour, "sensitive duration for testing").Sensitive()
+	e := NewFlagsEndpoint(set, "")
+	resp := httptest.NewRecorder()
+	e.Metrics(resp, httptest.NewRequest(http.MethodGet, "/debug/flags/metrics", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `# HELP dflag_value Current value of the numeric and bool dynamic flags.
+# TYPE dflag_value gauge
+dflag_value{flag="some_dynint"} 42
+`, resp.Body.String())
+}
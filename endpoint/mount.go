@@ -0,0 +1,57 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Middleware wraps a handler, e.g. for authentication, logging, compression or tracing.
+type Middleware func(next http.Handler) http.Handler
+
+// WithMiddleware adds middlewares wrapping the handlers registered by Mount (and returned by Handler),
+// the first one being the outermost.
+func (e *FlagsEndpoint) WithMiddleware(middlewares ...Middleware) *FlagsEndpoint {
+	e.middlewares = append(e.middlewares, middlewares...)
+	return e
+}
+
+func (e *FlagsEndpoint) wrap(h http.HandlerFunc) http.Handler {
+	var res http.Handler = h
+	for i := len(e.middlewares) - 1; i >= 0; i-- {
+		res = e.middlewares[i](res)
+	}
+	return res
+}
+
+// Mount registers all the handlers on mux under prefix (e.g. "/debug/flags"), wrapped by the WithMiddleware
+// ones: ListFlags on prefix, GetFlag on prefix/{name} (see WithGetPrefix), DiffFlags on prefix/diff,
+// FlagHistory on prefix/history, Metrics on prefix/metrics, OpenAPI on prefix/openapi.json, ExportConfigMap on prefix/export, AuditLog on prefix/audit when WithAuditHistory is used and, when
+// setting flags is enabled, SetFlag on the setURL and ImportFlags on prefix/import.
+func (e *FlagsEndpoint) Mount(mux *http.ServeMux, prefix string) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	e.WithGetPrefix(prefix + "/")
+	mux.Handle(prefix, e.wrap(e.ListFlags))
+	mux.Handle(prefix+"/", e.wrap(e.GetFlag))
+	mux.Handle(prefix+"/diff", e.wrap(e.DiffFlags))
+	mux.Handle(prefix+"/history", e.wrap(e.FlagHistory))
+	mux.Handle(prefix+"/metrics", e.wrap(e.Metrics))
+	mux.Handle(prefix+"/openapi.json", e.wrap(e.OpenAPI))
+	mux.Handle(prefix+"/export", e.wrap(e.ExportConfigMap))
+	if e.auditHistory != nil {
+		mux.Handle(prefix+"/audit", e.wrap(e.AuditLog))
+	}
+	if e.setURL != "" {
+		mux.Handle(e.setURL, e.wrap(e.SetFlag))
+		mux.Handle(prefix+"/import", e.wrap(e.ImportFlags))
+	}
+}
+
+// Handler returns an http.Handler serving all the handlers under prefix, see Mount.
+func (e *FlagsEndpoint) Handler(prefix string) http.Handler {
+	mux := http.NewServeMux()
+	e.Mount(mux, prefix)
+	return mux
+}
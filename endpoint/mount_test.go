@@ -0,0 +1,59 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestMount(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	var calls []string
+	tag := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	e := NewFlagsEndpoint(set, "/debug/flags/set").WithAuditHistory(10).WithMiddleware(tag("outer"), tag("inner"))
+	srv := httptest.NewServer(e.Handler("/debug/flags/"))
+	defer srv.Close()
+	get := func(path string) (int, string) {
+		resp, err := http.Get(srv.URL + path)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		buf := &strings.Builder{}
+		_, _ = io.Copy(buf, resp.Body)
+		return resp.StatusCode, buf.String()
+	}
+	code, body := get("/debug/flags?format=txt")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "some_dynint=1")
+	assert.Equal(t, []string{"outer", "inner"}, calls)
+	code, _ = get("/debug/flags/set?name=some_dynint&value=2")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int64(2), dynInt.Get())
+	code, body = get("/debug/flags/some_dynint")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "2", body)
+	for _, path := range []string{"/debug/flags/diff", "/debug/flags/export", "/debug/flags/audit"} {
+		code, _ = get(path)
+		assert.Equal(t, http.StatusOK, code, path)
+	}
+	code, _ = get("/debug/flags/import")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+	code, _ = get("/other")
+	assert.Equal(t, http.StatusNotFound, code)
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"flag"
+	"fmt"
+	"path"
+
+	"fortio.org/dflag"
+)
+
+// checkPatterns returns an error for malformed patterns, path.Match only reports them when a name reaches them.
+func checkPatterns(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid flag name pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// WithSetAllowList restricts the flags SetFlag can change to the ones whose name matches one of the
+// patterns (path.Match syntax, e.g. `cache.*`), the other flags can still be changed through configmap
+// or the command line. Returns an error, and leaves the endpoint unchanged, if a pattern is invalid.
+func (e *FlagsEndpoint) WithSetAllowList(patterns ...string) (*FlagsEndpoint, error) {
+	if err := checkPatterns(patterns); err != nil {
+		return e, err
+	}
+	e.setAllow = append(e.setAllow, patterns...)
+	return e, nil
+}
+
+// WithSetDenyList prevents SetFlag from changing the flags whose name matches one of the patterns
+// (path.Match syntax), even if they are in the allow list. Returns an error, and leaves the endpoint
+// unchanged, if a pattern is invalid.
+func (e *FlagsEndpoint) WithSetDenyList(patterns ...string) (*FlagsEndpoint, error) {
+	if err := checkPatterns(patterns); err != nil {
+		return e, err
+	}
+	e.setDeny = append(e.setDeny, patterns...)
+	return e, nil
+}
+
+// WithSetPredicate adds a check on which flags SetFlag can change, in addition to the allow and deny lists.
+func (e *FlagsEndpoint) WithSetPredicate(predicate func(f *flag.Flag) bool) *FlagsEndpoint {
+	e.setPredicate = predicate
+	return e
+}
+
+// Mutable returns whether the flag can be changed through SetFlag: it must be dynamic and allowed
+// by the WithSetAllowList, WithSetDenyList and WithSetPredicate configuration.
+func (e *FlagsEndpoint) Mutable(f *flag.Flag) bool {
+	if !dflag.IsFlagDynamic(f) {
+		return false
+	}
+	if len(e.setAllow) > 0 && !matchAny(e.setAllow, f.Name) {
+		return false
+	}
+	if matchAny(e.setDeny, f.Name) {
+		return false
+	}
+	return e.setPredicate == nil || e.setPredicate(f)
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestMutable(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	cacheSize := dflag.DynInt64(set, "cache.size", 1, "dynamic int for testing")
+	cacheSecret := dflag.DynString(set, "cache.secret", "a", "dynamic string for testing")
+	dbPool := dflag.DynInt64(set, "db.pool", 1, "dynamic int for testing")
+	e := NewFlagsEndpoint(set, "/set")
+	_, err := e.WithSetAllowList("cache.[")
+	assert.Error(t, err, "malformed pattern")
+	_, err = e.WithSetDenyList("[")
+	assert.Error(t, err, "malformed pattern")
+	_, err = e.WithSetAllowList("cache.*")
+	assert.NoError(t, err)
+	_, err = e.WithSetDenyList("*.secret")
+	assert.NoError(t, err)
+	set.String("static", "x", "static string for testing")
+	assert.True(t, e.Mutable(set.Lookup("cache.size")))
+	assert.False(t, e.Mutable(set.Lookup("cache.secret")), "denied")
+	assert.False(t, e.Mutable(set.Lookup("db.pool")), "not allowed")
+	assert.False(t, e.Mutable(set.Lookup("static")), "static")
+	do := func(url string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		e.SetFlag(resp, httptest.NewRequest(http.MethodGet, url, nil))
+		return resp
+	}
+	assert.Equal(t, http.StatusOK, do("/set?name=cache.size&value=42").Code)
+	assert.Equal(t, int64(42), cacheSize.Get())
+	assert.Equal(t, http.StatusForbidden, do("/set?name=cache.secret&value=b").Code)
+	assert.Equal(t, http.StatusForbidden, do("/set?name=db.pool&value=3").Code)
+	assert.Equal(t, "a", cacheSecret.Get())
+	assert.Equal(t, int64(1), dbPool.Get())
+	// bulk set with one refused flag applies none.
+	req := httptest.NewRequest(http.MethodPost, "/set", strings.NewReader(`{"cache.size": 43, "db.pool": 3}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	e.SetFlag(resp, req)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	assert.Equal(t, int64(42), cacheSize.Get())
+	// predicate.
+	e.WithSetPredicate(func(f *flag.Flag) bool { return f.Name != "cache.size" })
+	assert.Equal(t, http.StatusForbidden, do("/set?name=cache.size&value=44").Code)
+	// listing only offers to change the mutable flags.
+	e.WithSetPredicate(nil)
+	resp = httptest.NewRecorder()
+	e.ListFlags(resp, httptest.NewRequest(http.MethodGet, "/debug/flags", nil))
+	list := &flagSetJSON{}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), list))
+	mutable := map[string]bool{}
+	for _, f := range list.Flags {
+		mutable[f.Name] = f.IsMutable
+	}
+	assert.Equal(t, map[string]bool{"cache.size": true, "cache.secret": false, "db.pool": false, "static": false}, mutable)
+}
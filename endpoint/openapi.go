@@ -0,0 +1,152 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"sort"
+	"strings"
+
+	"fortio.org/dflag"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification of the OpenAPISpec documents.
+const OpenAPIVersion = "3.0.3"
+
+// jsonObject is a JSON object of the OpenAPI document.
+type jsonObject = map[string]interface{}
+
+// OpenAPISpec returns the OpenAPI document of the handlers as registered by Mount under prefix, with the
+// current flag names enumerated in the `name` parameters (the mutable dynamic ones for SetFlag).
+func (e *FlagsEndpoint) OpenAPISpec(prefix string) map[string]interface{} {
+	prefix = strings.TrimSuffix(prefix, "/")
+	var names, mutable []string
+	e.flags().VisitAll(func(f *flag.Flag) {
+		names = append(names, f.Name)
+		if dflag.IsFlagDynamic(f) && e.Mutable(f) {
+			mutable = append(mutable, f.Name)
+		}
+	})
+	sort.Strings(names)
+	sort.Strings(mutable)
+	nameParam := func(in string, names []string) jsonObject {
+		return jsonObject{
+			"name": "name", "in": in, "required": true, "description": "Flag name.",
+			"schema": jsonObject{"type": "string", "enum": names},
+		}
+	}
+	json200 := func(description string) jsonObject {
+		return jsonObject{"200": jsonObject{
+			"description": description,
+			"content":     jsonObject{"application/json": jsonObject{"schema": jsonObject{"type": "object"}}},
+		}}
+	}
+	paths := jsonObject{
+		prefix: jsonObject{"get": jsonObject{
+			"operationId": "listFlags",
+			"summary":     "List the flags (HTML for browsers).",
+			"parameters": []jsonObject{
+				queryParam("format", "Output format.", "json", "txt", "csv"),
+				queryParam("filter", "Only the flags whose name or description contains this."),
+				queryParam("prefix", "Only the flags whose name starts with this."),
+				queryParam("changed", "Only the (un)changed flags.", "true", "false"),
+				queryParam("dynamic", "Only the dynamic (or static) flags.", "true", "false"),
+				queryParam("sort", "Order of the flags.", "name", "changed", "modified"),
+				intQueryParam("offset", "Index of the first flag returned."),
+				intQueryParam("limit", "Maximum number of flags returned."),
+			},
+			"responses": json200("The flags."),
+		}},
+		prefix + "/{name}": jsonObject{"get": jsonObject{
+			"operationId": "getFlag",
+			"summary":     "Current value of a flag.",
+			"parameters":  []jsonObject{nameParam("path", names)},
+			"responses": jsonObject{
+				"200": jsonObject{
+					"description": "The flag's value.",
+					"content":     jsonObject{"text/plain": jsonObject{"schema": jsonObject{"type": "string"}}},
+				},
+				"404": jsonObject{"description": "Unknown flag."},
+			},
+		}},
+		prefix + "/export": jsonObject{"get": jsonObject{
+			"operationId": "exportConfigMap",
+			"summary":     "The changed (or all) flags as a Kubernetes ConfigMap.",
+			"parameters": []jsonObject{
+				queryParam("name", "ConfigMap name."),
+				queryParam("namespace", "ConfigMap namespace."),
+				queryParam("all", "Export all the dynamic flags.", "true", "false"),
+				queryParam("format", "Instead of the ConfigMap, the effective configuration of all the flags.",
+					string(dflag.DumpJSON), string(dflag.DumpYAML), string(dflag.DumpEnv)),
+			},
+			"responses": jsonObject{
+				"200": jsonObject{
+					"description": "The ConfigMap, or the effective configuration in the requested format.",
+					"content": jsonObject{
+						"application/yaml": jsonObject{"schema": jsonObject{"type": "string"}},
+						"application/json": jsonObject{"schema": jsonObject{"type": "array"}},
+						"text/plain":       jsonObject{"schema": jsonObject{"type": "string"}},
+					},
+				},
+				"400": jsonObject{"description": "Unknown format."},
+			},
+		}},
+	}
+	if e.setURL != "" {
+		paths[e.setURL] = jsonObject{"post": jsonObject{
+			"operationId": "setFlag",
+			"summary":     "Set (or reset with action=reset) a dynamic flag.",
+			"parameters": []jsonObject{
+				nameParam("query", mutable),
+				queryParam("value", "New value."),
+				queryParam("dryrun", "Only validate the value.", "true", "false"),
+				queryParam("action", "Reset instead of set.", "reset"),
+			},
+			"responses": jsonObject{
+				"200": jsonObject{"description": "The flag was set."},
+				"429": jsonObject{"description": "Too many changes, see Retry-After."},
+				"default": jsonObject{
+					"description": "The flag wasn't set.",
+					"content":     jsonObject{ProblemContentType: jsonObject{"schema": jsonObject{"type": "object"}}},
+				},
+			},
+		}}
+	}
+	return jsonObject{
+		"openapi": OpenAPIVersion,
+		"info":    jsonObject{"title": "dflag endpoint", "version": "1"},
+		"paths":   paths,
+	}
+}
+
+func queryParam(name, description string, values ...string) jsonObject {
+	schema := jsonObject{"type": "string"}
+	if len(values) > 0 {
+		schema["enum"] = values
+	}
+	return jsonObject{"name": name, "in": "query", "description": description, "schema": schema}
+}
+
+func intQueryParam(name, description string) jsonObject {
+	return jsonObject{"name": name, "in": "query", "description": description, "schema": jsonObject{"type": "integer", "minimum": 0}}
+}
+
+// OpenAPI serves the OpenAPISpec document as JSON (e.g. registered as `/debug/flags/openapi.json`,
+// the prefix being the request path without its last element).
+func (e *FlagsEndpoint) OpenAPI(resp http.ResponseWriter, req *http.Request) {
+	e.logRequest(req, "OpenAPI")
+	if !e.authorized(resp, req, false) {
+		return
+	}
+	prefix := req.URL.Path[:strings.LastIndex(req.URL.Path, "/")+1]
+	out, err := json.MarshalIndent(e.OpenAPISpec(prefix), "", "  ")
+	if err != nil {
+		resp.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	_, _ = resp.Write(out)
+}
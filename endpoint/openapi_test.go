@@ -0,0 +1,49 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestOpenAPI(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	set.Int("some_int", 1, "static int for testing")
+	e := NewFlagsEndpoint(set, "/debug/flags/set")
+	h := e.Handler("/debug/flags")
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/debug/flags/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+			Parameters  []struct {
+				Name   string `json:"name"`
+				Schema struct {
+					Enum []string `json:"enum"`
+				} `json:"schema"`
+			} `json:"parameters"`
+		} `json:"paths"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &spec))
+	assert.Equal(t, OpenAPIVersion, spec.OpenAPI)
+	assert.Equal(t, "listFlags", spec.Paths["/debug/flags"]["get"].OperationID)
+	assert.Equal(t, "exportConfigMap", spec.Paths["/debug/flags/export"]["get"].OperationID)
+	get := spec.Paths["/debug/flags/{name}"]["get"]
+	assert.Equal(t, []string{"some_dynint", "some_int"}, get.Parameters[0].Schema.Enum)
+	setOp := spec.Paths["/debug/flags/set"]["post"]
+	assert.Equal(t, "setFlag", setOp.OperationID)
+	assert.Equal(t, []string{"some_dynint"}, setOp.Parameters[0].Schema.Enum, "only the mutable flags")
+	_, hasSet := NewFlagsEndpoint(set, "").OpenAPISpec("/debug/flags")["paths"].(map[string]interface{})["/debug/flags/set"]
+	assert.False(t, hasSet, "no set without setURL")
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// lastModifiedFormat is the fixed width (so they sort as strings) format of the flags' LastModified.
+const lastModifiedFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+// listPage is the order and page of flags listed, from the `sort`, `offset` and `limit` query parameters.
+type listPage struct {
+	sortBy string // name (default, grouped by section), changed or modified.
+	offset int
+	limit  int // 0 for all.
+}
+
+func newListPage(q url.Values) (*listPage, error) {
+	p := &listPage{sortBy: q.Get("sort")}
+	switch p.sortBy {
+	case "", "name", "changed", "modified":
+	default:
+		return nil, fmt.Errorf("invalid sort %q, expecting name, changed or modified", p.sortBy)
+	}
+	for _, param := range []struct {
+		name string
+		v    *int
+	}{{"offset", &p.offset}, {"limit", &p.limit}} {
+		s := q.Get(param.name)
+		if s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid %s %q", param.name, s)
+		}
+		*param.v = n
+	}
+	return p, nil
+}
+
+// apply sorts the flags and returns the requested page of them.
+func (p *listPage) apply(flags []*flagJSON) []*flagJSON {
+	switch p.sortBy {
+	case "", "name": // already in name order, grouped by section (see WithFlagSet).
+		sort.SliceStable(flags, func(i, j int) bool { return flags[i].Section < flags[j].Section })
+	case "changed":
+		sort.SliceStable(flags, func(i, j int) bool { return flags[i].IsChanged && !flags[j].IsChanged })
+	case "modified": // most recent first, see lastModifiedFormat.
+		sort.SliceStable(flags, func(i, j int) bool { return flags[i].LastModified > flags[j].LastModified })
+	}
+	if p.offset >= len(flags) {
+		return []*flagJSON{}
+	}
+	flags = flags[p.offset:]
+	if p.limit > 0 && p.limit < len(flags) {
+		flags = flags[:p.limit]
+	}
+	return flags
+}
+
+// pageURL returns the URL of the page starting at offset, "" if out of range.
+func (p *listPage) pageURL(u *url.URL, offset, total int) string {
+	if p.limit == 0 || offset < 0 || offset >= total || offset == p.offset {
+		return ""
+	}
+	q := u.Query()
+	q.Set("offset", strconv.Itoa(offset))
+	return "?" + q.Encode()
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+
+	"fortio.org/dflag"
+)
+
+// ProblemContentType is the RFC 7807 media type of the SetFlag error replies.
+const ProblemContentType = "application/problem+json"
+
+// Problem is the RFC 7807 problem details of a failed SetFlag, with the flag specific members tooling
+// can use to render actionable errors.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Flag and Value are the flag and the value attempted.
+	Flag  string `json:"flag,omitempty"`
+	Value string `json:"value,omitempty"`
+	// Constraint describes the acceptable values (e.g. "between 1 and 10") and AllowedValues lists
+	// them when they are enumerated, see dflag.DynamicFlagHints.
+	Constraint    string   `json:"constraint,omitempty"`
+	AllowedValues []string `json:"allowed_values,omitempty"`
+}
+
+// kindConstraints describes the values accepted for each kind of flag (see valueKind).
+var kindConstraints = map[string]string{
+	"bool":  "true or false",
+	"int":   "an integer",
+	"float": "a number",
+	"json":  "a valid JSON value",
+}
+
+// newProblem returns the Problem for setting f (nil if not found) named name to value.
+func newProblem(status int, title string, f *flag.Flag, name, value string, err error) *Problem {
+	p := &Problem{Type: "about:blank", Title: title, Status: status, Flag: name, Value: dflag.Redact(f, value)}
+	if err != nil {
+		p.Detail = err.Error()
+	}
+	if f == nil {
+		return p
+	}
+	if h, ok := f.Value.(dflag.DynamicFlagHints); ok {
+		p.AllowedValues = h.Choices()
+		if from, to, ok := h.Range(); ok {
+			p.Constraint = fmt.Sprintf("between %s and %s", from, to)
+		} else if p.AllowedValues != nil {
+			p.Constraint = "one of the allowed values"
+		}
+	}
+	if p.Constraint == "" {
+		p.Constraint = kindConstraints[valueKind(f.Value, flagToJSON(f).IsJSON)]
+	}
+	return p
+}
+
+// writeProblem replies with the problem as application/problem+json.
+func (e *FlagsEndpoint) writeProblem(resp http.ResponseWriter, p *Problem) {
+	e.logger().Error(p.Title, "flag", p.Flag, "value", p.Value, "detail", p.Detail)
+	out, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		resp.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	resp.Header().Set("Content-Type", ProblemContentType)
+	resp.WriteHeader(p.Status)
+	_, _ = resp.Write(out)
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestSetFlagProblem(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing").WithRange(1, 10)
+	dflag.DynString(set, "some_dynstr", "a", "dynamic string for testing").WithOneOf("a", "b")
+	dflag.DynFloat64(set, "some_dynfloat", 1.5, "dynamic float for testing")
+	set.Int("some_int", 1, "static int for testing")
+	e := NewFlagsEndpoint(set, "/set")
+	setFlag := func(query string) *Problem {
+		resp := httptest.NewRecorder()
+		e.SetFlag(resp, httptest.NewRequest(http.MethodGet, "/set?"+query, nil))
+		assert.Equal(t, ProblemContentType, resp.Header().Get("Content-Type"))
+		p := &Problem{}
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), p))
+		assert.Equal(t, resp.Code, p.Status)
+		return p
+	}
+	p := setFlag("name=some_dynint&value=11")
+	assert.Equal(t, http.StatusNotAcceptable, p.Status)
+	assert.Equal(t, "some_dynint", p.Flag)
+	assert.Equal(t, "11", p.Value)
+	assert.Equal(t, "between 1 and 10", p.Constraint)
+	assert.Contains(t, p.Detail, "not in [1, 10] range")
+	p = setFlag("name=some_dynstr&value=c")
+	assert.Equal(t, "one of the allowed values", p.Constraint)
+	assert.Equal(t, []string{"a", "b"}, p.AllowedValues)
+	p = setFlag("name=some_dynfloat&value=x")
+	assert.Equal(t, "a number", p.Constraint)
+	assert.Contains(t, p.Detail, "invalid syntax")
+	p = setFlag("name=some_int&value=2")
+	assert.Equal(t, http.StatusBadRequest, p.Status)
+	assert.Equal(t, "Flag is not dynamic", p.Title)
+	p = setFlag("name=nope&value=2")
+	assert.Equal(t, http.StatusForbidden, p.Status)
+	assert.Equal(t, "about:blank", p.Type)
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxRateLimitedAddrs bounds the per remote address buckets kept, the full ones are dropped beyond it.
+const maxRateLimitedAddrs = 10000
+
+// WithSetRateLimit limits the mutating requests (SetFlag, including dry runs and resets, and ImportFlags)
+// to perAddrPerSecond per remote address and globalPerSecond overall, allowing bursts of burst requests;
+// 0 disables the corresponding limit. Requests over the limits get a 429 with a Retry-After header.
+// Independently of the limits, mutating requests are processed one at a time so validators and notifiers
+// never run concurrently for them.
+func (e *FlagsEndpoint) WithSetRateLimit(perAddrPerSecond, globalPerSecond float64, burst int) *FlagsEndpoint {
+	if burst < 1 {
+		burst = 1
+	}
+	e.setLimiter = &setLimiter{
+		addrRate:   perAddrPerSecond,
+		globalRate: globalPerSecond,
+		burst:      float64(burst),
+		global:     bucket{tokens: float64(burst)},
+		addrs:      make(map[string]*bucket),
+	}
+	return e
+}
+
+// bucket is a token bucket, refilled at the limiter's rate up to its burst.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (b *bucket) refill(now time.Time, rate, burst float64) {
+	if !b.last.IsZero() {
+		b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	}
+	b.last = now
+}
+
+// wait is how long until the bucket has a token, 0 if it has one.
+func (b *bucket) wait(rate float64) time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / rate * float64(time.Second))
+}
+
+type setLimiter struct {
+	mu         sync.Mutex
+	addrRate   float64
+	globalRate float64
+	burst      float64
+	global     bucket
+	addrs      map[string]*bucket
+}
+
+// allow takes a token from the global and addr buckets, or returns how long to wait if either is empty.
+func (l *setLimiter) allow(addr string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var retry time.Duration
+	if l.globalRate > 0 {
+		l.global.refill(now, l.globalRate, l.burst)
+		retry = l.global.wait(l.globalRate)
+	}
+	var b *bucket
+	if l.addrRate > 0 {
+		b = l.addrs[addr]
+		if b == nil {
+			if len(l.addrs) >= maxRateLimitedAddrs {
+				l.prune(now)
+			}
+			b = &bucket{tokens: l.burst}
+			l.addrs[addr] = b
+		}
+		b.refill(now, l.addrRate, l.burst)
+		if w := b.wait(l.addrRate); w > retry {
+			retry = w
+		}
+	}
+	if retry > 0 {
+		return retry, false
+	}
+	if l.globalRate > 0 {
+		l.global.tokens--
+	}
+	if b != nil {
+		b.tokens--
+	}
+	return 0, true
+}
+
+// prune drops the buckets that are full again, they'd be recreated the same.
+func (l *setLimiter) prune(now time.Time) {
+	for addr, b := range l.addrs {
+		if b.tokens+now.Sub(b.last).Seconds()*l.addrRate >= l.burst {
+			delete(l.addrs, addr)
+		}
+	}
+}
+
+// setGuard checks the rate limits of WithSetRateLimit, replying 429 when exceeded, and otherwise
+// waits for the other mutating requests to be done: the returned release must then be called.
+func (e *FlagsEndpoint) setGuard(resp http.ResponseWriter, req *http.Request) (release func(), ok bool) {
+	if e.setLimiter != nil {
+		addr, _, err := net.SplitHostPort(req.RemoteAddr)
+		if err != nil {
+			addr = req.RemoteAddr
+		}
+		if retry, ok := e.setLimiter.allow(addr, time.Now()); !ok {
+			resp.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+			e.httpErrf(resp, http.StatusTooManyRequests, "Too many flag changes, retry in %v", retry.Round(time.Millisecond))
+			return nil, false
+		}
+	}
+	e.setMu.Lock()
+	return e.setMu.Unlock, true
+}
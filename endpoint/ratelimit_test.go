@@ -0,0 +1,53 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestSetLimiter(t *testing.T) {
+	l := (&FlagsEndpoint{}).WithSetRateLimit(1, 0, 2).setLimiter
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		_, ok := l.allow("a", now)
+		assert.True(t, ok, "within the burst")
+	}
+	retry, ok := l.allow("a", now)
+	assert.False(t, ok, "burst exhausted")
+	assert.Equal(t, time.Second, retry)
+	_, ok = l.allow("b", now)
+	assert.True(t, ok, "other address has its own bucket")
+	_, ok = l.allow("a", now.Add(time.Second))
+	assert.True(t, ok, "refilled")
+	g := (&FlagsEndpoint{}).WithSetRateLimit(0, 1, 1).setLimiter
+	_, ok = g.allow("a", now)
+	assert.True(t, ok)
+	retry, ok = g.allow("b", now.Add(500*time.Millisecond))
+	assert.False(t, ok, "global limit applies across addresses")
+	assert.Equal(t, 500*time.Millisecond, retry)
+}
+
+func TestSetRateLimit(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	e := NewFlagsEndpoint(set, "/debug/flags/set").WithSetRateLimit(0.001, 0, 1)
+	setFlag := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/debug/flags/set?name=some_dynint&value=2", nil)
+		resp := httptest.NewRecorder()
+		e.SetFlag(resp, req)
+		return resp
+	}
+	assert.Equal(t, http.StatusOK, setFlag().Code)
+	resp := setFlag()
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.NotEqual(t, "", resp.Header().Get("Retry-After"))
+}
@@ -0,0 +1,25 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"net/http"
+	"strings"
+
+	"fortio.org/dflag"
+)
+
+// ReadyHandler returns an http.Handler suitable for Kubernetes readiness probes gated on required flags
+// (see dflag.Require): it replies 200 once they're all set and 503 listing the missing ones until then.
+func ReadyHandler(r *dflag.Requirements) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, _ *http.Request) {
+		resp.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+		if missing := r.Missing(); len(missing) > 0 {
+			resp.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = resp.Write([]byte("required flags not set yet: " + strings.Join(missing, ", ") + "\n"))
+			return
+		}
+		_, _ = resp.Write([]byte("ok\n"))
+	})
+}
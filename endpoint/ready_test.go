@@ -0,0 +1,33 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestReadyHandler(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynString(set, "db_url", "", "")
+	dflag.DynInt64(set, "pool", 1, "")
+	r, err := dflag.Require(set, "db_url", "pool")
+	assert.NoError(t, err)
+	h := ReadyHandler(r)
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	assert.Equal(t, "required flags not set yet: db_url, pool\n", resp.Body.String())
+	assert.NoError(t, set.Set("db_url", "postgres://db"))
+	assert.NoError(t, set.Set("pool", "4"))
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "ok\n", resp.Body.String())
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+
+	"fortio.org/dflag"
+)
+
+// ResetResponse is the JSON reply of a reset: the flags reset (or that would be, when not Confirmed)
+// with their default value.
+type ResetResponse struct {
+	Confirmed bool        `json:"confirmed"`
+	Results   []SetResult `json:"results"`
+}
+
+// resetFlags handles the SetFlag requests with `action=reset`: the flag named by `name`, or with `all=true`
+// all the changed dynamic flags that can be set through this endpoint, are reset to their default value.
+// Unless `confirm=true` is also passed nothing is changed and the flags that would be reset are returned,
+// for the operator to confirm.
+func (e *FlagsEndpoint) resetFlags(resp http.ResponseWriter, req *http.Request) {
+	var flags []*flag.Flag
+	if req.FormValue("all") == "true" {
+		e.flags().VisitAll(func(f *flag.Flag) {
+			if e.Mutable(f) && dflag.IsChanged(f) {
+				flags = append(flags, f)
+			}
+		})
+	} else {
+		name := req.FormValue("name")
+		f := e.flags().Lookup(name)
+		switch {
+		case f == nil:
+			e.httpErrf(resp, http.StatusForbidden, "Flag %q not found", name)
+			return
+		case !dflag.IsFlagDynamic(f):
+			e.httpErrf(resp, http.StatusBadRequest, "Trying to reset non dynamic flag %q", name)
+			return
+		case !e.Mutable(f):
+			e.httpErrf(resp, http.StatusForbidden, "Flag %q can't be changed through this endpoint", name)
+			return
+		}
+		flags = append(flags, f)
+	}
+	res := ResetResponse{Confirmed: req.FormValue("confirm") == "true", Results: make([]SetResult, len(flags))}
+	status := http.StatusOK
+	for i, f := range flags {
+		r := &res.Results[i]
+		*r = SetResult{Name: f.Name, Value: f.DefValue}
+		if !res.Confirmed {
+			continue
+		}
+		oldValue := f.Value.String()
+		err := dflag.ResetFlag(e.flags(), f.Name)
+		e.audit(req, f.Name, oldValue, f.DefValue, err)
+		if err != nil {
+			r.Error = err.Error()
+			status = http.StatusMultiStatus
+			continue
+		}
+		r.Applied = true
+		for _, hook := range e.setHooks {
+			hook(f.Name)
+		}
+	}
+	e.logger().Info("dflag: reset", "flags", len(flags), "confirmed", res.Confirmed)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(status)
+	_ = json.NewEncoder(resp).Encode(res)
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestReset(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	dynStr := dflag.DynString(set, "some_dynstr", "a", "dynamic string for testing")
+	secret := dflag.DynString(set, "some_secret", "s", "dynamic string for testing")
+	staticInt := set.Int("some_int", 1, "static int for testing")
+	assert.NoError(t, set.Set("some_dynint", "2"))
+	assert.NoError(t, set.Set("some_dynstr", "b"))
+	assert.NoError(t, set.Set("some_secret", "t"))
+	assert.NoError(t, set.Set("some_int", "2"))
+	e, err := NewFlagsEndpoint(set, "/set").WithSetDenyList("*secret")
+	assert.NoError(t, err)
+	var hooked []string
+	e.WithSetHook(func(name string) { hooked = append(hooked, name) })
+	reset := func(query string) (int, *ResetResponse) {
+		resp := httptest.NewRecorder()
+		e.SetFlag(resp, httptest.NewRequest(http.MethodGet, "/set?action=reset&"+query, nil))
+		res := &ResetResponse{}
+		if resp.Code == http.StatusOK {
+			assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), res))
+		}
+		return resp.Code, res
+	}
+	req := httptest.NewRequest(http.MethodGet, "/debug/flags", nil)
+	req.Header.Set("Accept", "text/html")
+	resp := httptest.NewRecorder()
+	e.ListFlags(resp, req)
+	assert.Contains(t, resp.Body.String(), `value="Reset to default"`)
+	assert.Contains(t, resp.Body.String(), `value="Reset all changed dynamic flags"`)
+	code, res := reset("all=true")
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, res.Confirmed, "preview only")
+	assert.Equal(t, []SetResult{{Name: "some_dynint", Value: "1"}, {Name: "some_dynstr", Value: "a"}}, res.Results)
+	assert.Equal(t, int64(2), dynInt.Get(), "not reset without confirmation")
+	code, res = reset("name=some_dynint&confirm=true")
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, res.Results[0].Applied, "reset")
+	assert.Equal(t, int64(1), dynInt.Get())
+	assert.Equal(t, []string{"some_dynint"}, hooked)
+	code, _ = reset("name=some_secret&confirm=true")
+	assert.Equal(t, http.StatusForbidden, code, "not mutable")
+	code, _ = reset("name=some_int&confirm=true")
+	assert.Equal(t, http.StatusBadRequest, code, "static")
+	code, _ = reset("name=nope&confirm=true")
+	assert.Equal(t, http.StatusForbidden, code, "not found")
+	code, res = reset("all=true&confirm=true")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, len(res.Results), "only the remaining changed mutable flag")
+	assert.Equal(t, "a", dynStr.Get())
+	assert.Equal(t, "t", secret.Get())
+	assert.Equal(t, 2, *staticInt)
+}
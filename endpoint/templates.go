@@ -0,0 +1,263 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+)
+
+// DefaultStyleSheet is the CSS linked by the default templates, see WithStyleSheet.
+const DefaultStyleSheet = "https://maxcdn.bootstrapcdn.com/bootstrap/3.3.4/css/bootstrap.css"
+
+// WithStyleSheet replaces the DefaultStyleSheet linked by the HTML pages (e.g. with the admin console's CSS).
+func (e *FlagsEndpoint) WithStyleSheet(href string) *FlagsEndpoint {
+	e.styleSheet = href
+	return e
+}
+
+// WithListTemplate replaces the HTML template of the ListFlags page. It is executed with the same data as the
+// JSON output (Flags, ChecksumDynamic, ChecksumStatic, FlagSetURL, CSRFToken) and the StyleSheet. To only
+// change parts of the page, override the "title", "style" or "header" blocks of a DefaultListTemplate(), e.g.
+//
+//	t := template.Must(endpoint.DefaultListTemplate().Parse(`{{define "header"}}<h1>My Service</h1>{{end}}`))
+func (e *FlagsEndpoint) WithListTemplate(t *template.Template) *FlagsEndpoint {
+	e.listTemplate = t
+	return e
+}
+
+// WithDiffTemplate replaces the HTML template of the DiffFlags page, executed with the changed Flags
+// and the StyleSheet. See WithListTemplate for the blocks of DefaultDiffTemplate() that can be overridden.
+func (e *FlagsEndpoint) WithDiffTemplate(t *template.Template) *FlagsEndpoint {
+	e.diffTemplate = t
+	return e
+}
+
+// DefaultListTemplate returns a new copy of the default ListFlags template, to override some of its blocks.
+func DefaultListTemplate() *template.Template {
+	return template.Must(template.New("dflag_list").Parse(dflagListHTML))
+}
+
+// DefaultDiffTemplate returns a new copy of the default DiffFlags template, to override some of its blocks.
+func DefaultDiffTemplate() *template.Template {
+	return template.Must(template.New("dflag_diff").Parse(dflagDiffHTML))
+}
+
+func (e *FlagsEndpoint) styleSheetOrDefault() string {
+	if e.styleSheet == "" {
+		return DefaultStyleSheet
+	}
+	return e.styleSheet
+}
+
+// writeHTML executes the template (or the default one when nil), replying with a 500 error if it fails.
+func (e *FlagsEndpoint) writeHTML(resp http.ResponseWriter, t, def *template.Template, data interface{}) {
+	if t == nil {
+		t = def
+	}
+	buf := &bytes.Buffer{}
+	if err := t.Execute(buf, data); err != nil {
+		e.httpErrf(resp, http.StatusInternalServerError, "Bad template evaluation: %v", err)
+		return
+	}
+	resp.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	_, _ = resp.Write(buf.Bytes())
+}
+
+var (
+	dflagListTemplate    = template.Must(template.New("dflag_list").Parse(dflagListHTML))
+	dflagDiffTemplate    = template.Must(template.New("dflag_diff").Parse(dflagDiffHTML))
+	dflagHistoryTemplate = template.Must(template.New("dflag_history").Parse(dflagHistoryHTML))
+)
+
+//nolint:lll
+const dflagListHTML = `
+<html><head>
+<title>{{ block "title" . }}Flags List{{ end }}</title>
+{{ block "style" . }}<link href="{{ .StyleSheet }}" rel="stylesheet">{{ end }}
+<script>
+// dflagSubmit sends the form in the background and shows the outcome next to its submit button.
+function dflagSubmit(form) {
+  var data = new URLSearchParams(new FormData(form));
+  var method = (form.getAttribute("method") || "get").toUpperCase();
+  var url = form.action, opts = {method: method, credentials: "same-origin"};
+  if (method === "POST") {
+    opts.body = data;
+  } else {
+    url += (url.indexOf("?") < 0 ? "?" : "&") + data;
+  }
+  var status = form.querySelector(".dflag-status");
+  fetch(url, opts).then(function(r) {
+    return r.text().then(function(t) {
+      if ((r.headers.get("Content-Type") || "").indexOf("problem+json") >= 0) {
+        var p = JSON.parse(t);
+        t = p.title + ": " + (p.detail || p.flag) + (p.constraint ? " (expecting " + p.constraint + ")" : "");
+      }
+      status.textContent = t;
+      status.className = "dflag-status label label-" + (r.ok ? "success" : "danger");
+    });
+  }).catch(function(e) {
+    status.textContent = e;
+    status.className = "dflag-status label label-danger";
+  });
+  return false;
+}
+// dflagPretty re-indents the JSON value of the form, or shows the parsing error.
+function dflagPretty(form) {
+  var status = form.querySelector(".dflag-status");
+  try {
+    form.value.value = JSON.stringify(JSON.parse(form.value.value), null, 2);
+    status.textContent = "";
+  } catch (e) {
+    status.textContent = e;
+    status.className = "dflag-status label label-danger";
+  }
+}
+</script>
+</head>
+<body>
+<div class="container-fluid">
+<div class="col-md-10 col-md-offset-1">
+	{{ block "header" . }}<h1>Flags Debug View</h1>{{ end }}
+	<p>
+	This page presents the configuration flags of this server (<a href="?format=json">JSON</a>, <a href="?format=txt">text</a>, <a href="?format=csv">CSV</a>).
+	</p>
+	<p>
+	You can easily filter only <a href="?changed=true"><span class="label label-primary">changed</span> flag</a> or filter flags by type:
+	</p>
+	<ul>
+	  <li><a href="?dynamic=true"><span class="label label-success">dynamic</span></a> - flags tweakable dynamically - checksum <code>{{ .ChecksumDynamic }}</code></li>
+	  <li><a href="?dynamic=false"><span class="label label-default">static</span></a> - initialization-time only flags - checksum <code>{{ .ChecksumStatic }}</code></li>
+	</ul>
+	<form class="form-inline"><input type="text" class="form-control" name="filter" placeholder="search name or description" />
+	<input type="text" class="form-control" name="prefix" placeholder="name prefix" /> <input type="submit" class="btn btn-default" value="Filter"/></form>
+	{{ if .FlagSetURL }}
+	<form action="{{ .FlagSetURL }}"{{ if .CSRFToken }} method="post"{{ end }} onsubmit="return confirm('Reset all the changed dynamic flags to their defaults?')">
+	<input type="hidden" name="action" value="reset" /><input type="hidden" name="all" value="true" /><input type="hidden" name="confirm" value="true" />
+	{{ if .CSRFToken }}<input type="hidden" name="csrf_token" value="{{ .CSRFToken }}" />{{ end }}
+	<input type="submit" class="btn btn-warning" value="Reset all changed dynamic flags"/></form>
+	{{ end }}
+
+	<p>Sort by <a href="?sort=name">name</a>, <a href="?sort=changed">changed first</a> or
+	<a href="?sort=modified">most recently set</a>{{ if or .PrevURL .NextURL }} -
+	{{ if .PrevURL }}<a href="{{ .PrevURL }}">&laquo; previous</a>{{ end }}
+	{{ len .Flags }} of {{ .Total }} flags
+	{{ if .NextURL }}<a href="{{ .NextURL }}">next &raquo;</a>{{ end }}{{ end }}</p>
+
+	{{ $section := "" }}
+	{{range $flag := .Flags }}
+		{{ if ne $flag.Section $section }}{{ $section = $flag.Section }}<h2><code>{{ $section }}</code> flags</h2>{{ end }}
+		<div class="panel panel-default">
+          <div class="panel-heading">
+            <code>{{ $flag.Name }}</code>
+            {{ if $flag.IsChanged }}<span class="label label-primary">changed</span>{{ end }}
+            {{ if $flag.IsDynamic }}
+                <span class="label label-success">dynamic</span>
+            {{ else }}
+                <span class="label label-default">static</span>
+            {{ end }}
+
+          </div>
+		  <div class="panel-body">
+		    <dl class="dl-horizontal" style="margin-bottom: 0px">
+			  <dt>Description</dt>
+			  <dd><small>{{ $flag.Description }}</small></dd>
+			  <dt>Default</dt>
+			  <dd><pre style="font-size: 8pt">{{ $flag.DefaultValue }}</pre></dd>
+			  <dt>Current</dt>
+			  {{ if $flag.IsMutable }}
+			  <form action="{{ $.FlagSetURL }}"{{ if $.CSRFToken }} method="post"{{ end }} onsubmit="return dflagSubmit(this)">
+			  <input type="hidden" name="name" value="{{ $flag.Name }}" />
+			  {{ if $.CSRFToken }}<input type="hidden" name="csrf_token" value="{{ $.CSRFToken }}" />{{ end }}
+			  <dd><pre class="success" style="font-size: 8pt">
+				  {{- if $flag.IsJSON }}<textarea name="value" rows="8" cols="80">{{ $flag.CurrentValue }}</textarea>
+					  <button type="button" onclick="dflagPretty(this.form)">Pretty print</button>
+				  {{- else if $flag.Choices }}<select name="value">
+					  {{- range $c := $flag.Choices }}<option value="{{ $c }}"{{ if eq $c $flag.CurrentValue }} selected{{ end }}>{{ $c }}</option>{{ end -}}
+					  </select>
+				  {{- else if eq $flag.Kind "bool" }}<input type="checkbox" name="value" value="true"{{ if eq $flag.CurrentValue "true" }} checked{{ end }} /><input type="hidden" name="value" value="false" />
+				  {{- else if or (eq $flag.Kind "int") (eq $flag.Kind "float") }}<input type="number" name="value" value="{{ $flag.CurrentValue }}" step="{{ if eq $flag.Kind "int" }}1{{ else }}any{{ end }}"{{ if $flag.Range }} min="{{ index $flag.Range 0 }}" max="{{ index $flag.Range 1 }}"{{ end }} />
+				  {{- else }}<input type="text" name="value" value="{{ $flag.CurrentValue }}" />
+				  {{- end }}</pre>
+			  <input type="submit" value="Update"/> <span class="dflag-status"></span></dd>
+			  </form>
+			  {{ if $flag.IsChanged }}
+			  <form action="{{ $.FlagSetURL }}"{{ if $.CSRFToken }} method="post"{{ end }} onsubmit="return confirm('Reset {{ $flag.Name }} to its default?') && dflagSubmit(this)">
+			  <input type="hidden" name="action" value="reset" /><input type="hidden" name="confirm" value="true" />
+			  <input type="hidden" name="name" value="{{ $flag.Name }}" />
+			  {{ if $.CSRFToken }}<input type="hidden" name="csrf_token" value="{{ $.CSRFToken }}" />{{ end }}
+			  <dd><input type="submit" class="btn btn-default btn-xs" value="Reset to default"/> <span class="dflag-status"></span></dd>
+			  </form>
+			  {{ end }}
+			  {{ else }}
+			  <dd><pre class="success" style="font-size: 8pt">{{ $flag.CurrentValue }}</pre></dd>
+			  {{ end }}
+		    </dl>
+		  </div>
+		</div>
+	{{end}}
+</div></div>
+</body>
+</html>
+`
+
+const dflagDiffHTML = `
+<html><head>
+<title>{{ block "title" . }}Changed Flags{{ end }}</title>
+{{ block "style" . }}<link href="{{ .StyleSheet }}" rel="stylesheet">{{ end }}
+</head>
+<body>
+<div class="container-fluid">
+<div class="col-md-10 col-md-offset-1">
+	{{ block "header" . }}<h1>Changed Flags</h1>{{ end }}
+	<p>Flags whose current value differs from the default (<a href="?format=json">JSON</a>).</p>
+	<table class="table table-striped">
+	<tr><th>Name</th><th>Default</th><th>Current</th></tr>
+	{{range $flag := .Flags }}
+	<tr>
+	  <td><code>{{ $flag.Name }}</code>
+	    {{ if $flag.IsDynamic }}<span class="label label-success">dynamic</span>{{ else }}<span class="label label-default">static</span>{{ end }}</td>
+	  <td><pre style="font-size: 8pt">{{ $flag.DefaultValue }}</pre></td>
+	  <td><pre class="success" style="font-size: 8pt">{{ $flag.CurrentValue }}</pre></td>
+	</tr>
+	{{else}}
+	<tr><td colspan="3">All flags have their default value.</td></tr>
+	{{end}}
+	</table>
+</div>
+</div>
+</body>
+</html>
+`
+
+const dflagHistoryHTML = `
+<html><head>
+<title>{{ block "title" . }}Flag {{ .Name }} History{{ end }}</title>
+{{ block "style" . }}<link href="{{ .StyleSheet }}" rel="stylesheet">{{ end }}
+</head>
+<body>
+<div class="container-fluid">
+<div class="col-md-10 col-md-offset-1">
+	{{ block "header" . }}<h1>Flag <code>{{ .Name }}</code> History</h1>{{ end }}
+	<p>Default <code>{{ .DefaultValue }}</code>, current <code>{{ .CurrentValue }}</code>,
+	most recent last (<a href="?name={{ .Name }}&format=json">JSON</a>).</p>
+	<table class="table table-striped">
+	<tr><th>Time</th><th>Generation</th><th>Value</th><th>Source</th></tr>
+	{{range $entry := .History }}
+	<tr>
+	  <td>{{ $entry.Time.UTC.Format "2006-01-02 15:04:05.000 MST" }}</td>
+	  <td>{{ $entry.Generation }}</td>
+	  <td><pre style="font-size: 8pt">{{ $entry.Value }}</pre></td>
+	  <td>{{ $entry.Source }}</td>
+	</tr>
+	{{else}}
+	<tr><td colspan="4">The flag wasn't changed yet.</td></tr>
+	{{end}}
+	</table>
+</div>
+</div>
+</body>
+</html>
+`
@@ -0,0 +1,48 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package endpoint
+
+import (
+	"flag"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestTemplates(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	assert.NoError(t, set.Set("some_dynint", "2"))
+	e := NewFlagsEndpoint(set, "")
+	html := func(handler http.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/debug/flags", nil)
+		req.Header.Set("Accept", "text/html")
+		resp := httptest.NewRecorder()
+		handler(resp, req)
+		return resp
+	}
+	resp := html(e.ListFlags)
+	assert.Equal(t, "text/html; charset=UTF-8", resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Body.String(), DefaultStyleSheet)
+	assert.Contains(t, html(e.DiffFlags).Body.String(), DefaultStyleSheet)
+	e.WithStyleSheet("/static/admin.css")
+	assert.Contains(t, html(e.ListFlags).Body.String(), `<link href="/static/admin.css" rel="stylesheet">`)
+	assert.Contains(t, html(e.DiffFlags).Body.String(), `<link href="/static/admin.css" rel="stylesheet">`)
+	// override of a block of the default page.
+	e.WithListTemplate(template.Must(DefaultListTemplate().Parse(`{{define "header"}}<h1>My Service</h1>{{end}}`)))
+	body := html(e.ListFlags).Body.String()
+	assert.Contains(t, body, "<h1>My Service</h1>")
+	assert.Contains(t, body, "<code>some_dynint</code>", "rest of the default page")
+	assert.Contains(t, html(NewFlagsEndpoint(set, "").ListFlags).Body.String(), "Flags Debug View",
+		"the default template isn't changed")
+	// whole page.
+	e.WithDiffTemplate(template.Must(template.New("diff").Parse(`{{range .Flags}}{{.Name}}={{.CurrentValue}};{{end}}`)))
+	assert.Equal(t, "some_dynint=2;", html(e.DiffFlags).Body.String())
+	e.WithDiffTemplate(template.Must(template.New("diff").Parse(`{{.Nope}}`)))
+	assert.Equal(t, http.StatusInternalServerError, html(e.DiffFlags).Code)
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package env
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"fortio.org/struct2env"
+)
+
+// DiffKind is how an environment variable differs from the expected one, see Diff.
+type DiffKind string
+
+const (
+	// Missing is for an expected variable that isn't set.
+	Missing DiffKind = "missing"
+	// Extra is for a variable with the prefix that doesn't correspond to any field.
+	Extra DiffKind = "extra"
+	// Different is for a variable set to another value than the field's.
+	Different DiffKind = "different"
+)
+
+// Difference is one environment variable not matching the struct, see Diff.
+type Difference struct {
+	Name     string
+	Kind     DiffKind
+	Expected string // Empty for Extra.
+	Actual   string // Empty for Missing.
+}
+
+func (d Difference) String() string {
+	switch d.Kind {
+	case Missing:
+		return fmt.Sprintf("%s is missing, expected %q", d.Name, d.Expected)
+	case Extra:
+		return fmt.Sprintf("%s=%q is extra", d.Name, d.Actual)
+	default:
+		return fmt.Sprintf("%s=%q, expected %q", d.Name, d.Actual, d.Expected)
+	}
+}
+
+// Diff compares the environment with the variables s serializes to (struct2env.StructToEnvVars with
+// prefix prepended), sorted by name, e.g. to debug a container environment not matching what the code
+// expects. The values are compared as strings, so e.g. durations must be in seconds like struct2env
+// writes them. Extra variables are only reported with a non-empty prefix. The fields that can't be
+// serialized are reported in the returned error.
+func Diff(s interface{}, prefix string) ([]Difference, error) {
+	kvl, errs := struct2env.StructToEnvVars(s)
+	var res []Difference
+	expected := make(map[string]bool, len(kvl))
+	for _, kv := range kvl {
+		name := prefix + kv.Key
+		expected[name] = true
+		want := Value(kv)
+		actual, found := os.LookupEnv(name)
+		switch {
+		case !found:
+			res = append(res, Difference{Name: name, Kind: Missing, Expected: want})
+		case actual != want:
+			res = append(res, Difference{Name: name, Kind: Different, Expected: want, Actual: actual})
+		}
+	}
+	if prefix != "" {
+		for _, kv := range os.Environ() {
+			name, value, _ := strings.Cut(kv, "=")
+			if strings.HasPrefix(name, prefix) && !expected[name] {
+				res = append(res, Difference{Name: name, Kind: Extra, Actual: value})
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res, serializationError(errs)
+}
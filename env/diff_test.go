@@ -0,0 +1,41 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package env
+
+import (
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+)
+
+type diffConfig struct {
+	Name    string
+	Port    int
+	Timeout time.Duration
+	Debug   bool
+}
+
+func TestDiff(t *testing.T) {
+	cfg := diffConfig{Name: "x", Port: 80, Timeout: 2 * time.Second, Debug: true}
+	t.Setenv("DIFFTEST_NAME", "x")
+	t.Setenv("DIFFTEST_PORT", "8080")
+	t.Setenv("DIFFTEST_TIMEOUT", "2")
+	t.Setenv("DIFFTEST_OLD", "y")
+	diff, err := Diff(&cfg, "DIFFTEST_")
+	assert.NoError(t, err)
+	assert.Equal(t, []Difference{
+		{Name: "DIFFTEST_DEBUG", Kind: Missing, Expected: "true"},
+		{Name: "DIFFTEST_OLD", Kind: Extra, Actual: "y"},
+		{Name: "DIFFTEST_PORT", Kind: Different, Expected: "80", Actual: "8080"},
+	}, diff)
+	assert.Equal(t, `DIFFTEST_DEBUG is missing, expected "true"`, diff[0].String())
+	assert.Equal(t, `DIFFTEST_OLD="y" is extra`, diff[1].String())
+	assert.Equal(t, `DIFFTEST_PORT="8080", expected "80"`, diff[2].String())
+	t.Setenv("DIFFTEST_PORT", "80")
+	t.Setenv("DIFFTEST_DEBUG", "true")
+	diff, err = Diff(&cfg, "DIFFTEST_")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(diff), "only the extra variable is left")
+}
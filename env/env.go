@@ -0,0 +1,59 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Package env sets flags from environment variables, for 12-factor style configuration without a
+// configuration framework. It complements fortio.org/struct2env (structs to and from environment
+// variables) for FlagSets.
+package env
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"fortio.org/dflag"
+	"fortio.org/log"
+	"fortio.org/struct2env"
+)
+
+// Source is recorded in the history of the flags set from the environment, see dflag.SetFlagFrom.
+const Source = dflag.SourceEnv
+
+// FlagEnvName returns the environment variable name of the flag, see dflag.FlagEnvName.
+func FlagEnvName(prefix, name string) string {
+	return dflag.FlagEnvName(prefix, name)
+}
+
+// SetFlagsFromEnv sets the flags of flagSet that weren't set yet (e.g. on the command line when called
+// after flag.Parse) from their environment variable, see FlagEnvName. Called before flag.Parse the
+// environment provides defaults the command line can override. Errors for invalid values are aggregated.
+func SetFlagsFromEnv(prefix string, flagSet *flag.FlagSet) error {
+	return SetFlagsFrom(os.LookupEnv, prefix, flagSet)
+}
+
+// SetFlagsFrom is SetFlagsFromEnv using lookup instead of the process' environment.
+func SetFlagsFrom(lookup struct2env.EnvLookup, prefix string, flagSet *flag.FlagSet) error {
+	set := make(map[string]bool)
+	flagSet.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	var errorStrings []string
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if set[f.Name] {
+			return
+		}
+		envName := FlagEnvName(prefix, f.Name)
+		value, found := lookup(envName)
+		if !found {
+			return
+		}
+		log.S(log.Info, "Setting flag from environment", log.Str("flag", f.Name), log.Str("env", envName))
+		if err := dflag.SetFlagFrom(flagSet, f.Name, value, Source); err != nil {
+			errorStrings = append(errorStrings, fmt.Sprintf("flag %v from %s: %v", f.Name, envName, err))
+		}
+	})
+	if len(errorStrings) > 0 {
+		return fmt.Errorf("encountered %d errors while setting flags from the environment\n  %v",
+			len(errorStrings), strings.Join(errorStrings, "\n  "))
+	}
+	return nil
+}
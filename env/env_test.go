@@ -0,0 +1,47 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package env
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestFlagEnvName(t *testing.T) {
+	assert.Equal(t, "MYAPP_SOME_FLAG", FlagEnvName("MYAPP_", "some-flag"))
+	assert.Equal(t, "MYAPP_SOME_FLAG", FlagEnvName("MYAPP_", "some_flag"))
+	assert.Equal(t, "GRPC_TIMEOUT", FlagEnvName("", "grpc.timeout"))
+}
+
+func TestSetFlagsFromEnv(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(set, "some-dynint", 1, "dynamic int for testing").WithHistory(2)
+	dur := set.Duration("some_duration", time.Second, "static duration for testing")
+	str := set.String("some-string", "a", "static string for testing")
+	cmdLine := set.String("cmd-line", "a", "set on the command line")
+	set.Int("bad", 1, "invalid in the environment")
+	assert.NoError(t, set.Parse([]string{"-cmd-line=b"}))
+	t.Setenv("MYAPP_SOME_DYNINT", "42")
+	t.Setenv("MYAPP_SOME_DURATION", "1m")
+	t.Setenv("MYAPP_CMD_LINE", "c")
+	t.Setenv("SOME_STRING", "no prefix")
+	assert.NoError(t, SetFlagsFromEnv("MYAPP_", set))
+	assert.Equal(t, int64(42), dynInt.Get())
+	assert.Equal(t, Source, dynInt.History()[0].Source)
+	assert.Equal(t, time.Minute, *dur)
+	assert.Equal(t, "a", *str, "prefix required")
+	assert.Equal(t, "b", *cmdLine, "command line wins")
+	t.Setenv("MYAPP_BAD", "x")
+	t.Setenv("MYAPP_SOME_STRING", "z")
+	t.Setenv("MYAPP_SOME_DYNINT", "43")
+	err := SetFlagsFromEnv("MYAPP_", set)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "flag bad from MYAPP_BAD")
+	assert.Equal(t, "z", *str, "other flags are still set")
+	assert.Equal(t, int64(42), dynInt.Get(), "already set from the environment by the first call")
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package env
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fortio.org/struct2env"
+)
+
+// Value returns the unquoted value of kv (as produced by struct2env.StructToEnvVars), for the output
+// formats needing their own quoting.
+func Value(kv struct2env.KeyValue) string {
+	v := kv.ShellQuotedVal
+	if len(v) < 2 || v[0] != '\'' || v[len(v)-1] != '\'' {
+		return v // bools and durations aren't quoted.
+	}
+	return strings.ReplaceAll(v[1:len(v)-1], `'\''`, "'")
+}
+
+// dotEnvSafe is true when value can be written without quotes in a .env file.
+func dotEnvSafe(value string) bool {
+	for _, r := range value {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("_-./:@%+,", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// dotEnvQuote quotes value for the common dotenv parsers (docker compose, godotenv, python-dotenv...):
+// as is when safe, single quoted (no escapes nor interpolation) when possible, double quoted otherwise
+// with \, ", $ and newlines escaped.
+func dotEnvQuote(value string) string {
+	if dotEnvSafe(value) {
+		return value
+	}
+	if !strings.ContainsAny(value, "'\n\r") {
+		return "'" + value + "'"
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "\n", `\n`, "\r", `\r`).Replace(value) + `"`
+}
+
+// ToDotEnv returns the key values in .env file format, as read by docker compose's env_file and
+// many development tools: one KEY=value per line, quoted only when needed, and no export line.
+func ToDotEnv(kvl []struct2env.KeyValue) string {
+	var sb strings.Builder
+	_ = WriteDotEnv(&sb, kvl) // strings.Builder writes don't fail.
+	return sb.String()
+}
+
+// yamlSection writes the header line followed by one line per key value, indented by indent spaces.
+func yamlSection(indent int, header string, kvl []struct2env.KeyValue,
+	entry func(sb *strings.Builder, pad, key, value string),
+) string {
+	var sb strings.Builder
+	pad := strings.Repeat(" ", indent)
+	sb.WriteString(pad)
+	sb.WriteString(header)
+	sb.WriteRune('\n')
+	for _, kv := range kvl {
+		// Both Kubernetes and compose expand $ references; $$ is a literal $. Values are always quoted
+		// as they must be strings (e.g. not a yaml bool or number).
+		entry(&sb, pad+"  ", kv.Key, strconv.Quote(strings.ReplaceAll(Value(kv), "$", "$$")))
+	}
+	return sb.String()
+}
+
+// ToKubernetesEnv returns the env: section of a Kubernetes container spec setting the key values,
+// indented by indent spaces.
+func ToKubernetesEnv(indent int, kvl []struct2env.KeyValue) string {
+	return yamlSection(indent, "env:", kvl, func(sb *strings.Builder, pad, key, value string) {
+		sb.WriteString(pad + "- name: " + key + "\n")
+		sb.WriteString(pad + "  value: " + value + "\n")
+	})
+}
+
+// ToComposeEnvironment returns the environment: block of a docker compose service setting the key
+// values, indented by indent spaces.
+func ToComposeEnvironment(indent int, kvl []struct2env.KeyValue) string {
+	return yamlSection(indent, "environment:", kvl, func(sb *strings.Builder, pad, key, value string) {
+		sb.WriteString(pad + key + ": " + value + "\n")
+	})
+}
+
+// ToPowerShell returns PowerShell statements setting the key values in the environment of the current
+// session ($env:KEY = 'value'), e.g. to be dot sourced. Values are single quoted so nothing is expanded.
+func ToPowerShell(kvl []struct2env.KeyValue) string {
+	// PowerShell also ends single quoted strings on the typographic single quotes, doubling escapes all of them.
+	quote := strings.NewReplacer("'", "''", "\u2018", "\u2018\u2018", "\u2019", "\u2019\u2019",
+		"\u201a", "\u201a\u201a", "\u201b", "\u201b\u201b")
+	var sb strings.Builder
+	for _, kv := range kvl {
+		sb.WriteString("$env:" + kv.Key + " = '" + quote.Replace(Value(kv)) + "'\n")
+	}
+	return sb.String()
+}
+
+// ToBatch returns Windows batch file (.bat/.cmd) lines setting the key values (set "KEY=value").
+// The quoting protects the special characters but for % which is doubled, and assumes delayed expansion
+// isn't enabled (the default) for ! to be literal. Values with double quotes or line breaks can't be safely
+// set that way and are an error.
+func ToBatch(kvl []struct2env.KeyValue) (string, error) {
+	var sb strings.Builder
+	for _, kv := range kvl {
+		value := Value(kv)
+		if strings.ContainsAny(value, "\"\n\r") {
+			return "", fmt.Errorf("dflag: value of %s has double quotes or line breaks, not supported in batch files", kv.Key)
+		}
+		sb.WriteString(`set "` + kv.Key + "=" + strings.ReplaceAll(value, "%", "%%") + "\"\r\n")
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package env
+
+import (
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/struct2env"
+)
+
+type formatConfig struct {
+	Name    string
+	Debug   bool
+	Port    int
+	Timeout time.Duration
+	Quote   string
+	Multi   string
+	Secret  []byte
+	Empty   string
+}
+
+func formatKeyValues(t *testing.T) []struct2env.KeyValue {
+	t.Helper()
+	kvl, errs := struct2env.StructToEnvVars(&formatConfig{
+		Name:    "a-b.c",
+		Debug:   true,
+		Port:    8080,
+		Timeout: 1500 * time.Millisecond,
+		Quote:   "it's $HOME",
+		Multi:   "line1\nsay \"hi\" $x",
+		Secret:  []byte("x"),
+	})
+	assert.Equal(t, 0, len(errs))
+	return kvl
+}
+
+func TestValue(t *testing.T) {
+	kvl := formatKeyValues(t)
+	var values []string
+	for _, kv := range kvl {
+		values = append(values, Value(kv))
+	}
+	assert.Equal(t, []string{"a-b.c", "true", "8080", "1.5", "it's $HOME", "line1\nsay \"hi\" $x", "eA==", ""}, values)
+}
+
+func TestToDotEnv(t *testing.T) {
+	assert.Equal(t, `NAME=a-b.c
+DEBUG=true
+PORT=8080
+TIMEOUT=1.5
+QUOTE="it's \$HOME"
+MULTI="line1\nsay \"hi\" \$x"
+SECRET='eA=='
+EMPTY=
+`, ToDotEnv(formatKeyValues(t)))
+	assert.Equal(t, "A='x y $z'\n", ToDotEnv([]struct2env.KeyValue{{Key: "A", ShellQuotedVal: "'x y $z'"}}))
+}
+
+func TestToKubernetesEnv(t *testing.T) {
+	assert.Equal(t, `    env:
+      - name: NAME
+        value: "a-b.c"
+      - name: DEBUG
+        value: "true"
+      - name: PORT
+        value: "8080"
+      - name: TIMEOUT
+        value: "1.5"
+      - name: QUOTE
+        value: "it's $$HOME"
+      - name: MULTI
+        value: "line1\nsay \"hi\" $$x"
+      - name: SECRET
+        value: "eA=="
+      - name: EMPTY
+        value: ""
+`, ToKubernetesEnv(4, formatKeyValues(t)))
+}
+
+func TestToComposeEnvironment(t *testing.T) {
+	assert.Equal(t, `environment:
+  NAME: "a-b.c"
+  DEBUG: "true"
+  PORT: "8080"
+  TIMEOUT: "1.5"
+  QUOTE: "it's $$HOME"
+  MULTI: "line1\nsay \"hi\" $$x"
+  SECRET: "eA=="
+  EMPTY: ""
+`, ToComposeEnvironment(0, formatKeyValues(t)))
+}
+
+func TestToPowerShell(t *testing.T) {
+	assert.Equal(t, `$env:NAME = 'a-b.c'
+$env:DEBUG = 'true'
+$env:PORT = '8080'
+$env:TIMEOUT = '1.5'
+$env:QUOTE = 'it''s $HOME'
+$env:MULTI = 'line1
+say "hi" $x'
+$env:SECRET = 'eA=='
+$env:EMPTY = ''
+`, ToPowerShell(formatKeyValues(t)))
+	assert.Equal(t, "$env:TYPO = 'a\u2019\u2019b'\n",
+		ToPowerShell([]struct2env.KeyValue{{Key: "TYPO", ShellQuotedVal: "'a\u2019b'"}}))
+}
+
+func TestToBatch(t *testing.T) {
+	_, err := ToBatch(formatKeyValues(t))
+	assert.Error(t, err, "double quotes and line breaks aren't supported")
+	res, err := ToBatch([]struct2env.KeyValue{
+		{Key: "A", ShellQuotedVal: "'50% & <more> | ^!'"},
+		{Key: "B", ShellQuotedVal: "true"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "set \"A=50%% & <more> | ^!\"\r\nset \"B=true\"\r\n", res)
+}
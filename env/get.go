@@ -0,0 +1,25 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package env
+
+import (
+	"fmt"
+	"os"
+
+	"fortio.org/dflag"
+)
+
+// Get returns the value of the environment variable name parsed like the dynamic flags of the same type
+// parse theirs (dflag.Parse), or def when it isn't set. On a parse error def is returned with the error.
+func Get[T dflag.DynValueTypes](name string, def T) (T, error) {
+	value, found := os.LookupEnv(name)
+	if !found {
+		return def, nil
+	}
+	v, err := dflag.Parse[T](value)
+	if err != nil {
+		return def, fmt.Errorf("dflag: invalid %s=%q: %w", name, value, err)
+	}
+	return v, nil
+}
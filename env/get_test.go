@@ -0,0 +1,34 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package env
+
+import (
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+)
+
+func TestGet(t *testing.T) {
+	t.Setenv("TEST_GET_INT", " 0x10 ")
+	t.Setenv("TEST_GET_DURATION", "1m30s")
+	t.Setenv("TEST_GET_LIST", "a,b")
+	t.Setenv("TEST_GET_BAD", "nope")
+	i, err := Get("TEST_GET_INT", int64(1))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(16), i)
+	d, err := Get("TEST_GET_DURATION", time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, d)
+	l, err := Get("TEST_GET_LIST", []string{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, l)
+	f, err := Get("TEST_GET_MISSING", 0.5)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, f)
+	b, err := Get("TEST_GET_BAD", true)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "TEST_GET_BAD")
+	assert.True(t, b, "default on error")
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package env
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"time"
+
+	"fortio.org/dflag"
+	"fortio.org/struct2env"
+)
+
+// StructToFlags registers a dynamic flag on flagSet for each exported field of the struct s points to,
+// with the field's value as default, like struct2env.StructToEnvVars walks them: the flag name is the prefix
+// followed by the field name in kebab-case (e.g. HTTPPort is http-port) or the `flag` tag (`flag:"-"` skips
+// the field), nested structs' fields are prefixed by their name and a dash, and the usage comes from the
+// `usage` tag. Each time a flag is set, the new value is written back to its field (synchronously in the
+// setter's goroutine, so concurrent readers of the struct need their own synchronization). Supported field types are bool, string, ints, floats, time.Duration,
+// []string and []byte; other fields are reported as errors.
+func StructToFlags(flagSet *flag.FlagSet, prefix string, s interface{}) []error {
+	v := reflect.ValueOf(s)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return []error{fmt.Errorf("unexpected %T, expected a pointer to a struct", s)}
+	}
+	return structToFlags(nil, flagSet, prefix, v.Elem())
+}
+
+func structToFlags(allErrors []error, flagSet *flag.FlagSet, prefix string, v reflect.Value) []error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+		tag := fieldType.Tag.Get("flag")
+		if tag == "-" || !fieldType.IsExported() {
+			continue
+		}
+		if fieldType.Anonymous && fieldType.Type.Kind() == reflect.Struct {
+			allErrors = structToFlags(allErrors, flagSet, prefix, v.Field(i))
+			continue
+		}
+		if tag == "" {
+			tag = struct2env.CamelCaseToLowerKebabCase(fieldType.Name)
+		}
+		name := prefix + tag
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			allErrors = structToFlags(allErrors, flagSet, name+"-", field)
+			continue
+		}
+		if err := fieldFlag(flagSet, name, fieldType.Tag.Get("usage"), field); err != nil {
+			allErrors = append(allErrors, fmt.Errorf("field %s: %w", fieldType.Name, err))
+		}
+	}
+	return allErrors
+}
+
+// fieldFlag registers the dynamic flag of the field, setting the field on changes.
+func fieldFlag(flagSet *flag.FlagSet, name, usage string, field reflect.Value) error {
+	switch field.Kind() { //nolint:exhaustive // other kinds are errors.
+	case reflect.Bool:
+		dflag.FlagSetBool(flagSet, name, dflag.NewBool(field.Bool(), usage)).
+			WithSyncNotifier(func(_, newValue bool) { field.SetBool(newValue) })
+	case reflect.String:
+		dflag.Dyn(flagSet, name, field.String(), usage).
+			WithSyncNotifier(func(_, newValue string) { field.SetString(newValue) })
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.Type() == reflect.TypeOf(time.Duration(0)) {
+			dflag.Dyn(flagSet, name, time.Duration(field.Int()), usage).
+				WithSyncNotifier(func(_, newValue time.Duration) { field.SetInt(int64(newValue)) })
+			return nil
+		}
+		dflag.Dyn(flagSet, name, field.Int(), usage).
+			WithValidator(func(value int64) error {
+				if field.OverflowInt(value) {
+					return fmt.Errorf("value %d overflows %v", value, field.Type())
+				}
+				return nil
+			}).
+			WithSyncNotifier(func(_, newValue int64) { field.SetInt(newValue) })
+	case reflect.Float32, reflect.Float64:
+		dflag.Dyn(flagSet, name, field.Float(), usage).
+			WithSyncNotifier(func(_, newValue float64) { field.SetFloat(newValue) })
+	case reflect.Slice:
+		switch field.Type().Elem().Kind() { //nolint:exhaustive // other kinds are errors.
+		case reflect.String:
+			dflag.Dyn(flagSet, name, field.Interface().([]string), usage).
+				WithSyncNotifier(func(_, newValue []string) { field.Set(reflect.ValueOf(newValue)) })
+		case reflect.Uint8:
+			dflag.Dyn(flagSet, name, field.Bytes(), usage).
+				WithSyncNotifier(func(_, newValue []byte) { field.SetBytes(newValue) })
+		default:
+			return fmt.Errorf("unsupported type %v", field.Type())
+		}
+	default:
+		return fmt.Errorf("unsupported type %v", field.Type())
+	}
+	return nil
+}
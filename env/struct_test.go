@@ -0,0 +1,57 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package env
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+)
+
+type Limits struct {
+	MaxConns int32   `usage:"maximum number of connections"`
+	Ratio    float64 `flag:"ratio-pct"`
+}
+
+type config struct {
+	HTTPPort int
+	Name     string `usage:"the name"`
+	Debug    bool
+	Timeout  time.Duration
+	Tags     []string
+	Blob     []byte
+	Limits   Limits
+	Skipped  string `flag:"-"`
+	Bad      map[string]int
+	private  int
+}
+
+func TestStructToFlags(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	c := &config{HTTPPort: 8080, Name: "a", Timeout: time.Second, Tags: []string{"x"}, Limits: Limits{MaxConns: 10}}
+	errs := StructToFlags(set, "app-", c)
+	assert.Equal(t, 1, len(errs), "only Bad isn't supported")
+	assert.Contains(t, errs[0].Error(), "field Bad: unsupported type map[string]int")
+	assert.Equal(t, "8080", set.Lookup("app-http-port").DefValue)
+	assert.Equal(t, "the name", set.Lookup("app-name").Usage)
+	assert.Equal(t, "maximum number of connections", set.Lookup("app-limits-max-conns").Usage)
+	assert.True(t, set.Lookup("app-skipped") == nil, "skipped")
+	assert.True(t, set.Lookup("app-private") == nil, "not exported")
+	assert.NoError(t, set.Parse([]string{"-app-debug", "-app-http-port=9090", "-app-tags=y,z"}))
+	assert.NoError(t, set.Set("app-timeout", "1m"))
+	assert.NoError(t, set.Set("app-limits-ratio-pct", "0.5"))
+	assert.NoError(t, set.Set("app-blob", "AQI="))
+	assert.Error(t, set.Set("app-limits-max-conns", "3000000000"), "overflows int32")
+	assert.NoError(t, set.Set("app-limits-max-conns", "20"))
+	assert.True(t, c.Debug)
+	assert.Equal(t, 9090, c.HTTPPort)
+	assert.Equal(t, []string{"y", "z"}, c.Tags)
+	assert.Equal(t, time.Minute, c.Timeout)
+	assert.Equal(t, 0.5, c.Limits.Ratio)
+	assert.Equal(t, int32(20), c.Limits.MaxConns)
+	assert.Equal(t, []byte{1, 2}, c.Blob)
+	assert.Equal(t, 1, len(StructToFlags(set, "", *c)), "not a pointer")
+}
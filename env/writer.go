@@ -0,0 +1,60 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package env
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"fortio.org/struct2env"
+)
+
+// WriteShell writes the key values to w in the struct2env.ToShellWithPrefix format (bourne shell
+// assignments followed by an export line), without building the whole output in memory first.
+func WriteShell(w io.Writer, prefix string, kvl []struct2env.KeyValue) error {
+	keys := make([]string, 0, len(kvl))
+	for _, kv := range kvl {
+		if _, err := io.WriteString(w, prefix+kv.ToShell()+"\n"); err != nil {
+			return err
+		}
+		keys = append(keys, prefix+kv.Key)
+	}
+	_, err := io.WriteString(w, "export "+strings.Join(keys, " ")+"\n")
+	return err
+}
+
+// WriteDotEnv is ToDotEnv writing to w.
+func WriteDotEnv(w io.Writer, kvl []struct2env.KeyValue) error {
+	for _, kv := range kvl {
+		if _, err := io.WriteString(w, kv.Key+"="+dotEnvQuote(Value(kv))+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StructToEnvWriter writes the environment variables of s (see struct2env.StructToEnvVars) to w with
+// WriteShell, e.g. for a configuration dump endpoint. The fields that couldn't be serialized are skipped
+// and reported in the returned error.
+func StructToEnvWriter(w io.Writer, prefix string, s interface{}) error {
+	kvl, errs := struct2env.StructToEnvVars(s)
+	if err := WriteShell(w, prefix, kvl); err != nil {
+		return err
+	}
+	return serializationError(errs)
+}
+
+// serializationError aggregates the errors of struct2env.StructToEnvVars, nil if there are none.
+func serializationError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	errorStrings := make([]string, 0, len(errs))
+	for _, err := range errs {
+		errorStrings = append(errorStrings, err.Error())
+	}
+	return fmt.Errorf("encountered %d errors while serializing the struct\n  %v",
+		len(errs), strings.Join(errorStrings, "\n  "))
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package env
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/struct2env"
+)
+
+type failingWriter struct{ left int }
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if f.left == 0 {
+		return 0, errors.New("disk full")
+	}
+	f.left--
+	return len(p), nil
+}
+
+func TestWriteShell(t *testing.T) {
+	kvl := formatKeyValues(t)
+	var buf bytes.Buffer
+	assert.NoError(t, WriteShell(&buf, "APP_", kvl))
+	assert.Equal(t, struct2env.ToShellWithPrefix("APP_", kvl, false), buf.String())
+	buf.Reset()
+	assert.NoError(t, WriteDotEnv(&buf, kvl))
+	assert.Equal(t, ToDotEnv(kvl), buf.String())
+	assert.Error(t, WriteShell(&failingWriter{left: 3}, "", kvl))
+	assert.Error(t, WriteDotEnv(&failingWriter{}, kvl))
+}
+
+func TestStructToEnvWriter(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, StructToEnvWriter(&buf, "APP_", &struct {
+		Name string
+		Port int
+	}{Name: "x", Port: 80}))
+	assert.Equal(t, "APP_NAME='x'\nAPP_PORT='80'\nexport APP_NAME APP_PORT\n", buf.String())
+	buf.Reset()
+	err := StructToEnvWriter(&buf, "", &struct {
+		Name string
+		Bad  string
+	}{Name: "x", Bad: "nul\x00"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "1 errors")
+	assert.Contains(t, buf.String(), "NAME='x'")
+}
@@ -69,12 +69,12 @@ func main() {
 	var dflagEndpoint *endpoint.FlagsEndpoint
 	if *hasSetFlag {
 		setURL := "/debug/flags/set"
-		dflagEndpoint = endpoint.NewFlagsEndpoint(flag.CommandLine, setURL)
-		http.HandleFunc(setURL, dflagEndpoint.SetFlag)
+		dflagEndpoint = endpoint.NewFlagsEndpoint(flag.CommandLine, setURL).WithCSRFProtection(nil).WithAuditHistory(100)
 	} else {
 		dflagEndpoint = endpoint.NewFlagsEndpoint(flag.CommandLine, "")
 	}
-	http.HandleFunc("/debug/flags", dflagEndpoint.ListFlags)
+	// list, get (e.g. /debug/flags/example_str2), diff, export... and set when enabled.
+	dflagEndpoint.Mount(http.DefaultServeMux, "/debug/flags")
 	http.HandleFunc("/", handleDefaultPage)
 
 	addr := fmt.Sprintf("%s:%d", *listenHost, *listenPort)
@@ -0,0 +1,73 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"flag"
+	"strings"
+	"testing"
+
+	"fortio.org/assert"
+)
+
+var fuzzTypes = []string{"bool", "int64", "float64", "time.Duration", "string", "[]string", "sets.Set[string]", "[]byte"}
+
+func TestParseAny(t *testing.T) {
+	v, err := ParseAny("int64", " 0x10 ")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(16), v)
+	v, err = ParseAny("[]uint8", "AAEC")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 1, 2}, v)
+	_, err = ParseAny("int", "1")
+	assert.Error(t, err)
+	for _, typeName := range fuzzTypes {
+		_, err = ParseAny(typeName, "")
+		if typeName == "string" || typeName == "[]string" || typeName == "sets.Set[string]" || typeName == "[]byte" {
+			assert.NoError(t, err, typeName)
+		} else {
+			assert.Error(t, err, typeName)
+		}
+	}
+}
+
+// FuzzParseAny checks parsing doesn't panic and that the string representation of parsed values parses.
+func FuzzParseAny(f *testing.F) {
+	for _, seed := range []string{"", "1", "-0x1F", "1e309", "NaN", "true", "1h2m3.5s", "a,b,,c", "AAEC", "=", "\x00\xff"} {
+		for i := range fuzzTypes {
+			f.Add(uint8(i), seed)
+		}
+	}
+	f.Fuzz(func(t *testing.T, typeIdx uint8, input string) {
+		typeName := fuzzTypes[int(typeIdx)%len(fuzzTypes)]
+		v, err := ParseAny(typeName, input)
+		if err != nil {
+			return
+		}
+		if _, err = ParseAny(typeName, valueString(v)); err != nil {
+			t.Errorf("%s %q parsed to %v whose representation doesn't parse: %v", typeName, input, v, err)
+		}
+	})
+}
+
+// FuzzDynJSONSet checks the JSON flags' Set, reachable from the endpoint, doesn't panic and keeps a valid value.
+func FuzzDynJSONSet(f *testing.F) {
+	for _, seed := range []string{`{}`, `{"ints":[1,2]}`, `{"string":1}`, `null`, `[`, `{"inner":{"bool":true}}`, `{"inner":null}`} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		set := flag.NewFlagSet("fuzz", flag.ContinueOnError)
+		d := DynJSON(set, "json", &outerJSON{FieldString: "x"}, "fuzzed json")
+		if err := set.Set("json", input); err != nil {
+			return
+		}
+		out := d.String()
+		if strings.HasPrefix(out, "ERR") {
+			t.Errorf("%q set but can't be marshaled back", input)
+		}
+		if err := d.Set(out); err != nil {
+			t.Errorf("%q set to %q which doesn't set back: %v", input, out, err)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"fortio.org/sets"
+)
+
+// GetPolicy is whether Get() (and Default() and the notifiers' values) returns copies of the flag's
+// value or the stored value itself, see WithGetPolicy.
+type GetPolicy int
+
+const (
+	// CopyOnGet returns copies, so callers modifying them don't change the value seen by the others.
+	// It's the default for the []string, []byte and sets.Set[string] flags and costs an allocation and copy
+	// per Get(). DynJSON flags copy through a JSON marshal and unmarshal, which is much more expensive
+	// (see the benchmarks of getpolicy_test.go).
+	CopyOnGet GetPolicy = iota + 1
+	// Immutable returns the stored value: the callers must not modify it. It's the default for DynJSON flags
+	// (and the only behavior of DynTypedJSON's Load()) and makes no difference for the other types.
+	Immutable
+)
+
+// WithGetPolicy sets whether Get() returns copies of slice and set values, see GetPolicy.
+func (d *DynValue[T]) WithGetPolicy(policy GetPolicy) *DynValue[T] {
+	d.copier = nil
+	if policy == CopyOnGet {
+		d.copier = defaultCopier[T]()
+	}
+	return d
+}
+
+// WithGetPolicy sets whether Get() returns a (deep) copy of the value, see GetPolicy.
+func (d *DynJSONValue) WithGetPolicy(policy GetPolicy) *DynJSONValue {
+	d.copier = nil
+	if policy == CopyOnGet {
+		d.copier = d.jsonCopy
+	}
+	return d
+}
+
+// jsonCopy returns a copy of v through JSON, v itself if that fails.
+func (d *DynJSONValue) jsonCopy(v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	res := reflect.New(d.structType).Interface()
+	if err := json.Unmarshal(b, res); err != nil {
+		return v
+	}
+	return res
+}
+
+// defaultCopier returns the function copying the values of the mutable types (slices and sets), nil
+// for the other ones.
+func defaultCopier[T any]() func(T) T {
+	var zero T
+	switch any(zero).(type) {
+	case []string:
+		return func(v T) T { return any(cloneSlice(any(v).([]string))).(T) }
+	case []byte:
+		return func(v T) T { return any(cloneSlice(any(v).([]byte))).(T) }
+	case sets.Set[string]:
+		return func(v T) T {
+			if s := any(v).(sets.Set[string]); s != nil {
+				return any(s.Clone()).(T)
+			}
+			return v
+		}
+	default:
+		return nil
+	}
+}
+
+// cloneSlice is slices.Clone (Go 1.21+): nil stays nil and empty stays empty.
+func cloneSlice[S ~[]E, E any](s S) S {
+	if s == nil {
+		return nil
+	}
+	return append(S{}, s...)
+}
@@ -1,17 +1,17 @@
 module fortio.org/dflag
 
-go 1.19
+go 1.20
 
 require (
 	fortio.org/assert v1.2.1
 	fortio.org/log v1.17.1
 	fortio.org/sets v1.2.0
+	fortio.org/struct2env v0.4.1
 	github.com/fsnotify/fsnotify v1.7.0
 	golang.org/x/exp v0.0.0-20240604190554-fc45aab8b7f8
 )
 
 require (
-	fortio.org/struct2env v0.4.1 // indirect
 	github.com/kortschak/goroutine v1.1.2 // indirect
 	golang.org/x/sys v0.21.0 // indirect
 )
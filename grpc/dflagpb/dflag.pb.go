@@ -0,0 +1,544 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: dflag.proto
+
+package dflagpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Flag struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Name         string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description  string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	CurrentValue string                 `protobuf:"bytes,3,opt,name=current_value,json=currentValue,proto3" json:"current_value,omitempty"`
+	DefaultValue string                 `protobuf:"bytes,4,opt,name=default_value,json=defaultValue,proto3" json:"default_value,omitempty"`
+	IsChanged    bool                   `protobuf:"varint,5,opt,name=is_changed,json=isChanged,proto3" json:"is_changed,omitempty"`
+	IsDynamic    bool                   `protobuf:"varint,6,opt,name=is_dynamic,json=isDynamic,proto3" json:"is_dynamic,omitempty"`
+	IsJson       bool                   `protobuf:"varint,7,opt,name=is_json,json=isJson,proto3" json:"is_json,omitempty"`
+	// Number of times the dynamic flag was set.
+	Generation    uint64 `protobuf:"varint,8,opt,name=generation,proto3" json:"generation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Flag) Reset() {
+	*x = Flag{}
+	mi := &file_dflag_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Flag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Flag) ProtoMessage() {}
+
+func (x *Flag) ProtoReflect() protoreflect.Message {
+	mi := &file_dflag_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Flag.ProtoReflect.Descriptor instead.
+func (*Flag) Descriptor() ([]byte, []int) {
+	return file_dflag_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Flag) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Flag) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Flag) GetCurrentValue() string {
+	if x != nil {
+		return x.CurrentValue
+	}
+	return ""
+}
+
+func (x *Flag) GetDefaultValue() string {
+	if x != nil {
+		return x.DefaultValue
+	}
+	return ""
+}
+
+func (x *Flag) GetIsChanged() bool {
+	if x != nil {
+		return x.IsChanged
+	}
+	return false
+}
+
+func (x *Flag) GetIsDynamic() bool {
+	if x != nil {
+		return x.IsDynamic
+	}
+	return false
+}
+
+func (x *Flag) GetIsJson() bool {
+	if x != nil {
+		return x.IsJson
+	}
+	return false
+}
+
+func (x *Flag) GetGeneration() uint64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+type ListRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only the dynamic flags.
+	DynamicOnly bool `protobuf:"varint,1,opt,name=dynamic_only,json=dynamicOnly,proto3" json:"dynamic_only,omitempty"`
+	// Only the flags whose value differs from their default.
+	ChangedOnly bool `protobuf:"varint,2,opt,name=changed_only,json=changedOnly,proto3" json:"changed_only,omitempty"`
+	// Only the flags whose name starts with prefix.
+	Prefix        string `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	mi := &file_dflag_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dflag_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_dflag_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListRequest) GetDynamicOnly() bool {
+	if x != nil {
+		return x.DynamicOnly
+	}
+	return false
+}
+
+func (x *ListRequest) GetChangedOnly() bool {
+	if x != nil {
+		return x.ChangedOnly
+	}
+	return false
+}
+
+func (x *ListRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+type ListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Flags         []*Flag                `protobuf:"bytes,1,rep,name=flags,proto3" json:"flags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListResponse) Reset() {
+	*x = ListResponse{}
+	mi := &file_dflag_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponse) ProtoMessage() {}
+
+func (x *ListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dflag_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return file_dflag_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListResponse) GetFlags() []*Flag {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+type GetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	mi := &file_dflag_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dflag_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_dflag_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type SetRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// Only parse and validate the value.
+	DryRun        bool `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRequest) Reset() {
+	*x = SetRequest{}
+	mi := &file_dflag_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRequest) ProtoMessage() {}
+
+func (x *SetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dflag_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRequest.ProtoReflect.Descriptor instead.
+func (*SetRequest) Descriptor() ([]byte, []int) {
+	return file_dflag_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SetRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetRequest) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *SetRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type SetResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The flag after the set (unchanged for a dry run).
+	Flag          *Flag `protobuf:"bytes,1,opt,name=flag,proto3" json:"flag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetResponse) Reset() {
+	*x = SetResponse{}
+	mi := &file_dflag_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetResponse) ProtoMessage() {}
+
+func (x *SetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dflag_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetResponse.ProtoReflect.Descriptor instead.
+func (*SetResponse) Descriptor() ([]byte, []int) {
+	return file_dflag_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SetResponse) GetFlag() *Flag {
+	if x != nil {
+		return x.Flag
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Flags to watch, all the dynamic ones when empty.
+	Names []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	// Also send the current values when the watch starts.
+	SendInitial   bool `protobuf:"varint,2,opt,name=send_initial,json=sendInitial,proto3" json:"send_initial,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_dflag_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dflag_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_dflag_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WatchRequest) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetSendInitial() bool {
+	if x != nil {
+		return x.SendInitial
+	}
+	return false
+}
+
+var File_dflag_proto protoreflect.FileDescriptor
+
+const file_dflag_proto_rawDesc = "" +
+	"\n" +
+	"\vdflag.proto\x12\x0ffortio.dflag.v1\"\xfd\x01\n" +
+	"\x04Flag\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12#\n" +
+	"\rcurrent_value\x18\x03 \x01(\tR\fcurrentValue\x12#\n" +
+	"\rdefault_value\x18\x04 \x01(\tR\fdefaultValue\x12\x1d\n" +
+	"\n" +
+	"is_changed\x18\x05 \x01(\bR\tisChanged\x12\x1d\n" +
+	"\n" +
+	"is_dynamic\x18\x06 \x01(\bR\tisDynamic\x12\x17\n" +
+	"\ais_json\x18\a \x01(\bR\x06isJson\x12\x1e\n" +
+	"\n" +
+	"generation\x18\b \x01(\x04R\n" +
+	"generation\"k\n" +
+	"\vListRequest\x12!\n" +
+	"\fdynamic_only\x18\x01 \x01(\bR\vdynamicOnly\x12!\n" +
+	"\fchanged_only\x18\x02 \x01(\bR\vchangedOnly\x12\x16\n" +
+	"\x06prefix\x18\x03 \x01(\tR\x06prefix\";\n" +
+	"\fListResponse\x12+\n" +
+	"\x05flags\x18\x01 \x03(\v2\x15.fortio.dflag.v1.FlagR\x05flags\" \n" +
+	"\n" +
+	"GetRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"O\n" +
+	"\n" +
+	"SetRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"8\n" +
+	"\vSetResponse\x12)\n" +
+	"\x04flag\x18\x01 \x01(\v2\x15.fortio.dflag.v1.FlagR\x04flag\"G\n" +
+	"\fWatchRequest\x12\x14\n" +
+	"\x05names\x18\x01 \x03(\tR\x05names\x12!\n" +
+	"\fsend_initial\x18\x02 \x01(\bR\vsendInitial2\x8a\x02\n" +
+	"\x05Flags\x12C\n" +
+	"\x04List\x12\x1c.fortio.dflag.v1.ListRequest\x1a\x1d.fortio.dflag.v1.ListResponse\x129\n" +
+	"\x03Get\x12\x1b.fortio.dflag.v1.GetRequest\x1a\x15.fortio.dflag.v1.Flag\x12@\n" +
+	"\x03Set\x12\x1b.fortio.dflag.v1.SetRequest\x1a\x1c.fortio.dflag.v1.SetResponse\x12?\n" +
+	"\x05Watch\x12\x1d.fortio.dflag.v1.WatchRequest\x1a\x15.fortio.dflag.v1.Flag0\x01B\x1fZ\x1dfortio.org/dflag/grpc/dflagpbb\x06proto3"
+
+var (
+	file_dflag_proto_rawDescOnce sync.Once
+	file_dflag_proto_rawDescData []byte
+)
+
+func file_dflag_proto_rawDescGZIP() []byte {
+	file_dflag_proto_rawDescOnce.Do(func() {
+		file_dflag_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_dflag_proto_rawDesc), len(file_dflag_proto_rawDesc)))
+	})
+	return file_dflag_proto_rawDescData
+}
+
+var file_dflag_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_dflag_proto_goTypes = []any{
+	(*Flag)(nil),         // 0: fortio.dflag.v1.Flag
+	(*ListRequest)(nil),  // 1: fortio.dflag.v1.ListRequest
+	(*ListResponse)(nil), // 2: fortio.dflag.v1.ListResponse
+	(*GetRequest)(nil),   // 3: fortio.dflag.v1.GetRequest
+	(*SetRequest)(nil),   // 4: fortio.dflag.v1.SetRequest
+	(*SetResponse)(nil),  // 5: fortio.dflag.v1.SetResponse
+	(*WatchRequest)(nil), // 6: fortio.dflag.v1.WatchRequest
+}
+var file_dflag_proto_depIdxs = []int32{
+	0, // 0: fortio.dflag.v1.ListResponse.flags:type_name -> fortio.dflag.v1.Flag
+	0, // 1: fortio.dflag.v1.SetResponse.flag:type_name -> fortio.dflag.v1.Flag
+	1, // 2: fortio.dflag.v1.Flags.List:input_type -> fortio.dflag.v1.ListRequest
+	3, // 3: fortio.dflag.v1.Flags.Get:input_type -> fortio.dflag.v1.GetRequest
+	4, // 4: fortio.dflag.v1.Flags.Set:input_type -> fortio.dflag.v1.SetRequest
+	6, // 5: fortio.dflag.v1.Flags.Watch:input_type -> fortio.dflag.v1.WatchRequest
+	2, // 6: fortio.dflag.v1.Flags.List:output_type -> fortio.dflag.v1.ListResponse
+	0, // 7: fortio.dflag.v1.Flags.Get:output_type -> fortio.dflag.v1.Flag
+	5, // 8: fortio.dflag.v1.Flags.Set:output_type -> fortio.dflag.v1.SetResponse
+	0, // 9: fortio.dflag.v1.Flags.Watch:output_type -> fortio.dflag.v1.Flag
+	6, // [6:10] is the sub-list for method output_type
+	2, // [2:6] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_dflag_proto_init() }
+func file_dflag_proto_init() {
+	if File_dflag_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_dflag_proto_rawDesc), len(file_dflag_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_dflag_proto_goTypes,
+		DependencyIndexes: file_dflag_proto_depIdxs,
+		MessageInfos:      file_dflag_proto_msgTypes,
+	}.Build()
+	File_dflag_proto = out.File
+	file_dflag_proto_goTypes = nil
+	file_dflag_proto_depIdxs = nil
+}
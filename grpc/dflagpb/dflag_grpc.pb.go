@@ -0,0 +1,254 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: dflag.proto
+
+package dflagpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Flags_List_FullMethodName  = "/fortio.dflag.v1.Flags/List"
+	Flags_Get_FullMethodName   = "/fortio.dflag.v1.Flags/Get"
+	Flags_Set_FullMethodName   = "/fortio.dflag.v1.Flags/Set"
+	Flags_Watch_FullMethodName = "/fortio.dflag.v1.Flags/Watch"
+)
+
+// FlagsClient is the client API for Flags service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Flags is the admin service of the flags of a FlagSet, mirroring the HTTP endpoint.
+type FlagsClient interface {
+	// List returns the flags, optionally filtered.
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	// Get returns one flag.
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Flag, error)
+	// Set changes the value of a dynamic flag (or only validates it with dry_run).
+	Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*SetResponse, error)
+	// Watch streams the watched dynamic flags each time they change.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Flag], error)
+}
+
+type flagsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFlagsClient(cc grpc.ClientConnInterface) FlagsClient {
+	return &flagsClient{cc}
+}
+
+func (c *flagsClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, Flags_List_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagsClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Flag, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Flag)
+	err := c.cc.Invoke(ctx, Flags_Get_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagsClient) Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*SetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetResponse)
+	err := c.cc.Invoke(ctx, Flags_Set_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagsClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Flag], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Flags_ServiceDesc.Streams[0], Flags_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, Flag]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Flags_WatchClient = grpc.ServerStreamingClient[Flag]
+
+// FlagsServer is the server API for Flags service.
+// All implementations must embed UnimplementedFlagsServer
+// for forward compatibility.
+//
+// Flags is the admin service of the flags of a FlagSet, mirroring the HTTP endpoint.
+type FlagsServer interface {
+	// List returns the flags, optionally filtered.
+	List(context.Context, *ListRequest) (*ListResponse, error)
+	// Get returns one flag.
+	Get(context.Context, *GetRequest) (*Flag, error)
+	// Set changes the value of a dynamic flag (or only validates it with dry_run).
+	Set(context.Context, *SetRequest) (*SetResponse, error)
+	// Watch streams the watched dynamic flags each time they change.
+	Watch(*WatchRequest, grpc.ServerStreamingServer[Flag]) error
+	mustEmbedUnimplementedFlagsServer()
+}
+
+// UnimplementedFlagsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFlagsServer struct{}
+
+func (UnimplementedFlagsServer) List(context.Context, *ListRequest) (*ListResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedFlagsServer) Get(context.Context, *GetRequest) (*Flag, error) {
+	return nil, status.Error(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedFlagsServer) Set(context.Context, *SetRequest) (*SetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Set not implemented")
+}
+func (UnimplementedFlagsServer) Watch(*WatchRequest, grpc.ServerStreamingServer[Flag]) error {
+	return status.Error(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedFlagsServer) mustEmbedUnimplementedFlagsServer() {}
+func (UnimplementedFlagsServer) testEmbeddedByValue()               {}
+
+// UnsafeFlagsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FlagsServer will
+// result in compilation errors.
+type UnsafeFlagsServer interface {
+	mustEmbedUnimplementedFlagsServer()
+}
+
+func RegisterFlagsServer(s grpc.ServiceRegistrar, srv FlagsServer) {
+	// If the following call panics, it indicates UnimplementedFlagsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Flags_ServiceDesc, srv)
+}
+
+func _Flags_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagsServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Flags_List_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagsServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Flags_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagsServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Flags_Get_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagsServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Flags_Set_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagsServer).Set(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Flags_Set_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagsServer).Set(ctx, req.(*SetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Flags_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FlagsServer).Watch(m, &grpc.GenericServerStream[WatchRequest, Flag]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Flags_WatchServer = grpc.ServerStreamingServer[Flag]
+
+// Flags_ServiceDesc is the grpc.ServiceDesc for Flags service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Flags_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "fortio.dflag.v1.Flags",
+	HandlerType: (*FlagsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _Flags_List_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _Flags_Get_Handler,
+		},
+		{
+			MethodName: "Set",
+			Handler:    _Flags_Set_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Flags_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "dflag.proto",
+}
@@ -0,0 +1,26 @@
+module fortio.org/dflag/grpc
+
+go 1.25.0
+
+require (
+	fortio.org/assert v1.2.1
+	fortio.org/dflag v1.9.0
+	fortio.org/log v1.17.1
+	google.golang.org/grpc v1.84.0
+	google.golang.org/protobuf v1.36.11
+)
+
+require (
+	fortio.org/sets v1.2.0 // indirect
+	fortio.org/struct2env v0.4.1 // indirect
+	github.com/kortschak/goroutine v1.1.2 // indirect
+	golang.org/x/exp v0.0.0-20240604190554-fc45aab8b7f8 // indirect
+	golang.org/x/net v0.57.0 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+	golang.org/x/text v0.40.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260706201446-f0a921348800 // indirect
+)
+
+// Build against the dflag module of this repository (the required v1.9.0 must be tagged, see
+// Releasing in the README, before this module is).
+replace fortio.org/dflag => ../
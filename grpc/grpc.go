@@ -0,0 +1,203 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Package grpc is a gRPC admin service for the flags of a FlagSet, mirroring the HTTP endpoint:
+// List, Get, Set and a streaming Watch of the dynamic flags, for fleets whose admin plane is gRPC only.
+// It is its own module so the main dflag module doesn't depend on gRPC. Authentication and
+// authorization are left to the grpc.Server's interceptors and credentials, e.g.
+//
+//	s := grpc.NewServer(grpc.Creds(creds), grpc.UnaryInterceptor(auth))
+//	dflaggrpc.NewServer(flag.CommandLine).Register(s)
+//
+// The service is defined in dflagpb/dflag.proto.
+package grpc
+
+//go:generate protoc -I dflagpb --go_out=dflagpb --go_opt=paths=source_relative --go-grpc_out=dflagpb --go-grpc_opt=paths=source_relative dflag.proto
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"strings"
+
+	"fortio.org/dflag"
+	"fortio.org/dflag/grpc/dflagpb"
+	"fortio.org/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Source is recorded in the history of the flags set through this service, see dflag.SetFlagFrom.
+const Source = "grpc"
+
+// Server implements the dflagpb.FlagsServer service for a FlagSet.
+type Server struct {
+	dflagpb.UnimplementedFlagsServer
+	flagSet      *flag.FlagSet
+	setPredicate func(f *flag.Flag) bool
+}
+
+// NewServer returns the admin service of the flagSet's flags.
+func NewServer(flagSet *flag.FlagSet) *Server {
+	return &Server{flagSet: flagSet}
+}
+
+// WithSetPredicate restricts which dynamic flags Set can change (it returns PermissionDenied for the others),
+// like the endpoint's WithSetPredicate.
+func (s *Server) WithSetPredicate(predicate func(f *flag.Flag) bool) *Server {
+	s.setPredicate = predicate
+	return s
+}
+
+// Register registers the service on the gRPC server.
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	dflagpb.RegisterFlagsServer(registrar, s)
+}
+
+func flagToProto(f *flag.Flag) *dflagpb.Flag {
+	res := &dflagpb.Flag{
+		Name:         f.Name,
+		Description:  f.Usage,
+		CurrentValue: f.Value.String(),
+		DefaultValue: f.DefValue,
+		IsDynamic:    dflag.IsFlagDynamic(f),
+		IsChanged:    dflag.IsChanged(f),
+	}
+	if dj, ok := f.Value.(dflag.DynamicJSONFlagValue); ok {
+		res.IsJson = dj.IsJSON()
+	}
+	if w, ok := f.Value.(dflag.DynamicFlagWatcher); ok {
+		res.Generation = w.Generation()
+	}
+	return res
+}
+
+func (s *Server) lookup(name string) (*flag.Flag, error) {
+	f := s.flagSet.Lookup(name)
+	if f == nil {
+		return nil, status.Errorf(codes.NotFound, "flag %q not found", name)
+	}
+	return f, nil
+}
+
+// List returns the flags matching the request's filters, in name order.
+func (s *Server) List(_ context.Context, req *dflagpb.ListRequest) (*dflagpb.ListResponse, error) {
+	res := &dflagpb.ListResponse{}
+	s.flagSet.VisitAll(func(f *flag.Flag) {
+		if !strings.HasPrefix(f.Name, req.GetPrefix()) {
+			return
+		}
+		pf := flagToProto(f)
+		if (req.GetDynamicOnly() && !pf.IsDynamic) || (req.GetChangedOnly() && !pf.IsChanged) {
+			return
+		}
+		res.Flags = append(res.Flags, pf)
+	})
+	return res, nil
+}
+
+// Get returns the named flag, NotFound if it doesn't exist.
+func (s *Server) Get(_ context.Context, req *dflagpb.GetRequest) (*dflagpb.Flag, error) {
+	f, err := s.lookup(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return flagToProto(f), nil
+}
+
+// Set changes the value of a dynamic flag, or only validates it when DryRun is true. Errors are NotFound,
+// FailedPrecondition for static flags (or when a dry run can't validate), PermissionDenied for flags
+// excluded by WithSetPredicate and InvalidArgument for values that don't parse or validate.
+func (s *Server) Set(_ context.Context, req *dflagpb.SetRequest) (*dflagpb.SetResponse, error) {
+	f, err := s.lookup(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if !dflag.IsFlagDynamic(f) {
+		return nil, status.Errorf(codes.FailedPrecondition, "flag %q is not dynamic", f.Name)
+	}
+	if s.setPredicate != nil && !s.setPredicate(f) {
+		return nil, status.Errorf(codes.PermissionDenied, "flag %q can't be changed through this service", f.Name)
+	}
+	if req.GetDryRun() {
+		err = dflag.ValidateFlag(f, req.GetValue())
+		if errors.Is(err, dflag.ErrNotValidatable) {
+			return nil, status.Errorf(codes.FailedPrecondition, "flag %q: %v", f.Name, err)
+		}
+	} else {
+		err = dflag.SetFlagFrom(s.flagSet, f.Name, req.GetValue(), Source)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "flag %q value %q: %v", f.Name, dflag.Redact(f, req.GetValue()), err)
+	}
+	if !req.GetDryRun() {
+		log.S(log.Info, "dflag set through grpc", log.Str("flag", f.Name), log.Str("value", dflag.Redact(f, req.GetValue())))
+	}
+	return &dflagpb.SetResponse{Flag: flagToProto(f)}, nil
+}
+
+// Watch streams the requested dynamic flags (all of them when none are named) each time they are set,
+// and first their current values when SendInitial is true, until the client cancels.
+func (s *Server) Watch(req *dflagpb.WatchRequest, stream dflagpb.Flags_WatchServer) error {
+	var flags []*flag.Flag
+	if len(req.GetNames()) == 0 {
+		s.flagSet.VisitAll(func(f *flag.Flag) {
+			if _, ok := f.Value.(dflag.DynamicFlagWatcher); ok {
+				flags = append(flags, f)
+			}
+		})
+	}
+	for _, name := range req.GetNames() {
+		f, err := s.lookup(name)
+		if err != nil {
+			return err
+		}
+		if _, ok := f.Value.(dflag.DynamicFlagWatcher); !ok {
+			return status.Errorf(codes.FailedPrecondition, "flag %q is not dynamic", name)
+		}
+		flags = append(flags, f)
+	}
+	ctx := stream.Context()
+	updates := make(chan *flag.Flag)
+	for _, f := range flags {
+		go watch(ctx, f, req.GetSendInitial(), updates)
+	}
+	for {
+		select {
+		case f := <-updates:
+			if err := stream.Send(flagToProto(f)); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// watch sends f on updates each time its generation changes, until ctx is done.
+func watch(ctx context.Context, f *flag.Flag, sendInitial bool, updates chan<- *flag.Flag) {
+	w := f.Value.(dflag.DynamicFlagWatcher)
+	last := w.Generation()
+	send := sendInitial
+	for {
+		changed := w.Changed()
+		if g := w.Generation(); g != last {
+			last, send = g, true
+		}
+		if send {
+			select {
+			case updates <- f:
+			case <-ctx.Done():
+				return
+			}
+			send = false
+			continue
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/grpc/dflagpb"
+	"fortio.org/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newClient(t *testing.T, s *Server) dflagpb.FlagsClient {
+	lis := bufconn.Listen(1 << 16)
+	gs := grpc.NewServer()
+	s.Register(gs)
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return dflagpb.NewFlagsClient(conn)
+}
+
+func TestListGetSet(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing").WithRange(1, 10).WithHistory(5)
+	dflag.DynString(set, "some_locked", "a", "dynamic string not settable")
+	set.Int("some_int", 1, "static int for testing")
+	client := newClient(t, NewServer(set).WithSetPredicate(func(f *flag.Flag) bool { return f.Name != "some_locked" }))
+	ctx := context.Background()
+
+	list, err := client.List(ctx, &dflagpb.ListRequest{DynamicOnly: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(list.GetFlags()))
+	assert.Equal(t, "some_dynint", list.GetFlags()[0].GetName())
+
+	res, err := client.Set(ctx, &dflagpb.SetRequest{Name: "some_dynint", Value: "5", DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", res.GetFlag().GetCurrentValue(), "dry run doesn't set")
+	res, err = client.Set(ctx, &dflagpb.SetRequest{Name: "some_dynint", Value: "5"})
+	assert.NoError(t, err)
+	assert.Equal(t, "5", res.GetFlag().GetCurrentValue())
+	assert.True(t, res.GetFlag().GetIsChanged())
+	assert.Equal(t, uint64(1), res.GetFlag().GetGeneration())
+	assert.Equal(t, int64(5), dynInt.Get())
+	assert.Equal(t, Source, dynInt.History()[0].Source)
+
+	for _, c := range []struct {
+		req  *dflagpb.SetRequest
+		code codes.Code
+	}{
+		{&dflagpb.SetRequest{Name: "some_dynint", Value: "11"}, codes.InvalidArgument},
+		{&dflagpb.SetRequest{Name: "some_int", Value: "2"}, codes.FailedPrecondition},
+		{&dflagpb.SetRequest{Name: "some_locked", Value: "b"}, codes.PermissionDenied},
+		{&dflagpb.SetRequest{Name: "nope", Value: "2"}, codes.NotFound},
+	} {
+		_, err = client.Set(ctx, c.req)
+		assert.Equal(t, c.code, status.Code(err), c.req.GetName())
+	}
+	f, err := client.Get(ctx, &dflagpb.GetRequest{Name: "some_int"})
+	assert.NoError(t, err)
+	assert.False(t, f.GetIsDynamic())
+	_, err = client.Get(ctx, &dflagpb.GetRequest{Name: "nope"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestWatch(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynInt64(set, "some_dynint", 1, "dynamic int for testing")
+	dflag.DynString(set, "some_dynstr", "a", "dynamic string for testing")
+	client := newClient(t, NewServer(set))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := client.Watch(ctx, &dflagpb.WatchRequest{Names: []string{"some_dynint"}, SendInitial: true})
+	assert.NoError(t, err)
+	f, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, "1", f.GetCurrentValue(), "initial value")
+	assert.NoError(t, set.Set("some_dynstr", "b")) // not watched.
+	assert.NoError(t, set.Set("some_dynint", "2"))
+	f, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, "some_dynint", f.GetName())
+	assert.Equal(t, "2", f.GetCurrentValue())
+	assert.Equal(t, uint64(1), f.GetGeneration())
+
+	stream, err = client.Watch(ctx, &dflagpb.WatchRequest{Names: []string{"nope"}})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestSensitive(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dflag.DynString(set, "api_key", "", "sensitive string for testing").Sensitive().
+		WithValidator(func(s string) error {
+			if strings.HasPrefix(s, "bad") {
+				return fmt.Errorf("invalid key %q", s)
+			}
+			return nil
+		})
+	client := newClient(t, NewServer(set))
+	ctx := context.Background()
+	res, err := client.Set(ctx, &dflagpb.SetRequest{Name: "api_key", Value: "secret-7351"})
+	assert.NoError(t, err)
+	assert.Equal(t, dflag.Redacted, res.GetFlag().GetCurrentValue())
+	assert.True(t, res.GetFlag().GetIsChanged(), "changed even though both values are redacted")
+	_, err = client.Set(ctx, &dflagpb.SetRequest{Name: "api_key", Value: "bad-8642"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.False(t, strings.Contains(err.Error(), "8642"), err.Error())
+	assert.True(t, strings.Contains(buf.String(), "api_key"), buf.String())
+	assert.False(t, strings.Contains(buf.String(), "7351"), buf.String())
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sync"
+	"time"
+
+	"fortio.org/sets"
+)
+
+// HistoryEntry is one of the values a dynamic flag was set to, see WithHistory.
+type HistoryEntry struct {
+	Time       time.Time `json:"time"`
+	Generation uint64    `json:"generation"`
+	Value      string    `json:"value"`
+	// Source is who or what set the value, when set through SetFlagFrom.
+	Source string `json:"source,omitempty"`
+}
+
+// DynamicFlagHistory is implemented by dynamic flags to return the values they were set to,
+// oldest first (nil unless enabled with WithHistory).
+type DynamicFlagHistory interface {
+	History() []HistoryEntry
+}
+
+// historySourcer is implemented by the dynamic flags to attribute their next set to a source.
+type historySourcer interface {
+	setNextSource(source string) (done func())
+}
+
+// valueHistory is a fixed size ring of the most recent entries.
+type valueHistory struct {
+	mu      sync.Mutex
+	entries []HistoryEntry
+	next    int // where the next entry goes once full.
+	size    int
+	// sourceMu serializes SetFlagFrom calls so each gets its own source.
+	sourceMu   sync.Mutex
+	nextSource string
+}
+
+func (h *valueHistory) add(entry HistoryEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	entry.Source, h.nextSource = h.nextSource, ""
+	if len(h.entries) < h.size {
+		h.entries = append(h.entries, entry)
+		return
+	}
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % h.size
+}
+
+func (h *valueHistory) setSource(source string) {
+	h.mu.Lock()
+	h.nextSource = source
+	h.mu.Unlock()
+}
+
+// WithHistory keeps the last size values the flag was set to, with when and (see SetFlagFrom) by whom,
+// e.g. for the endpoint's history page.
+func (d *DynValue[T]) WithHistory(size int) *DynValue[T] {
+	if size <= 0 {
+		d.history = nil
+		return d
+	}
+	d.history = &valueHistory{entries: make([]HistoryEntry, 0, size), size: size}
+	return d
+}
+
+// History returns the values recorded since WithHistory, oldest first.
+func (d *DynValue[T]) History() []HistoryEntry {
+	h := d.history
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	res := make([]HistoryEntry, 0, len(h.entries))
+	for i := range h.entries {
+		e := h.entries[(h.next+i)%len(h.entries)]
+		if d.sensitive {
+			e.Value = Redacted
+		}
+		res = append(res, e)
+	}
+	return res
+}
+
+func (d *DynValue[T]) setNextSource(source string) func() {
+	h := d.history
+	if h == nil {
+		return func() {}
+	}
+	h.sourceMu.Lock()
+	h.setSource(source)
+	return func() {
+		h.setSource("")
+		h.sourceMu.Unlock()
+	}
+}
+
+// recordHistory is called by SetV once the value is set.
+func (d *DynValue[T]) recordHistory(generation uint64, value T) {
+	if d.history == nil {
+		return
+	}
+	d.history.add(HistoryEntry{Time: time.Now(), Generation: generation, Value: historyValue(value)})
+}
+
+// historyValue is the value's flag string representation, JSON for the DynJSON values.
+func historyValue[T any](value T) string {
+	switch any(value).(type) {
+	case bool, int64, float64, time.Duration, string, []string, []byte, sets.Set[string]:
+		return valueString(value)
+	}
+	if b, err := json.Marshal(value); err == nil {
+		return string(b)
+	}
+	return valueString(value)
+}
+
+// SetFlagFrom sets the named flag like flagSet.Set does, recording source (e.g. "endpoint" or a user)
+// in the history of the flags using WithHistory. The attribution is best effort: a concurrent direct Set
+// of the same flag can get the source instead.
+func SetFlagFrom(flagSet *flag.FlagSet, name, value, source string) error {
+	f := flagSet.Lookup(name)
+	if f == nil {
+		return fmt.Errorf("flag %q not found", name)
+	}
+	if s, ok := f.Value.(historySourcer); ok {
+		done := s.setNextSource(source)
+		defer done()
+	}
+	return flagSet.Set(name, value)
+}
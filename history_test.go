@@ -0,0 +1,39 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"flag"
+	"testing"
+
+	"fortio.org/assert"
+)
+
+func TestHistory(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := DynInt64(set, "some_dynint", 5, "some dyn int").WithHistory(2)
+	dynJSON := DynJSON(set, "some_json", &outerJSON{FieldString: "x"}, "some json")
+	dynJSON.WithHistory(5)
+	noHistory := DynString(set, "some_string", "a", "no history")
+	var _ DynamicFlagHistory = dynJSON
+	assert.Equal(t, 0, len(dynInt.History()))
+	assert.NoError(t, set.Set("some_dynint", "6"))
+	assert.Error(t, set.Set("some_dynint", "x"))
+	assert.NoError(t, SetFlagFrom(set, "some_dynint", "7", "endpoint"))
+	assert.NoError(t, set.Set("some_dynint", "8"))
+	h := dynInt.History()
+	assert.Equal(t, 2, len(h), "only the last 2 values are kept")
+	assert.Equal(t, "7", h[0].Value)
+	assert.Equal(t, "endpoint", h[0].Source)
+	assert.Equal(t, uint64(2), h[0].Generation)
+	assert.Equal(t, "8", h[1].Value)
+	assert.Equal(t, "", h[1].Source)
+	assert.False(t, h[1].Time.Before(h[0].Time), "oldest first")
+	assert.NoError(t, SetFlagFrom(set, "some_json", `{"string": "y"}`, "test"))
+	assert.Equal(t, `{"ints":null,"string":"y","inner":null}`, dynJSON.History()[0].Value)
+	assert.NoError(t, SetFlagFrom(set, "some_string", "b", "test"))
+	assert.Equal(t, "b", noHistory.Get())
+	assert.True(t, noHistory.History() == nil, "history not enabled")
+	assert.Error(t, SetFlagFrom(set, "unknown", "b", "test"))
+}
@@ -0,0 +1,259 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Package httppoll periodically fetches a URL returning a JSON or YAML map of flag name to value
+// and updates the corresponding flags, enabling a simple centralized flag server (any static
+// file server will do) without new infrastructure. ETag and Last-Modified response headers are
+// used for conditional requests so unchanged documents aren't transferred nor re-applied.
+//
+// String values are used as is, other values (numbers, booleans, objects for DynJSON flags,...)
+// are used as their JSON encoding. e.g.
+//
+//	{"some_int": 42, "some_duration": "5s", "some_json": {"a": 1}}
+//
+// YAML documents are supported by also importing the fortio.org/dflag/httppoll/yaml module
+// (kept separate so this package doesn't depend on a YAML library), see RegisterYAML.
+//
+// It registers the http:// and https:// schemes with the source package (polling the URL
+// every DefaultInterval).
+package httppoll
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"fortio.org/dflag/source"
+	"fortio.org/log"
+)
+
+const (
+	// DefaultInterval is the polling interval when not specified in the Config.
+	DefaultInterval = 30 * time.Second
+	// DefaultMaxSize is the maximum document size when not specified in the Config.
+	DefaultMaxSize = 1 << 20
+)
+
+// yamlUnmarshal is set by RegisterYAML.
+var yamlUnmarshal func(data []byte, v interface{}) error
+
+// RegisterYAML sets the function used to decode YAML documents, e.g. gopkg.in/yaml.v3's Unmarshal
+// (which is what importing fortio.org/dflag/httppoll/yaml does).
+func RegisterYAML(unmarshal func(data []byte, v interface{}) error) {
+	yamlUnmarshal = unmarshal
+}
+
+// Config is the configuration of the HTTP poller. Only URL is required.
+type Config struct {
+	// URL of the flag values document.
+	URL string
+	// Interval between polls, defaults to DefaultInterval.
+	Interval time.Duration
+	// Header to add to each request (e.g. Authorization).
+	Header http.Header
+	// YAML forces parsing the document as YAML; otherwise YAML is used when the Content-Type
+	// mentions yaml or the URL ends in .yaml/.yml, and JSON otherwise.
+	YAML bool
+	// Client to use instead of http.DefaultClient.
+	Client *http.Client
+	// MaxSize is the maximum size of the document, larger ones are errors. Defaults to DefaultMaxSize.
+	MaxSize int64
+}
+
+// Poller is the HTTP flag values poller.
+type Poller struct {
+	cfg Config
+	mu  sync.Mutex
+	// conditional request headers from the last successful fetch.
+	etag         string
+	lastModified string
+	cancel       context.CancelFunc
+	done         chan struct{}
+}
+
+func init() {
+	source.Register("http", FromURL)
+	source.Register("https", FromURL)
+}
+
+// FromURL creates a poller of the URL with the default Config.
+func FromURL(u *url.URL) (source.Source, error) {
+	return New(Config{URL: u.String()})
+}
+
+// New creates a poller.
+func New(cfg Config) (*Poller, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("dflag: httppoll URL is required")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = DefaultMaxSize
+	}
+	return &Poller{cfg: cfg}, nil
+}
+
+// Setup is a combination/shortcut for New+source.Apply: the document is fetched, setting both
+// static and dynamic flags, then polled for updates of dynamic flags.
+func Setup(ctx context.Context, flagSet *flag.FlagSet, cfg Config) (*source.Applier, error) {
+	p, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return source.Apply(ctx, flagSet, p)
+}
+
+// Initialize fetches the document for the first time.
+func (p *Poller) Initialize(ctx context.Context) (map[string][]byte, error) {
+	values, _, err := p.fetch(ctx)
+	return values, err
+}
+
+// Watch kicks off the polling go routine. It stops when the context is done or Stop() is called.
+func (p *Poller) Watch(ctx context.Context, updates chan<- source.Update) error {
+	if p.done != nil {
+		return errors.New("dflag: httppoll already started")
+	}
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.done = make(chan struct{})
+	go p.pollLoop(ctx, updates)
+	return nil
+}
+
+// Stop stops the polling go routine and waits for it to exit.
+func (p *Poller) Stop() {
+	if p.done == nil {
+		return
+	}
+	p.cancel()
+	<-p.done
+	p.done = nil
+}
+
+func (p *Poller) pollLoop(ctx context.Context, updates chan<- source.Update) {
+	defer close(p.done)
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			values, modified, err := p.fetch(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Errf("dflag: %v", err)
+				}
+				continue
+			}
+			if modified {
+				source.Send(ctx, updates, values)
+			}
+		}
+	}
+}
+
+// fetch gets and parses the document, unless it wasn't modified since the last fetch.
+func (p *Poller) fetch(ctx context.Context) (map[string][]byte, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.URL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	for k, v := range p.cfg.Header {
+		req.Header[k] = v
+	}
+	p.mu.Lock()
+	if p.etag != "" {
+		req.Header.Set("If-None-Match", p.etag)
+	}
+	if p.lastModified != "" {
+		req.Header.Set("If-Modified-Since", p.lastModified)
+	}
+	p.mu.Unlock()
+	resp, err := p.cfg.Client.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		log.LogVf("dflag: httppoll %v not modified", p.cfg.URL)
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("dflag: httppoll GET %v: %v", p.cfg.URL, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, p.cfg.MaxSize+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if int64(len(data)) > p.cfg.MaxSize {
+		return nil, false, fmt.Errorf("dflag: httppoll %v: document larger than the %d bytes maximum", p.cfg.URL, p.cfg.MaxSize)
+	}
+	values, err := ParseValues(data, p.isYAML(resp.Header.Get("Content-Type")))
+	if err != nil {
+		return nil, false, fmt.Errorf("dflag: httppoll %v: %w", p.cfg.URL, err)
+	}
+	p.mu.Lock()
+	p.etag = resp.Header.Get("ETag")
+	p.lastModified = resp.Header.Get("Last-Modified")
+	p.mu.Unlock()
+	return values, true, nil
+}
+
+func (p *Poller) isYAML(contentType string) bool {
+	if p.cfg.YAML || strings.Contains(contentType, "yaml") {
+		return true
+	}
+	u := strings.SplitN(p.cfg.URL, "?", 2)[0]
+	return strings.HasSuffix(u, ".yaml") || strings.HasSuffix(u, ".yml")
+}
+
+// ParseValues decodes a JSON (or YAML when isYAML is true) map document into flag name
+// to flag value (input string) map. String values are used as is and other values as their JSON encoding.
+func ParseValues(data []byte, isYAML bool) (map[string][]byte, error) {
+	var raw map[string]interface{}
+	if isYAML {
+		if yamlUnmarshal == nil {
+			return nil, errors.New("YAML support not enabled, import fortio.org/dflag/httppoll/yaml")
+		}
+		if err := yamlUnmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber() // keep large integers as is.
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+	}
+	res := make(map[string][]byte, len(raw))
+	for name, v := range raw {
+		switch v := v.(type) {
+		case string:
+			res[name] = []byte(v)
+		case nil:
+			res[name] = []byte{}
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("flag %v: %w", name, err)
+			}
+			res[name] = b
+		}
+	}
+	return res, nil
+}
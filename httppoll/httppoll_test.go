@@ -0,0 +1,103 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package httppoll
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/source"
+)
+
+type jsonCfg struct {
+	A int `json:"a"`
+}
+
+func TestHTTPPoller(t *testing.T) {
+	var doc atomic.Value
+	var fetches, notModified atomic.Int32
+	doc.Store(`{"some_dynint": 5, "some_int": 6, "some_json": {"a": 1}, "unknown": "x"}`)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer tok", r.Header.Get("Authorization"))
+		cur := doc.Load().(string)
+		etag := `"` + cur + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fetches.Add(1)
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(cur))
+	}))
+	defer srv.Close()
+	fs := flag.NewFlagSet("httppoll_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	staticInt := fs.Int("some_int", 1, "static int for testing")
+	dynJSON := dflag.DynJSON(fs, "some_json", &jsonCfg{}, "dynamic json for testing")
+	_, err := New(Config{})
+	assert.Error(t, err, "URL is required")
+	cfg := Config{URL: srv.URL, Interval: 20 * time.Millisecond, Header: http.Header{"Authorization": {"Bearer tok"}}}
+	p, err := Setup(context.Background(), fs, cfg)
+	assert.NoError(t, err)
+	defer p.Stop()
+	assert.Equal(t, int64(5), dynInt.Get())
+	assert.Equal(t, 6, *staticInt)
+	assert.Equal(t, 1, dynJSON.Get().(*jsonCfg).A)
+	for i := 0; i < 100 && notModified.Load() < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int32(1), fetches.Load(), "unchanged document shouldn't be re-fetched")
+	doc.Store(`{"some_dynint": 7, "some_int": 99, "some_json": {"a": 1}}`)
+	for i := 0; i < 100 && dynInt.Get() != 7; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int64(7), dynInt.Get())
+	assert.Equal(t, 6, *staticInt, "static flags aren't changed by polling")
+}
+
+func TestIsYAML(t *testing.T) {
+	p, err := New(Config{URL: "http://example.com/flags.yaml"})
+	assert.NoError(t, err)
+	assert.True(t, p.isYAML("text/plain"))
+	p.cfg.URL = "http://example.com/flags"
+	assert.False(t, p.isYAML("application/json"))
+	assert.True(t, p.isYAML("application/yaml"))
+	_, err = ParseValues([]byte("some_int: 42\n"), false)
+	assert.Error(t, err, "should be parsed as JSON")
+	_, err = ParseValues([]byte("some_int: 42\n"), true)
+	assert.Error(t, err, "YAML support not registered")
+}
+
+func TestMaxSize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"some_str": "` + strings.Repeat("x", 100) + `"}`))
+	}))
+	defer srv.Close()
+	fs := flag.NewFlagSet("httppoll_test", flag.ContinueOnError)
+	dynStr := dflag.DynString(fs, "some_str", "", "dynamic string for testing")
+	_, err := Setup(context.Background(), fs, Config{URL: srv.URL, MaxSize: 100})
+	assert.Error(t, err, "document over the maximum size")
+	assert.Equal(t, "", dynStr.Get())
+	p, err := Setup(context.Background(), fs, Config{URL: srv.URL, MaxSize: 200})
+	assert.NoError(t, err)
+	defer p.Stop()
+	assert.Equal(t, 100, len(dynStr.Get()))
+}
+
+func TestFromURL(t *testing.T) {
+	s, err := source.Open("https://example.com/flags.json")
+	assert.NoError(t, err)
+	p := s.(*Poller)
+	assert.Equal(t, "https://example.com/flags.json", p.cfg.URL)
+	assert.Equal(t, DefaultInterval, p.cfg.Interval)
+}
@@ -0,0 +1,21 @@
+module fortio.org/dflag/httppoll/yaml
+
+go 1.20
+
+require (
+	fortio.org/assert v1.2.1
+	fortio.org/dflag v1.9.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	fortio.org/log v1.17.1 // indirect
+	fortio.org/sets v1.2.0 // indirect
+	fortio.org/struct2env v0.4.1 // indirect
+	github.com/kortschak/goroutine v1.1.2 // indirect
+	golang.org/x/exp v0.0.0-20240604190554-fc45aab8b7f8 // indirect
+)
+
+// Build against the dflag module of this repository (the required v1.9.0 must be tagged, see
+// Releasing in the README, before this module is).
+replace fortio.org/dflag => ../..
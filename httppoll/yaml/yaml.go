@@ -0,0 +1,21 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Package yaml adds YAML documents support to the httppoll source and the endpoint's import, and YAML
+// values support to the DynJSON flags (see dflag.RegisterYAML), using gopkg.in/yaml.v3:
+//
+//	import _ "fortio.org/dflag/httppoll/yaml"
+//
+// It's a separate module so httppoll users not needing YAML don't depend on a YAML library.
+package yaml
+
+import (
+	"fortio.org/dflag"
+	"fortio.org/dflag/httppoll"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+func init() {
+	httppoll.RegisterYAML(yamlv3.Unmarshal)
+	dflag.RegisterYAML(yamlv3.Unmarshal)
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package yaml
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/endpoint"
+	"fortio.org/dflag/httppoll"
+)
+
+func TestParseYAML(t *testing.T) {
+	values, err := httppoll.ParseValues([]byte("some_int: 42\nsome_bool: true\nsome_str: 5s\nsome_json:\n  a: 1\n"), true)
+	assert.NoError(t, err)
+	assert.Equal(t, "42", string(values["some_int"]))
+	assert.Equal(t, "true", string(values["some_bool"]))
+	assert.Equal(t, "5s", string(values["some_str"]))
+	assert.Equal(t, `{"a":1}`, string(values["some_json"]))
+	_, err = httppoll.ParseValues([]byte("[not a map"), true)
+	assert.Error(t, err)
+}
+
+func TestYAMLPoller(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write([]byte("some_dynint: 5\n"))
+	}))
+	defer srv.Close()
+	fs := flag.NewFlagSet("yaml_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	p, err := httppoll.Setup(context.Background(), fs, httppoll.Config{URL: srv.URL})
+	assert.NoError(t, err)
+	defer p.Stop()
+	assert.Equal(t, int64(5), dynInt.Get())
+}
+
+func TestImportConfigMapYAML(t *testing.T) {
+	fs := flag.NewFlagSet("yaml_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	e := endpoint.NewFlagsEndpoint(fs, "/set")
+	req := httptest.NewRequest(http.MethodPost, "/debug/flags/import", strings.NewReader(
+		"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: dflag\ndata:\n  some_dynint: \"42\"\n"))
+	req.Header.Set("Content-Type", "application/yaml")
+	resp := httptest.NewRecorder()
+	e.ImportFlags(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, int64(42), dynInt.Get())
+}
+
+type limits struct {
+	Max   int      `json:"max"`
+	Hosts []string `json:"hosts"`
+}
+
+func TestDynJSONYAML(t *testing.T) {
+	fs := flag.NewFlagSet("yaml_test", flag.ContinueOnError)
+	dynJSON := dflag.DynJSON(fs, "some_json", &limits{}, "dynamic json for testing")
+	assert.NoError(t, fs.Set("some_json", "max: 10\nhosts:\n  - a\n  - b\n"))
+	assert.Equal(t, &limits{Max: 10, Hosts: []string{"a", "b"}}, dynJSON.Get())
+	assert.Equal(t, `{"max":10,"hosts":["a","b"]}`, dynJSON.String())
+	assert.NoError(t, fs.Set("some_json", `{"max": 3}`), "JSON still works")
+	assert.Equal(t, &limits{Max: 3}, dynJSON.Get())
+	err := fs.Set("some_json", "max: ten\n")
+	assert.Error(t, err, "YAML of the wrong type")
+	assert.Contains(t, err.Error(), "cannot unmarshal string")
+	err = fs.Set("some_json", `{"max": 3`)
+	assert.Error(t, err, "truncated JSON")
+	assert.Contains(t, err.Error(), "unexpected end of JSON input")
+	typed := dflag.DynTypedJSON(fs, "some_typed", &limits{}, "dynamic typed json for testing")
+	assert.NoError(t, fs.Set("some_typed", "{max: 7, hosts: [c]}"))
+	assert.Equal(t, &limits{Max: 7, Hosts: []string{"c"}}, typed.Load())
+	assert.Equal(t, &limits{Max: 3}, dynJSON.Get())
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrDuplicateFlag is returned (wrapped) by Bind when a name is used by more than one pending value
+// or is already defined in the FlagSet.
+var ErrDuplicateFlag = errors.New("dflag: duplicate flag")
+
+// pendingFlag is a value waiting for Bind.
+type pendingFlag struct {
+	name string
+	pkg  string // package which called BindLater.
+	bind func(flagSet *flag.FlagSet)
+}
+
+var (
+	pendingMu sync.Mutex
+	pending   []pendingFlag
+)
+
+// BindLater records the value to be bound to the flag name by the next Bind, for libraries to
+// declare their flags at init time without picking the FlagSet nor racing other packages' init:
+//
+//	var Timeout = dflag.New(5*time.Second, "timeout of the foo calls").BindLater("foo-timeout")
+//
+// and in main:
+//
+//	if err := dflag.Bind(flag.CommandLine); err != nil { ... }
+func (d *DynValue[T]) BindLater(name string) *DynValue[T] {
+	p := pendingFlag{name: name, pkg: callerPackage(), bind: func(flagSet *flag.FlagSet) {
+		d.flagSet = flagSet
+		d.flagName = name
+		flagSet.Var(d, name, d.usage)
+		flagSet.Lookup(name).DefValue = d.String()
+	}}
+	pendingMu.Lock()
+	pending = append(pending, p)
+	pendingMu.Unlock()
+	return d
+}
+
+// Bind binds all the values recorded by BindLater (since the previous Bind) to flagSet, all or none:
+// if a name is used twice or already defined in flagSet, the error (wrapping ErrDuplicateFlag) names
+// the packages involved and the values are left pending.
+func Bind(flagSet *flag.FlagSet) error {
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+	sort.SliceStable(pending, func(i, j int) bool { return pending[i].name < pending[j].name })
+	var collisions []string
+	for i, p := range pending {
+		if i > 0 && pending[i-1].name == p.name {
+			collisions = append(collisions, fmt.Sprintf("%q from %s and %s", p.name, pending[i-1].pkg, p.pkg))
+		} else if flagSet.Lookup(p.name) != nil {
+			collisions = append(collisions, fmt.Sprintf("%q from %s is already defined in %s", p.name, p.pkg, flagSet.Name()))
+		}
+	}
+	if len(collisions) > 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicateFlag, strings.Join(collisions, ", "))
+	}
+	for _, p := range pending {
+		p.bind(flagSet)
+	}
+	pending = nil
+	return nil
+}
+
+// callerPackage returns the import path of the package calling the caller of callerPackage.
+func callerPackage() string {
+	pc, _, _, ok := runtime.Caller(2)
+	if !ok {
+		return "unknown package"
+	}
+	name := runtime.FuncForPC(pc).Name() // e.g. fortio.org/dflag/foo.init or fortio.org/dflag/foo.(*T).Method
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
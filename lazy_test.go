@@ -0,0 +1,57 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"errors"
+	"flag"
+	"sync"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+)
+
+func TestBind(t *testing.T) {
+	var wg sync.WaitGroup
+	values := make([]*DynValue[int64], 10)
+	for i := range values {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			values[i] = New(int64(i), "lazy int").BindLater("lazy_" + string(rune('a'+i)))
+		}(i)
+	}
+	wg.Wait()
+	dur := New(time.Second, "lazy duration").WithValidator(ValidateRange(time.Millisecond, time.Minute)).BindLater("lazy_dur")
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	assert.NoError(t, Bind(set))
+	for i, v := range values {
+		f := set.Lookup("lazy_" + string(rune('a'+i)))
+		assert.True(t, f != nil && f.Value == v, "bound")
+		assert.Equal(t, v.String(), f.DefValue)
+	}
+	assert.NoError(t, set.Set("lazy_c", "42"))
+	assert.Equal(t, int64(42), values[2].Get())
+	assert.Error(t, set.Set("lazy_dur", "1h"), "validator kept")
+	assert.Equal(t, time.Second, dur.Get())
+	assert.NoError(t, Bind(set), "nothing pending")
+}
+
+func TestBindDuplicates(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	set.Int("taken", 1, "")
+	New("x", "").BindLater("dup")
+	New("y", "").BindLater("dup")
+	New(true, "").BindLater("taken")
+	New(1.5, "").BindLater("other")
+	err := Bind(set)
+	assert.True(t, errors.Is(err, ErrDuplicateFlag))
+	assert.Contains(t, err.Error(), `"dup" from fortio.org/dflag and fortio.org/dflag`)
+	assert.Contains(t, err.Error(), `"taken" from fortio.org/dflag is already defined in foobar`)
+	assert.True(t, set.Lookup("other") == nil, "all or nothing")
+	pendingMu.Lock()
+	pending = nil // don't leak to the other tests.
+	pendingMu.Unlock()
+}
@@ -0,0 +1,14 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+// Logger is the minimal structured logger the configmap Updater and the endpoint can log to instead of
+// fortio.org/log (see their WithLogger). The args are alternating keys and values, like log/slog's:
+// a *slog.Logger satisfies it as is.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"flag"
+	"time"
+)
+
+// Metrics receives the outcome of each set of the dynamic flags and how long their notifiers take, so any
+// metrics system can be bridged without dflag depending on it (see WithMetrics and SetMetrics).
+// The calls are made from the setting (or, for the asynchronous notifiers, notifying) go routine.
+type Metrics interface {
+	IncSetSuccess(name string)
+	// IncSetError is called when a set fails, e.g. the input doesn't parse or the value isn't valid.
+	IncSetError(name string)
+	ObserveNotifyLatency(name string, d time.Duration)
+}
+
+// NoMetrics is the no-op Metrics, the default. It can be embedded to only implement some of the methods.
+type NoMetrics struct{}
+
+func (NoMetrics) IncSetSuccess(string)                       {}
+func (NoMetrics) IncSetError(string)                         {}
+func (NoMetrics) ObserveNotifyLatency(string, time.Duration) {}
+
+// metricsSetter is implemented by the dynamic flags, see SetMetrics.
+type metricsSetter interface {
+	setMetrics(m Metrics)
+}
+
+// WithMetrics reports the sets and the notifier latency of the flag to m (nil to stop reporting).
+// The name reported is the flag's name, empty until bound to a flag.
+func (d *DynValue[T]) WithMetrics(m Metrics) *DynValue[T] {
+	d.setMetrics(m)
+	return d
+}
+
+func (d *DynValue[T]) setMetrics(m Metrics) {
+	if _, noop := m.(NoMetrics); noop {
+		m = nil
+	}
+	d.metrics = m
+}
+
+// SetMetrics makes all the dynamic flags defined so far in flagSet report to m, like WithMetrics.
+func SetMetrics(flagSet *flag.FlagSet, m Metrics) {
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if s, ok := f.Value.(metricsSetter); ok {
+			s.setMetrics(m)
+		}
+	})
+}
+
+// setError reports and returns the error of a set.
+func (d *DynValue[T]) setError(err error) error {
+	if d.metrics != nil {
+		d.metrics.IncSetError(d.flagName)
+	}
+	return err
+}
+
+// notify calls the notifier, timing it when reporting metrics.
+func (d *DynValue[T]) notify(oldValue, newValue T) {
+	if d.metrics == nil {
+		d.notifier(oldValue, newValue)
+		return
+	}
+	start := time.Now()
+	d.notifier(oldValue, newValue)
+	d.metrics.ObserveNotifyLatency(d.flagName, time.Since(start))
+}
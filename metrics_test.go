@@ -0,0 +1,75 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"errors"
+	"flag"
+	"sync"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+)
+
+type recordedMetrics struct {
+	mu        sync.Mutex
+	successes map[string]int
+	errors    map[string]int
+	notifies  chan string
+}
+
+func newRecordedMetrics() *recordedMetrics {
+	return &recordedMetrics{successes: map[string]int{}, errors: map[string]int{}, notifies: make(chan string, 10)}
+}
+
+func (m *recordedMetrics) IncSetSuccess(name string) {
+	m.mu.Lock()
+	m.successes[name]++
+	m.mu.Unlock()
+}
+
+func (m *recordedMetrics) IncSetError(name string) {
+	m.mu.Lock()
+	m.errors[name]++
+	m.mu.Unlock()
+}
+
+func (m *recordedMetrics) ObserveNotifyLatency(name string, _ time.Duration) {
+	m.notifies <- name
+}
+
+func TestMetrics(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := DynInt64(set, "some_int", 1, "").WithValidator(ValidateDynInt64Range(0, 10)).
+		WithNotifier(func(_, _ int64) {})
+	type cfg struct{ A int }
+	DynJSON(set, "some_json", &cfg{}, "")
+	set.Int("lib_int", 1, "")
+	_, err := Wrap(set, "lib_int")
+	assert.NoError(t, err)
+	set.Int("static_int", 1, "")
+	m := newRecordedMetrics()
+	SetMetrics(set, m)
+	assert.NoError(t, set.Set("some_int", "5"))
+	assert.Equal(t, "some_int", <-m.notifies, "async notifier latency")
+	assert.Error(t, set.Set("some_int", "x"))
+	assert.Error(t, set.Set("some_int", "11"))
+	assert.Error(t, set.Set("some_json", `{"A": "x"}`))
+	assert.NoError(t, set.Set("some_json", `{"A": 2}`))
+	assert.Error(t, set.Set("lib_int", "x"))
+	assert.NoError(t, set.Set("lib_int", "2"))
+	assert.NoError(t, set.Set("static_int", "2"))
+	assert.Equal(t, map[string]int{"some_int": 1, "some_json": 1, "lib_int": 1}, m.successes)
+	assert.Equal(t, map[string]int{"some_int": 2, "some_json": 1, "lib_int": 1}, m.errors)
+	assert.Error(t, dynInt.ValidateInput("x"))
+	assert.Equal(t, 2, m.errors["some_int"], "validation isn't a set")
+	dynInt.WithMetrics(NoMetrics{})
+	assert.Error(t, dynInt.SetV(-1))
+	assert.Equal(t, 2, m.errors["some_int"], "no longer reported")
+	fn := DynFunc(set, "some_func", "a", "", func(string) error { return errors.New("failed") })
+	fn.WithMetrics(m)
+	assert.Error(t, fn.Set("b"))
+	assert.Equal(t, 1, m.errors["some_func"])
+}
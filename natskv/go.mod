@@ -0,0 +1,31 @@
+module fortio.org/dflag/natskv
+
+go 1.24.0
+
+require (
+	fortio.org/assert v1.2.1
+	fortio.org/dflag v1.9.0
+	fortio.org/log v1.17.1
+	github.com/nats-io/nats-server/v2 v2.11.8
+	github.com/nats-io/nats.go v1.45.0
+)
+
+require (
+	fortio.org/sets v1.2.0 // indirect
+	fortio.org/struct2env v0.4.1 // indirect
+	github.com/google/go-tpm v0.9.5 // indirect
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/kortschak/goroutine v1.1.2 // indirect
+	github.com/minio/highwayhash v1.0.3 // indirect
+	github.com/nats-io/jwt/v2 v2.7.4 // indirect
+	github.com/nats-io/nkeys v0.4.11 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	golang.org/x/crypto v0.41.0 // indirect
+	golang.org/x/exp v0.0.0-20240604190554-fc45aab8b7f8 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/time v0.12.0 // indirect
+)
+
+// Build against the dflag module of this repository (the required v1.9.0 must be tagged, see
+// Releasing in the README, before this module is).
+replace fortio.org/dflag => ../
@@ -0,0 +1,262 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Package natskv watches a NATS JetStream key-value bucket and updates the flags whose names
+// are the bucket's keys, leveraging existing NATS infrastructure for near-instant propagation
+// of flag changes across services, e.g.
+//
+//	nats kv put dflag some_flag 42
+//
+// It uses the official nats.go client's KeyValue watcher (its own module so the main dflag module
+// doesn't depend on it), which delivers the last value of each key then every update, and resumes
+// where it was after reconnections. Deleting a key doesn't change the flag. It registers the nats://
+// scheme with the source package, see FromURL.
+package natskv
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"fortio.org/dflag/source"
+	"fortio.org/log"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// DefaultURL is the NATS server used when not specified in the Config.
+const DefaultURL = nats.DefaultURL
+
+// Config is the configuration of the NATS KV source. Only Bucket is required.
+type Config struct {
+	// Conn is an existing connection to use (then the following connection settings are ignored and
+	// Stop() doesn't close it), e.g. shared with the application. A new one is made otherwise.
+	Conn *nats.Conn
+	// URL of the NATS server(s, comma separated), defaults to DefaultURL. User and password can be part of the URL.
+	URL string
+	// Bucket is the key-value bucket name.
+	Bucket string
+	// Token, or User and Password, for authentication if needed.
+	Token    string
+	User     string
+	Password string
+	// TLS configuration, if TLS is to be used.
+	TLS *tls.Config
+	// Timeout for connecting and the initial values, defaults to 5s.
+	Timeout time.Duration
+}
+
+// Source is the NATS KV flag values source.
+type Source struct {
+	cfg     Config
+	mu      sync.Mutex
+	nc      *nats.Conn // connection made by the source (nil when using Config.Conn).
+	watcher jetstream.KeyWatcher
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func init() {
+	source.Register("nats", FromURL)
+}
+
+// FromURL creates a source from a nats://[user:password@|token@]host[:port]/bucket URL.
+func FromURL(u *url.URL) (source.Source, error) {
+	cfg := Config{Bucket: strings.Trim(u.Path, "/")}
+	if u.User != nil {
+		if password, isSet := u.User.Password(); isSet {
+			cfg.User, cfg.Password = u.User.Username(), password
+		} else {
+			cfg.Token = u.User.Username()
+		}
+	}
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	cfg.URL = "nats://" + u.Host
+	return New(cfg)
+}
+
+// New creates a NATS KV source. It doesn't connect yet, Initialize does.
+func New(cfg Config) (*Source, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("dflag: natskv Bucket is required")
+	}
+	if cfg.URL == "" {
+		cfg.URL = DefaultURL
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	return &Source{cfg: cfg}, nil
+}
+
+// Setup is a combination/shortcut for New+source.Apply: the current values of the bucket are
+// read, setting both static and dynamic flags, then the updates are applied to dynamic flags.
+func Setup(ctx context.Context, flagSet *flag.FlagSet, cfg Config) (*source.Applier, error) {
+	s, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return source.Apply(ctx, flagSet, s)
+}
+
+// connect returns the connection to use, making it on first use.
+func (s *Source) connect() (*nats.Conn, error) {
+	if s.cfg.Conn != nil {
+		return s.cfg.Conn, nil
+	}
+	if s.nc != nil {
+		return s.nc, nil
+	}
+	opts := []nats.Option{
+		nats.Name("dflag"),
+		nats.Timeout(s.cfg.Timeout),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				log.Warnf("dflag: natskv watch of %v disconnected: %v", s.cfg.Bucket, err)
+			}
+		}),
+	}
+	switch {
+	case s.cfg.Token != "":
+		opts = append(opts, nats.Token(s.cfg.Token))
+	case s.cfg.User != "":
+		opts = append(opts, nats.UserInfo(s.cfg.User, s.cfg.Password))
+	}
+	if s.cfg.TLS != nil {
+		opts = append(opts, nats.Secure(s.cfg.TLS))
+	}
+	nc, err := nats.Connect(s.cfg.URL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("dflag: natskv connect: %w", err)
+	}
+	s.nc = nc
+	return nc, nil
+}
+
+// Initialize reads the current values of the bucket, starting the watcher whose updates Watch sends.
+func (s *Source) Initialize(ctx context.Context) (map[string][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watcher != nil {
+		_ = s.watcher.Stop()
+		s.watcher = nil
+	}
+	nc, err := s.connect()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, err
+	}
+	kv, err := js.KeyValue(ctx, s.cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("dflag: natskv bucket %v: %w", s.cfg.Bucket, err)
+	}
+	// not ctx: the watcher is stopped when the context it's created with is done.
+	w, err := kv.WatchAll(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("dflag: natskv watch of %v: %w", s.cfg.Bucket, err)
+	}
+	values := make(map[string][]byte)
+	for {
+		select {
+		case e, ok := <-w.Updates():
+			if !ok {
+				return nil, fmt.Errorf("dflag: natskv watch of %v closed", s.cfg.Bucket)
+			}
+			if e == nil { // all the current values were received.
+				s.watcher = w
+				return values, nil
+			}
+			if u := update(e); !u.Deleted {
+				values[u.Name] = u.Value
+			}
+		case <-ctx.Done():
+			_ = w.Stop()
+			return nil, fmt.Errorf("dflag: natskv initial values of %v: %w", s.cfg.Bucket, ctx.Err())
+		}
+	}
+}
+
+// Watch kicks off the go routine sending updates. It stops when the context is done or Stop() is called.
+func (s *Source) Watch(ctx context.Context, updates chan<- source.Update) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		return errors.New("dflag: natskv already started")
+	}
+	if s.watcher == nil {
+		return errors.New("dflag: natskv Watch called before Initialize")
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	go s.watchLoop(ctx, s.watcher, s.done, updates)
+	return nil
+}
+
+// Stop stops the watching go routine, waits for it to exit and closes the connection the source made.
+func (s *Source) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		s.cancel()
+		<-s.done
+		s.done = nil
+	}
+	if s.watcher != nil {
+		_ = s.watcher.Stop()
+		s.watcher = nil
+	}
+	if s.nc != nil {
+		s.nc.Close()
+		s.nc = nil
+	}
+}
+
+// watchLoop sends the watcher's updates until the context is done (or the watcher is closed);
+// it closes done when exiting.
+func (s *Source) watchLoop(ctx context.Context, w jetstream.KeyWatcher, done chan struct{}, updates chan<- source.Update) {
+	defer close(done)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-w.Updates():
+			if !ok {
+				log.Warnf("dflag: natskv watch of %v closed", s.cfg.Bucket)
+				return
+			}
+			if e == nil {
+				continue
+			}
+			select {
+			case updates <- update(e):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// update converts the key-value entry to an Update.
+func update(e jetstream.KeyValueEntry) source.Update {
+	op := e.Operation()
+	return source.Update{
+		Name:    e.Key(),
+		Value:   e.Value(),
+		Deleted: op == jetstream.KeyValueDelete || op == jetstream.KeyValuePurge,
+	}
+}
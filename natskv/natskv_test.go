@@ -0,0 +1,167 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package natskv
+
+import (
+	"context"
+	"flag"
+	"net"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/source"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// newServer starts an in process JetStream enabled NATS server, stopped when the test ends.
+func newServer(t *testing.T) *server.Server {
+	return startServer(t, -1, t.TempDir())
+}
+
+func startServer(t *testing.T, port int, storeDir string) *server.Server {
+	srv, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: port, JetStream: true, StoreDir: storeDir})
+	assert.NoError(t, err)
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	t.Cleanup(srv.Shutdown)
+	return srv
+}
+
+// newBucket creates the "dflag" bucket with the given values.
+func newBucket(t *testing.T, srv *server.Server, values map[string]string) jetstream.KeyValue {
+	nc, err := nats.Connect(srv.ClientURL())
+	assert.NoError(t, err)
+	t.Cleanup(nc.Close)
+	js, err := jetstream.New(nc)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	kv, err := js.CreateKeyValue(ctx, jetstream.KeyValueConfig{Bucket: "dflag"})
+	assert.NoError(t, err)
+	for k, v := range values {
+		_, err = kv.PutString(ctx, k, v)
+		assert.NoError(t, err)
+	}
+	return kv
+}
+
+func waitFor(cond func() bool) {
+	for i := 0; i < 200 && !cond(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNATSKVSource(t *testing.T) {
+	srv := newServer(t)
+	kv := newBucket(t, srv, map[string]string{"some_dynint": "5", "some_int": "6", "unknown": "x"})
+	ctx := context.Background()
+	fs := flag.NewFlagSet("natskv_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	dynStr := dflag.DynString(fs, "some_dynstr", "a", "dynamic string for testing")
+	staticInt := fs.Int("some_int", 1, "static int for testing")
+	_, err := New(Config{})
+	assert.Error(t, err, "bucket is required")
+	s, err := Setup(ctx, fs, Config{URL: srv.ClientURL(), Bucket: "dflag"})
+	assert.NoError(t, err)
+	defer s.Stop()
+	assert.Equal(t, int64(5), dynInt.Get())
+	assert.Equal(t, 6, *staticInt)
+	_, err = kv.PutString(ctx, "some_dynint", "7")
+	assert.NoError(t, err)
+	_, err = kv.PutString(ctx, "some_int", "99")
+	assert.NoError(t, err)
+	waitFor(func() bool { return dynInt.Get() == 7 })
+	assert.Equal(t, int64(7), dynInt.Get())
+	assert.Equal(t, 6, *staticInt, "static flags aren't changed by updates")
+	assert.NoError(t, kv.Delete(ctx, "some_dynint"))
+	_, err = kv.PutString(ctx, "some_dynstr", "b")
+	assert.NoError(t, err)
+	waitFor(func() bool { return dynStr.Get() == "b" })
+	assert.Equal(t, "b", dynStr.Get())
+	assert.Equal(t, int64(7), dynInt.Get(), "deleted key doesn't change the flag")
+}
+
+func TestNATSKVServerRestart(t *testing.T) {
+	storeDir := t.TempDir()
+	srv := startServer(t, -1, storeDir)
+	newBucket(t, srv, map[string]string{"some_dynint": "5"})
+	nc, err := nats.Connect(srv.ClientURL(), nats.MaxReconnects(-1), nats.ReconnectWait(20*time.Millisecond))
+	assert.NoError(t, err)
+	defer nc.Close()
+	fs := flag.NewFlagSet("natskv_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	s, err := Setup(context.Background(), fs, Config{Conn: nc, Bucket: "dflag"})
+	assert.NoError(t, err)
+	defer s.Stop()
+	assert.Equal(t, int64(5), dynInt.Get())
+	port := srv.Addr().(*net.TCPAddr).Port
+	srv.Shutdown()
+	srv.WaitForShutdown()
+	srv = startServer(t, port, storeDir)
+	// updates made while the watcher is disconnected are caught up on reconnection.
+	kv := newBucket(t, srv, nil)
+	_, err = kv.PutString(context.Background(), "some_dynint", "8")
+	assert.NoError(t, err)
+	for i := 0; i < 3000 && dynInt.Get() != 8; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int64(8), dynInt.Get())
+}
+
+func TestNATSKVSharedConn(t *testing.T) {
+	srv := newServer(t)
+	newBucket(t, srv, map[string]string{"some_dynint": "5"})
+	nc, err := nats.Connect(srv.ClientURL())
+	assert.NoError(t, err)
+	defer nc.Close()
+	for i := 0; i < 5; i++ {
+		s, err := New(Config{Conn: nc, Bucket: "dflag"})
+		assert.NoError(t, err)
+		values, err := s.Initialize(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "5", string(values["some_dynint"]))
+		updates := make(chan source.Update, 1)
+		assert.NoError(t, s.Watch(context.Background(), updates))
+		assert.Error(t, s.Watch(context.Background(), updates), "already started")
+		s.Stop()
+		s.Stop() // no-op
+	}
+	assert.False(t, nc.IsClosed(), "the application's connection isn't closed")
+	// initialized but never watched.
+	s, err := New(Config{URL: srv.ClientURL(), Bucket: "dflag"})
+	assert.NoError(t, err)
+	_, err = s.Initialize(context.Background())
+	assert.NoError(t, err)
+	s.Stop()
+}
+
+func TestNATSKVNoBucket(t *testing.T) {
+	srv := newServer(t)
+	fs := flag.NewFlagSet("natskv_test", flag.ContinueOnError)
+	_, err := Setup(context.Background(), fs, Config{URL: srv.ClientURL(), Bucket: "other"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "bucket other")
+}
+
+func TestFromURL(t *testing.T) {
+	src, err := source.Open("nats://tok@example.com/flags")
+	assert.NoError(t, err)
+	s := src.(*Source)
+	assert.Equal(t, "nats://example.com:4222", s.cfg.URL)
+	assert.Equal(t, "flags", s.cfg.Bucket)
+	assert.Equal(t, "tok", s.cfg.Token)
+	src, err = source.Open("nats://u:p@localhost:1234/b")
+	assert.NoError(t, err)
+	s = src.(*Source)
+	assert.Equal(t, "nats://localhost:1234", s.cfg.URL)
+	assert.Equal(t, "u", s.cfg.User)
+	assert.Equal(t, "p", s.cfg.Password)
+	_, err = source.Open("nats://localhost")
+	assert.Error(t, err, "bucket is required")
+}
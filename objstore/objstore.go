@@ -0,0 +1,280 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Package objstore polls a cloud object store bucket (S3 or any S3 compatible API like the
+// GCS XML API, MinIO, R2,...) and updates the corresponding flags, for serverless/VM deployments
+// where neither Kubernetes volumes nor etcd are available. Either a single object holding a
+// JSON/YAML map of flag values (see httppoll.ParseValues) or one object per flag under a prefix
+// (object name after the prefix is the flag name, like the files of a configmap directory) is used.
+// It uses the AWS SDK for Go v2 S3 client, so all its credential sources (environment, shared
+// config and SSO, web identity/IRSA, ECS and EC2 instance metadata,...) are supported. It is a
+// separate module so the main dflag module doesn't depend on the AWS SDK.
+// It registers the s3:// and gs:// schemes with the source package, see FromURL.
+package objstore
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"fortio.org/dflag/httppoll"
+	"fortio.org/dflag/source"
+	"fortio.org/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	// DefaultInterval is the polling interval when not specified in the Config.
+	DefaultInterval = 30 * time.Second
+	// GCSEndpoint is the GCS XML API endpoint used for gs:// URLs.
+	GCSEndpoint = "https://storage.googleapis.com"
+)
+
+// API is the subset of the s3.Client used, for alternative implementations and tests.
+type API interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input,
+		optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+}
+
+// Config is the configuration of the object store source. Client, Bucket and one of Key or Prefix are required.
+type Config struct {
+	// Client is the S3 client, e.g. s3.NewFromConfig(awsCfg).
+	Client API
+	// Bucket name.
+	Bucket string
+	// Key of a single object holding a JSON or YAML (when ending in .yaml or .yml, which requires
+	// importing fortio.org/dflag/httppoll/yaml) map of flag values.
+	Key string
+	// Prefix of one object per flag, e.g. "myapp/flags/". Ignored if Key is set.
+	Prefix string
+	// Interval between polls, defaults to DefaultInterval.
+	Interval time.Duration
+}
+
+// Source is the object store flag values source.
+type Source struct {
+	cfg Config
+	mu  sync.Mutex
+	// etags of the last fetched objects, to skip unchanged ones.
+	etags  map[string]string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func init() {
+	source.Register("s3", FromURL)
+	source.Register("gs", FromURL)
+}
+
+// FromURL creates a source from a s3://bucket/key or gs://bucket/key URL, or s3://bucket/prefix/
+// (trailing slash) for one object per flag. The client is configured by the AWS SDK default
+// configuration (AWS_REGION, credentials chain,...), the region and endpoint (e.g. for MinIO) can also
+// be set with ?region= and ?endpoint= query parameters. gs:// uses the GCS S3 interoperability
+// (XML API with HMAC keys as the AWS credentials).
+func FromURL(u *url.URL) (source.Source, error) {
+	cfg := Config{Bucket: u.Host}
+	if p := strings.TrimPrefix(u.Path, "/"); strings.HasSuffix(p, "/") {
+		cfg.Prefix = p
+	} else {
+		cfg.Key = p
+	}
+	q := u.Query()
+	endpoint, region := q.Get("endpoint"), q.Get("region")
+	var loadOpts []func(*config.LoadOptions) error
+	if u.Scheme == "gs" {
+		if endpoint == "" {
+			endpoint = GCSEndpoint
+		}
+		if region == "" {
+			region = "auto"
+		}
+		// GCS doesn't support the newer S3 checksum headers.
+		loadOpts = append(loadOpts,
+			config.WithRequestChecksumCalculation(aws.RequestChecksumCalculationWhenRequired),
+			config.WithResponseChecksumValidation(aws.ResponseChecksumValidationWhenRequired))
+	}
+	if region != "" {
+		loadOpts = append(loadOpts, config.WithRegion(region))
+	}
+	awsCfg, err := config.LoadDefaultConfig(context.Background(), loadOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("dflag: objstore aws config: %w", err)
+	}
+	cfg.Client = s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		}
+	})
+	return New(cfg)
+}
+
+// New creates an object store source.
+func New(cfg Config) (*Source, error) {
+	if cfg.Client == nil || cfg.Bucket == "" || (cfg.Key == "" && cfg.Prefix == "") {
+		return nil, errors.New("dflag: objstore Client, Bucket and Key or Prefix are required")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	return &Source{cfg: cfg, etags: make(map[string]string)}, nil
+}
+
+// Setup is a combination/shortcut for New+source.Apply: the object(s) are read, setting both
+// static and dynamic flags, then polled for updates of dynamic flags.
+func Setup(ctx context.Context, flagSet *flag.FlagSet, cfg Config) (*source.Applier, error) {
+	s, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return source.Apply(ctx, flagSet, s)
+}
+
+// Initialize reads the object(s) for the first time.
+func (s *Source) Initialize(ctx context.Context) (map[string][]byte, error) {
+	return s.poll(ctx)
+}
+
+// Watch kicks off the polling go routine. It stops when the context is done or Stop() is called.
+func (s *Source) Watch(ctx context.Context, updates chan<- source.Update) error {
+	if s.done != nil {
+		return errors.New("dflag: objstore already started")
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	go s.pollLoop(ctx, updates)
+	return nil
+}
+
+// Stop stops the polling go routine and waits for it to exit.
+func (s *Source) Stop() {
+	if s.done == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+	s.done = nil
+}
+
+func (s *Source) pollLoop(ctx context.Context, updates chan<- source.Update) {
+	defer close(s.done)
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			values, err := s.poll(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Errf("%v", err)
+				}
+				continue
+			}
+			source.Send(ctx, updates, values)
+		}
+	}
+}
+
+// fetch gets the object's content if its etag differs from the known one, returns nil content if unchanged.
+func (s *Source) fetch(ctx context.Context, key, knownETag string) ([]byte, string, error) {
+	in := &s3.GetObjectInput{Bucket: aws.String(s.cfg.Bucket), Key: aws.String(key)}
+	if knownETag != "" {
+		in.IfNoneMatch = aws.String(knownETag)
+	}
+	out, err := s.cfg.Client.GetObject(ctx, in)
+	if err != nil {
+		var re *awshttp.ResponseError
+		if errors.As(err, &re) && re.HTTPStatusCode() == http.StatusNotModified {
+			return nil, knownETag, nil
+		}
+		return nil, "", fmt.Errorf("dflag: objstore get %v: %w", key, err)
+	}
+	defer out.Body.Close()
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, aws.ToString(out.ETag), nil
+}
+
+// list returns the object key to etag map of objects directly under the prefix.
+func (s *Source) list(ctx context.Context) (map[string]string, error) {
+	res := make(map[string]string)
+	pages := s3.NewListObjectsV2Paginator(s.cfg.Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.cfg.Bucket),
+		Prefix: aws.String(s.cfg.Prefix),
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("dflag: objstore list %v: %w", s.cfg.Prefix, err)
+		}
+		for _, c := range page.Contents {
+			key := aws.ToString(c.Key)
+			name := strings.TrimPrefix(key, s.cfg.Prefix)
+			if name == "" || strings.Contains(name, "/") {
+				continue
+			}
+			res[key] = aws.ToString(c.ETag)
+		}
+	}
+	return res, nil
+}
+
+// poll returns the values of the changed object(s): the whole document in Key mode
+// (nil if unchanged), the changed objects' values in Prefix mode.
+func (s *Source) poll(ctx context.Context) (map[string][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var values map[string][]byte
+	if s.cfg.Key != "" {
+		data, etag, err := s.fetch(ctx, s.cfg.Key, s.etags[s.cfg.Key])
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			return nil, nil // unchanged
+		}
+		isYAML := strings.HasSuffix(s.cfg.Key, ".yaml") || strings.HasSuffix(s.cfg.Key, ".yml")
+		if values, err = httppoll.ParseValues(data, isYAML); err != nil {
+			return nil, fmt.Errorf("dflag: objstore %v: %w", s.cfg.Key, err)
+		}
+		s.etags[s.cfg.Key] = etag
+	} else {
+		objects, err := s.list(ctx)
+		if err != nil {
+			return nil, err
+		}
+		values = make(map[string][]byte)
+		etags := make(map[string]string, len(objects))
+		for key, etag := range objects {
+			name := strings.TrimPrefix(key, s.cfg.Prefix)
+			if known, found := s.etags[key]; found && known == etag {
+				etags[key] = etag
+				continue
+			}
+			data, newETag, err := s.fetch(ctx, key, "")
+			if err != nil {
+				log.Errf("%v", err)
+				continue // retried on next poll as its etag isn't recorded.
+			}
+			values[name] = data
+			etags[key] = newETag
+		}
+		s.etags = etags
+	}
+	return values, nil
+}
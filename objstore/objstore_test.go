@@ -0,0 +1,189 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package objstore
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/source"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// fakeBucket serves objects of bucket "b" S3 style (path style, ListObjectsV2 and etags).
+type fakeBucket struct {
+	mu      sync.Mutex
+	objects map[string]string
+	gets    atomic.Int32
+}
+
+func (f *fakeBucket) set(key, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objects[key] = value
+}
+
+func (f *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/b"), "/")
+	if key == "" {
+		if r.URL.Query().Get("list-type") != "2" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		prefix := r.URL.Query().Get("prefix")
+		keys := []string{}
+		for k := range f.objects {
+			if strings.HasPrefix(k, prefix) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		fmt.Fprint(w, "<ListBucketResult><IsTruncated>false</IsTruncated>")
+		for _, k := range keys {
+			fmt.Fprintf(w, "<Contents><Key>%s</Key><ETag>&quot;%x&quot;</ETag></Contents>", k, f.objects[k])
+		}
+		fmt.Fprint(w, "</ListBucketResult>")
+		return
+	}
+	v, found := f.objects[key]
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	etag := fmt.Sprintf("%q", fmt.Sprintf("%x", v))
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	f.gets.Add(1)
+	w.Header().Set("ETag", etag)
+	fmt.Fprint(w, v)
+}
+
+// newTestClient returns an S3 client for the fake bucket server.
+func newTestClient(endpoint, accessKeyID string) *s3.Client {
+	return s3.New(s3.Options{
+		BaseEndpoint:     aws.String(endpoint),
+		UsePathStyle:     true,
+		Region:           "us-east-1",
+		Credentials:      credentials.NewStaticCredentialsProvider(accessKeyID, "secret", ""),
+		RetryMaxAttempts: 1,
+	})
+}
+
+func newTestFlags() (*flag.FlagSet, *dflag.DynValue[int64], *int) {
+	fs := flag.NewFlagSet("objstore_test", flag.ContinueOnError)
+	return fs, dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing"), fs.Int("some_int", 1, "static int for testing")
+}
+
+func TestObjStorePrefix(t *testing.T) {
+	f := &fakeBucket{objects: map[string]string{
+		"app/flags/some_dynint": "5", "app/flags/some_int": "6", "app/flags/sub/other": "x", "app/other": "y",
+	}}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+	fs, dynInt, staticInt := newTestFlags()
+	client := newTestClient(srv.URL, "AKID")
+	_, err := New(Config{Client: client, Bucket: "b"})
+	assert.Error(t, err, "key or prefix required")
+	cfg := Config{Client: client, Bucket: "b", Prefix: "app/flags/", Interval: 20 * time.Millisecond}
+	s, err := Setup(context.Background(), fs, cfg)
+	assert.NoError(t, err)
+	defer s.Stop()
+	assert.Equal(t, int64(5), dynInt.Get())
+	assert.Equal(t, 6, *staticInt)
+	assert.Equal(t, int32(2), f.gets.Load())
+	f.set("app/flags/some_dynint", "7")
+	f.set("app/flags/some_int", "99")
+	for i := 0; i < 100 && dynInt.Get() != 7; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int64(7), dynInt.Get())
+	assert.Equal(t, 6, *staticInt, "static flags aren't changed by polling")
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, int32(4), f.gets.Load(), "only changed objects should be fetched")
+}
+
+func TestObjStoreKey(t *testing.T) {
+	f := &fakeBucket{objects: map[string]string{"flags.json": `{"some_dynint": 5, "some_int": 6}`}}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+	fs, dynInt, staticInt := newTestFlags()
+	cfg := Config{Client: newTestClient(srv.URL, "AKID"), Bucket: "b", Key: "flags.json", Interval: 20 * time.Millisecond}
+	s, err := Setup(context.Background(), fs, cfg)
+	assert.NoError(t, err)
+	defer s.Stop()
+	assert.Equal(t, int64(5), dynInt.Get())
+	assert.Equal(t, 6, *staticInt)
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, int32(1), f.gets.Load(), "unchanged object shouldn't be re-fetched")
+	f.set("flags.json", `{"some_dynint": 7, "some_int": 6}`)
+	for i := 0; i < 100 && dynInt.Get() != 7; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int64(7), dynInt.Get())
+}
+
+func TestObjStoreForbidden(t *testing.T) {
+	srv := httptest.NewServer(&fakeBucket{})
+	defer srv.Close()
+	fs, _, _ := newTestFlags()
+	_, err := Setup(context.Background(), fs, Config{Client: newTestClient(srv.URL, "other"), Bucket: "b", Key: "flags.json"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+}
+
+func TestFromURL(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-west-2")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_CONFIG_FILE", "/dev/null")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/dev/null")
+	src, err := source.Open("s3://b/app/flags/")
+	assert.NoError(t, err)
+	s := src.(*Source)
+	assert.Equal(t, "b", s.cfg.Bucket)
+	assert.Equal(t, "app/flags/", s.cfg.Prefix)
+	opts := s.cfg.Client.(*s3.Client).Options()
+	assert.Equal(t, "us-west-2", opts.Region)
+	creds, err := opts.Credentials.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "AKID", creds.AccessKeyID)
+	// the source works with the configured client (e.g. MinIO).
+	f := &fakeBucket{objects: map[string]string{"flags.json": `{"some_dynint": 5}`}}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+	src, err = source.Open("s3://b/flags.json?endpoint=" + srv.URL + "&region=local")
+	assert.NoError(t, err)
+	s = src.(*Source)
+	assert.Equal(t, "flags.json", s.cfg.Key)
+	assert.Equal(t, "local", s.cfg.Client.(*s3.Client).Options().Region)
+	values, err := s.Initialize(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "5", string(values["some_dynint"]))
+	src, err = source.Open("gs://b/flags.yaml")
+	assert.NoError(t, err)
+	opts = src.(*Source).cfg.Client.(*s3.Client).Options()
+	assert.Equal(t, GCSEndpoint, aws.ToString(opts.BaseEndpoint))
+	assert.True(t, opts.UsePathStyle, "path style for gs")
+}
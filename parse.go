@@ -0,0 +1,103 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Sources recorded in the history of the flags set by ParseWithEnv, see SetFlagFrom.
+const (
+	SourceEnv    = "env"
+	SourceConfig = "config"
+)
+
+// FlagEnvName returns the environment variable name of the flag: the prefix followed by the
+// flag name in upper case with dashes and dots replaced by underscores, e.g. MYAPP_SOME_FLAG for
+// some-flag or some_flag with prefix MYAPP_.
+func FlagEnvName(prefix, name string) string {
+	return prefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
+
+// ParseWithEnv parses the command line args into flagSet, then sets the flags not given there from their
+// environment variable (see FlagEnvName) and then from the configFiles, if any, in order. So the
+// precedence is command line, environment, first config file... and the flag's default last.
+// Config files have one `name value` or `name=value` per line (a bool flag alone is true), blank
+// lines and lines starting with # are ignored. Invalid values and unknown flags in config files are
+// errors, aggregated after the command line parsing ones.
+func ParseWithEnv(flagSet *flag.FlagSet, args []string, prefix string, configFiles ...string) error {
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+	set := make(map[string]bool)
+	flagSet.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	var errorStrings []string
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if set[f.Name] {
+			return
+		}
+		envName := FlagEnvName(prefix, f.Name)
+		value, found := os.LookupEnv(envName)
+		if !found {
+			return
+		}
+		set[f.Name] = true
+		if err := SetFlagFrom(flagSet, f.Name, value, SourceEnv); err != nil {
+			errorStrings = append(errorStrings, fmt.Sprintf("flag %v from %s: %v", f.Name, envName, err))
+		}
+	})
+	for _, file := range configFiles {
+		errorStrings = append(errorStrings, setFromConfigFile(flagSet, file, set)...)
+	}
+	if len(errorStrings) > 0 {
+		return fmt.Errorf("encountered %d errors while setting flags\n  %v",
+			len(errorStrings), strings.Join(errorStrings, "\n  "))
+	}
+	return nil
+}
+
+// setFromConfigFile sets the flags not in set from the config file, adding them to set.
+func setFromConfigFile(flagSet *flag.FlagSet, file string, set map[string]bool) []string {
+	f, err := os.Open(file)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	defer f.Close()
+	var errorStrings []string
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value := line, ""
+		if i := strings.IndexAny(line, "= \t"); i >= 0 {
+			name = line[:i]
+			value = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line[i:]), "="))
+		}
+		fl := flagSet.Lookup(name)
+		if fl == nil {
+			errorStrings = append(errorStrings, fmt.Sprintf("%s:%d: flag %q not found", file, lineNum, name))
+			continue
+		}
+		if set[name] {
+			continue
+		}
+		if b, ok := fl.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() && value == "" {
+			value = "true"
+		}
+		set[name] = true
+		if err := SetFlagFrom(flagSet, name, value, SourceConfig); err != nil {
+			errorStrings = append(errorStrings, fmt.Sprintf("%s:%d: flag %v: %v", file, lineNum, name, err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		errorStrings = append(errorStrings, fmt.Sprintf("%s: %v", file, err))
+	}
+	return errorStrings
+}
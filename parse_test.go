@@ -0,0 +1,56 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+)
+
+func TestFlagEnvName(t *testing.T) {
+	assert.Equal(t, "MYAPP_SOME_FLAG", FlagEnvName("MYAPP_", "some-flag"))
+	assert.Equal(t, "GRPC_TIMEOUT", FlagEnvName("", "grpc.timeout"))
+}
+
+func TestParseWithEnv(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "app.conf")
+	assert.NoError(t, os.WriteFile(config, []byte(`# comment
+cmd-line from-config
+
+from-env=from-config
+from-config = from config
+verbose
+`), 0o600))
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	cmdLine := set.String("cmd-line", "default", "")
+	fromEnv := set.String("from-env", "default", "")
+	fromConfig := DynString(set, "from-config", "default", "").WithHistory(1)
+	verbose := set.Bool("verbose", false, "")
+	dur := set.Duration("dur", time.Second, "")
+	t.Setenv("TEST_CMD_LINE", "from-env")
+	t.Setenv("TEST_FROM_ENV", "from-env")
+	assert.NoError(t, ParseWithEnv(set, []string{"-cmd-line", "from-cmd-line"}, "TEST_", config))
+	assert.Equal(t, "from-cmd-line", *cmdLine)
+	assert.Equal(t, "from-env", *fromEnv)
+	assert.Equal(t, "from config", fromConfig.Get())
+	assert.Equal(t, SourceConfig, fromConfig.History()[0].Source)
+	assert.True(t, *verbose, "bool flag alone in config")
+	assert.Equal(t, time.Second, *dur, "default")
+	// errors
+	set = flag.NewFlagSet("test", flag.ContinueOnError)
+	set.Duration("dur", time.Second, "")
+	bad := filepath.Join(dir, "bad.conf")
+	assert.NoError(t, os.WriteFile(bad, []byte("unknown 1\ndur x\n"), 0o600))
+	err := ParseWithEnv(set, nil, "TEST_", bad, filepath.Join(dir, "missing.conf"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "3 errors")
+	assert.Contains(t, err.Error(), "bad.conf:1")
+	assert.Error(t, ParseWithEnv(set, []string{"-nope"}, ""))
+}
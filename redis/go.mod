@@ -0,0 +1,26 @@
+module fortio.org/dflag/redis
+
+go 1.24
+
+require (
+	fortio.org/assert v1.2.1
+	fortio.org/dflag v1.9.0
+	fortio.org/log v1.17.1
+	github.com/alicebob/miniredis/v2 v2.39.0
+	github.com/redis/go-redis/v9 v9.22.0
+)
+
+require (
+	fortio.org/sets v1.2.0 // indirect
+	fortio.org/struct2env v0.4.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/kortschak/goroutine v1.1.2 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	go.uber.org/atomic v1.11.0 // indirect
+	golang.org/x/exp v0.0.0-20240604190554-fc45aab8b7f8 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+)
+
+// Build against the dflag module of this repository (the required v1.9.0 must be tagged, see
+// Releasing in the README, before this module is).
+replace fortio.org/dflag => ../
@@ -0,0 +1,184 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Package redis reads flag values from a Redis hash (field name is the flag name) and
+// subscribes to a pub/sub channel to re-read the hash when notified, for quick propagation
+// of flag flips across many instances. To update flags, change the hash and publish on the
+// channel, e.g.
+//
+//	HSET dflag some_flag 42
+//	PUBLISH dflag some_flag
+//
+// The message payload is only logged, the whole hash is re-read and only changed values are set.
+// It uses the go-redis client (its own module so the main dflag module doesn't depend on it) and
+// registers the redis:// and rediss:// (TLS) schemes with the source package, see FromURL.
+package redis
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"fortio.org/dflag/source"
+	"fortio.org/log"
+	"github.com/redis/go-redis/v9"
+)
+
+// Config is the configuration of the Redis source.
+type Config struct {
+	// Client to use, e.g. redis.NewClient(&redis.Options{...}) or a cluster/failover client.
+	Client redis.UniversalClient
+	// Key is the hash holding the flag values.
+	Key string
+	// Channel to subscribe to for change notifications, defaults to Key.
+	Channel string
+	// CloseClient makes Stop() also close the Client (set by FromURL which creates it).
+	CloseClient bool
+}
+
+// Source is the Redis flag values source.
+type Source struct {
+	cfg    Config
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func init() {
+	source.Register("redis", FromURL)
+	source.Register("rediss", FromURL)
+}
+
+// FromURL creates a source from a redis://[[user]:password@]host[:port][/db]?key=hash[&channel=name]
+// URL; rediss:// uses TLS. Other query parameters are go-redis options (e.g. dial_timeout=3s).
+func FromURL(u *url.URL) (source.Source, error) {
+	q := u.Query()
+	cfg := Config{Key: q.Get("key"), Channel: q.Get("channel"), CloseClient: true}
+	q.Del("key")
+	q.Del("channel")
+	clientURL := *u
+	clientURL.RawQuery = q.Encode()
+	opts, err := redis.ParseURL(clientURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("dflag: redis url: %w", err)
+	}
+	cfg.Client = redis.NewClient(opts)
+	s, err := New(cfg)
+	if err != nil {
+		_ = cfg.Client.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// New creates a Redis source.
+func New(cfg Config) (*Source, error) {
+	if cfg.Client == nil || cfg.Key == "" {
+		return nil, errors.New("dflag: redis Client and Key are required")
+	}
+	if cfg.Channel == "" {
+		cfg.Channel = cfg.Key
+	}
+	return &Source{cfg: cfg}, nil
+}
+
+// Setup is a combination/shortcut for New+source.Apply: the hash is read, setting both static
+// and dynamic flags, then the channel is subscribed to for updates of dynamic flags.
+func Setup(ctx context.Context, flagSet *flag.FlagSet, cfg Config) (*source.Applier, error) {
+	s, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return source.Apply(ctx, flagSet, s)
+}
+
+// Initialize reads the hash for the first time.
+func (s *Source) Initialize(ctx context.Context) (map[string][]byte, error) {
+	return s.readHash(ctx)
+}
+
+func (s *Source) readHash(ctx context.Context) (map[string][]byte, error) {
+	hash, err := s.cfg.Client.HGetAll(ctx, s.cfg.Key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("dflag: redis hash %v: %w", s.cfg.Key, err)
+	}
+	values := make(map[string][]byte, len(hash))
+	for k, v := range hash {
+		values[k] = []byte(v)
+	}
+	return values, nil
+}
+
+// Watch kicks off the go routine subscribed to the channel, re-reading the hash on each message
+// and each (re)subscription, as go-redis reconnects the subscription on errors and updates
+// may have been published while disconnected. It stops when the context is done or Stop() is called.
+func (s *Source) Watch(ctx context.Context, updates chan<- source.Update) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		return errors.New("dflag: redis source already started")
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	pubsub := s.cfg.Client.Subscribe(ctx, s.cfg.Channel)
+	go s.subscribeLoop(ctx, pubsub, updates)
+	return nil
+}
+
+// Stop stops the subscription go routine and waits for it to exit.
+func (s *Source) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+	s.done = nil
+	if s.cfg.CloseClient {
+		_ = s.cfg.Client.Close()
+	}
+}
+
+func (s *Source) subscribeLoop(ctx context.Context, pubsub *redis.PubSub, updates chan<- source.Update) {
+	defer close(s.done)
+	defer pubsub.Close()
+	msgs := pubsub.ChannelWithSubscriptions()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-msgs:
+			if !ok {
+				return
+			}
+			switch msg := m.(type) {
+			case *redis.Subscription:
+				if msg.Kind != "subscribe" {
+					continue
+				}
+				log.Infof("dflag: redis subscribed to %v", s.cfg.Channel)
+			case *redis.Message:
+				log.Infof("dflag: redis notification on %v: %q", s.cfg.Channel, msg.Payload)
+			default:
+				continue
+			}
+			s.reload(ctx, updates)
+		}
+	}
+}
+
+// reload re-reads the hash and sends its values as updates.
+func (s *Source) reload(ctx context.Context, updates chan<- source.Update) {
+	values, err := s.readHash(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Errf("%v", err)
+		}
+		return
+	}
+	source.Send(ctx, updates, values)
+}
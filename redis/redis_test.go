@@ -0,0 +1,92 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package redis
+
+import (
+	"context"
+	"flag"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/source"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func waitFor(cond func() bool) {
+	for i := 0; i < 300 && !cond(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRedisSource(t *testing.T) {
+	m := miniredis.RunT(t)
+	m.RequireAuth("secret")
+	m.HSet("dflag", "some_dynint", "5", "some_int", "6", "unknown", "x")
+	fs := flag.NewFlagSet("redis_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	dynStr := dflag.DynString(fs, "some_dynstr", "a", "dynamic string for testing")
+	staticInt := fs.Int("some_int", 1, "static int for testing")
+	client := redis.NewClient(&redis.Options{Addr: m.Addr(), Password: "secret", MaxRetries: -1})
+	defer client.Close()
+	_, err := New(Config{Client: client})
+	assert.Error(t, err, "key is required")
+	s, err := Setup(context.Background(), fs, Config{Client: client, Key: "dflag"})
+	assert.NoError(t, err)
+	defer s.Stop()
+	assert.Equal(t, int64(5), dynInt.Get())
+	assert.Equal(t, 6, *staticInt)
+	waitFor(func() bool { return len(m.PubSubChannels("")) == 1 })
+	m.HSet("dflag", "some_dynint", "7", "some_int", "99")
+	m.Publish("dflag", "some_dynint")
+	waitFor(func() bool { return dynInt.Get() == 7 })
+	assert.Equal(t, int64(7), dynInt.Get())
+	assert.Equal(t, 6, *staticInt, "static flags aren't changed by notifications")
+	// updates made while disconnected are caught up on re-subscription.
+	m.Close()
+	assert.NoError(t, m.Restart())
+	m.HSet("dflag", "some_dynint", "7", "some_dynstr", "b")
+	waitFor(func() bool { return dynStr.Get() == "b" })
+	assert.Equal(t, "b", dynStr.Get())
+}
+
+func TestRedisURL(t *testing.T) {
+	m := miniredis.RunT(t)
+	m.RequireAuth("secret")
+	m.HSet("dflag", "some_dynint", "5")
+	fs := flag.NewFlagSet("redis_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	a, err := source.Setup(context.Background(), fs, "redis://:secret@"+m.Addr()+"?key=dflag")
+	assert.NoError(t, err)
+	defer a.Stop()
+	assert.Equal(t, int64(5), dynInt.Get())
+	s, err := source.Open("rediss://u:p@example.com/3?key=k&channel=c&dial_timeout=3s")
+	assert.NoError(t, err)
+	cfg := s.(*Source).cfg
+	opts := cfg.Client.(*redis.Client).Options()
+	assert.Equal(t, "example.com:6379", opts.Addr)
+	assert.Equal(t, 3, opts.DB)
+	assert.Equal(t, "u", opts.Username)
+	assert.Equal(t, 3*time.Second, opts.DialTimeout)
+	assert.Equal(t, "k", cfg.Key)
+	assert.Equal(t, "c", cfg.Channel)
+	assert.True(t, opts.TLSConfig != nil, "rediss should use TLS")
+	_, err = source.Open("redis://localhost/x?key=k")
+	assert.Error(t, err, "bad db")
+	_, err = source.Open("redis://localhost/0")
+	assert.Error(t, err, "key is required")
+}
+
+func TestRedisBadPassword(t *testing.T) {
+	m := miniredis.RunT(t)
+	m.RequireAuth("secret")
+	fs := flag.NewFlagSet("redis_test", flag.ContinueOnError)
+	client := redis.NewClient(&redis.Options{Addr: m.Addr(), Password: "bad"})
+	defer client.Close()
+	_, err := Setup(context.Background(), fs, Config{Client: client, Key: "dflag"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "WRONGPASS")
+}
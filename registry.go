@@ -0,0 +1,58 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrFlagNotFound is returned (wrapped) by GetDyn when the FlagSet has no flag of that name.
+	ErrFlagNotFound = errors.New("dflag: flag not found")
+	// ErrFlagNotDynamic is returned (wrapped) by GetDyn for the standard flags.
+	ErrFlagNotDynamic = errors.New("dflag: flag is not dynamic")
+	// ErrFlagType is returned (wrapped) by GetDyn when the dynamic flag's values aren't of the requested type.
+	ErrFlagType = errors.New("dflag: dynamic flag of another type")
+)
+
+// dynValuer is implemented by all the dynamic flags built on DynValue (DynBoolValue, DynJSONValue,
+// DynFuncValue...) to return it.
+type dynValuer[T any] interface {
+	dynValue() *DynValue[T]
+}
+
+func (d *DynValue[T]) dynValue() *DynValue[T] {
+	return d
+}
+
+// VisitDynamic calls fn for each dynamic flag of flagSet, in lexicographical order, with its name and
+// value: type assert it to the Dynamic* interfaces (e.g. DynamicFlagWatcher) or use GetDyn for typed access.
+func VisitDynamic(flagSet *flag.FlagSet, fn func(name string, v DynamicFlagValue)) {
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if dv, ok := f.Value.(DynamicFlagValue); ok && dv.IsDynamicFlag() {
+			fn(f.Name, dv)
+		}
+	})
+}
+
+// GetDyn returns the DynValue of the named dynamic flag of flagSet, e.g. GetDyn[int64](fs, "port")
+// for a DynInt64 flag or GetDyn[bool] for a DynBool one (interface{} for DynJSON and Wrap flags).
+// The error wraps ErrFlagNotFound, ErrFlagNotDynamic or ErrFlagType.
+func GetDyn[T any](flagSet *flag.FlagSet, name string) (*DynValue[T], error) {
+	f := flagSet.Lookup(name)
+	if f == nil {
+		return nil, fmt.Errorf("%w: %q", ErrFlagNotFound, name)
+	}
+	if !IsFlagDynamic(f) {
+		return nil, fmt.Errorf("%w: %q", ErrFlagNotDynamic, name)
+	}
+	v, ok := f.Value.(dynValuer[T])
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is a %T, not a %v one", ErrFlagType, name, f.Value, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return v.dynValue(), nil
+}
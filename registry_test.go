@@ -0,0 +1,57 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"errors"
+	"flag"
+	"testing"
+
+	"fortio.org/assert"
+)
+
+func TestVisitDynamic(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	DynInt64(set, "some_int", 1, "")
+	DynBool(set, "some_bool", false, "")
+	set.Int("static_int", 1, "")
+	var names []string
+	VisitDynamic(set, func(name string, v DynamicFlagValue) {
+		names = append(names, name)
+		_, ok := v.(DynamicFlagWatcher)
+		assert.True(t, ok, "all the dynamic flags are watchers")
+	})
+	assert.Equal(t, []string{"some_bool", "some_int"}, names)
+}
+
+func TestGetDyn(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := DynInt64(set, "some_int", 1, "")
+	DynBool(set, "some_bool", false, "")
+	DynStringSet(set, "some_set", []string{"a"}, "")
+	DynJSON(set, "some_json", &struct{ A int }{}, "")
+	DynFunc(set, "some_func", "x", "", func(string) error { return nil })
+	set.Int("static_int", 1, "")
+	v, err := GetDyn[int64](set, "some_int")
+	assert.NoError(t, err)
+	assert.True(t, v == dynInt, "same DynValue")
+	b, err := GetDyn[bool](set, "some_bool")
+	assert.NoError(t, err)
+	assert.NoError(t, set.Set("some_bool", "true"))
+	assert.True(t, b.Get(), "bool value set through the flag")
+	_, err = GetDyn[[]string](set, "some_set")
+	assert.True(t, errors.Is(err, ErrFlagType), "sets aren't slices")
+	_, err = GetDyn[interface{}](set, "some_json")
+	assert.NoError(t, err)
+	f, err := GetDyn[string](set, "some_func")
+	assert.NoError(t, err)
+	assert.Equal(t, "x", f.Get())
+	_, err = GetDyn[string](set, "some_int")
+	assert.True(t, errors.Is(err, ErrFlagType))
+	assert.Contains(t, err.Error(), "not a string one")
+	_, err = GetDyn[int](set, "static_int")
+	assert.True(t, errors.Is(err, ErrFlagNotDynamic))
+	_, err = GetDyn[int](set, "missing")
+	assert.True(t, errors.Is(err, ErrFlagNotFound))
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"context"
+	"flag"
+	"fmt"
+)
+
+// Requirements tracks dynamic flags that must be set (from the command line, a ConfigMap, a source,
+// the endpoint,...) before the service can work, as their defaults aren't usable: e.g. to only report
+// ready, and accept traffic, once the first ConfigMap sync happened. See Require.
+type Requirements struct {
+	names    []string
+	watchers []DynamicFlagWatcher
+	ready    chan struct{}
+}
+
+// Require returns the Requirements that the named dynamic flags of flagSet are set at least once.
+// The error wraps ErrFlagNotFound or ErrFlagNotDynamic for names that can't be tracked.
+func Require(flagSet *flag.FlagSet, names ...string) (*Requirements, error) {
+	r := &Requirements{names: names, ready: make(chan struct{})}
+	for _, name := range names {
+		f := flagSet.Lookup(name)
+		if f == nil {
+			return nil, fmt.Errorf("%w: %q", ErrFlagNotFound, name)
+		}
+		w, ok := f.Value.(DynamicFlagWatcher)
+		if !ok || !IsFlagDynamic(f) {
+			return nil, fmt.Errorf("%w: %q", ErrFlagNotDynamic, name)
+		}
+		r.watchers = append(r.watchers, w)
+	}
+	go r.wait()
+	return r, nil
+}
+
+// wait closes the ready channel once all the flags are set (a go routine per Requirements until then).
+func (r *Requirements) wait() {
+	for _, w := range r.watchers {
+		for {
+			changed := w.Changed()
+			if w.Generation() > 0 {
+				break
+			}
+			<-changed
+		}
+	}
+	close(r.ready)
+}
+
+// Ready returns whether all the required flags were set.
+func (r *Requirements) Ready() bool {
+	return len(r.Missing()) == 0
+}
+
+// ReadyChan returns a channel closed once all the required flags were set.
+func (r *Requirements) ReadyChan() <-chan struct{} {
+	return r.ready
+}
+
+// Wait waits until all the required flags were set or ctx is done (returning its error).
+func (r *Requirements) Wait(ctx context.Context) error {
+	select {
+	case <-r.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Missing returns the names of the required flags not set yet.
+func (r *Requirements) Missing() []string {
+	var missing []string
+	for i, w := range r.watchers {
+		if w.Generation() == 0 {
+			missing = append(missing, r.names[i])
+		}
+	}
+	return missing
+}
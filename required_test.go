@@ -0,0 +1,45 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+)
+
+func TestRequire(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	DynString(set, "db_url", "", "")
+	DynInt64(set, "pool", 1, "")
+	DynJSON(set, "limits", &outerJSON{}, "")
+	set.Int("static", 1, "")
+	_, err := Require(set, "db_url", "static")
+	assert.True(t, errors.Is(err, ErrFlagNotDynamic))
+	_, err = Require(set, "missing")
+	assert.True(t, errors.Is(err, ErrFlagNotFound))
+	r, err := Require(set, "db_url", "pool", "limits")
+	assert.NoError(t, err)
+	assert.False(t, r.Ready())
+	assert.Equal(t, []string{"db_url", "pool", "limits"}, r.Missing())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.True(t, errors.Is(r.Wait(ctx), context.DeadlineExceeded))
+	assert.NoError(t, set.Set("pool", "1"), "set, even to the default value")
+	assert.NoError(t, set.Set("db_url", "postgres://db"))
+	assert.Equal(t, []string{"limits"}, r.Missing())
+	select {
+	case <-r.ReadyChan():
+		t.Fatal("not ready yet")
+	default:
+	}
+	assert.NoError(t, set.Set("limits", `{"string": "x"}`))
+	assert.NoError(t, r.Wait(context.Background()))
+	assert.True(t, r.Ready())
+	assert.Equal(t, 0, len(r.Missing()))
+}
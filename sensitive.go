@@ -0,0 +1,109 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"errors"
+	"flag"
+	"strings"
+)
+
+// Redacted replaces the values of the sensitive flags in all the dflag outputs, see Sensitive.
+const Redacted = "<redacted>"
+
+// ErrRedactedValue is returned when setting a sensitive flag to Redacted, e.g. when re-importing an export.
+var ErrRedactedValue = errors.New("dflag: refusing to set a sensitive flag to the redacted placeholder")
+
+// DynamicFlagSensitive is implemented by the dynamic flags to tell whether their values are secrets.
+type DynamicFlagSensitive interface {
+	IsSensitive() bool
+}
+
+// Sensitive marks the flag's values as secrets (passwords, tokens,...): String() (thus the flag's default in
+// PrintDefaults, the endpoint's listings and exports and Dump), History(), the errors of invalid values,
+// the endpoint's audit entries and responses and the configmap updater's and sources' logs and events
+// show Redacted instead. Get() still returns the actual value.
+func (d *DynValue[T]) Sensitive() *DynValue[T] {
+	d.sensitive = true
+	if d.flagSet != nil {
+		if f := d.flagSet.Lookup(d.flagName); f != nil {
+			f.DefValue = Redacted // already bound with the actual default.
+		}
+	}
+	return d
+}
+
+// IsSensitive returns whether the flag was marked Sensitive.
+func (d *DynValue[T]) IsSensitive() bool {
+	return d.sensitive
+}
+
+// IsSensitive returns whether the flag is a dynamic one marked Sensitive.
+func IsSensitive(f *flag.Flag) bool {
+	s, ok := f.Value.(DynamicFlagSensitive)
+	return ok && s.IsSensitive()
+}
+
+// Redact returns Redacted instead of value (e.g. a new value being set) for the sensitive flags, value otherwise.
+func Redact(f *flag.Flag, value string) string {
+	if f != nil && IsSensitive(f) {
+		return Redacted
+	}
+	return value
+}
+
+// changeDetector is implemented by the dynamic flags to tell if their value differs from the default
+// without comparing (redacted) strings.
+type changeDetector interface {
+	isChanged() bool
+}
+
+func (d *DynValue[T]) isChanged() bool {
+	return historyValue(d.load()) != historyValue(d.defaultValue)
+}
+
+// IsChanged returns whether the flag's value differs from its default, including for the sensitive flags.
+func IsChanged(f *flag.Flag) bool {
+	if c, ok := f.Value.(changeDetector); ok && IsSensitive(f) {
+		return c.isChanged()
+	}
+	return f.Value.String() != f.DefValue
+}
+
+// redactedError is an error whose message has the sensitive values replaced by Redacted.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactValueError is redactError for the errors about val (e.g. from validators).
+func (d *DynValue[T]) redactValueError(err error, val T) error {
+	if !d.sensitive || err == nil {
+		return err
+	}
+	return d.redactError(err, valueString(val))
+}
+
+// redactError returns err with the values removed from its message (e.g. strconv's errors quote the input)
+// when the flag is sensitive.
+func (d *DynValue[T]) redactError(err error, values ...string) error {
+	if !d.sensitive || err == nil {
+		return err
+	}
+	msg := err.Error()
+	for _, v := range values {
+		if v != "" {
+			msg = strings.ReplaceAll(msg, v, Redacted)
+		}
+	}
+	return &redactedError{msg: msg, err: err}
+}
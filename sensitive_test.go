@@ -0,0 +1,64 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"strconv"
+	"strings"
+	"testing"
+
+	"fortio.org/assert"
+)
+
+func TestSensitive(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	token := Dyn(set, "token", "s3cr3t", "api token").Sensitive().WithHistory(5)
+	pin := New(int64(1234), "pin").Sensitive().WithValidator(ValidateRange[int64](1000, 9999))
+	FlagSet(set, "pin", pin)
+	js := DynJSON(set, "creds", &outerJSON{FieldString: "pass"}, "")
+	js.Sensitive()
+	set.String("static", "x", "")
+	wrapped, err := Wrap(set, "static")
+	assert.NoError(t, err)
+	wrapped.Sensitive()
+	plain := Dyn(set, "plain", "visible", "")
+	for _, name := range []string{"token", "pin", "creds", "static"} {
+		f := set.Lookup(name)
+		assert.Equal(t, Redacted, f.Value.String(), name)
+		assert.Equal(t, Redacted, f.DefValue, name)
+		assert.True(t, IsSensitive(f), name)
+		assert.Equal(t, Redacted, Redact(f, "anything"))
+	}
+	assert.False(t, IsSensitive(set.Lookup("plain")))
+	assert.Equal(t, "visible", Redact(set.Lookup("plain"), "visible"))
+	assert.Equal(t, "visible", plain.String())
+	var buf bytes.Buffer
+	set.SetOutput(&buf)
+	set.PrintDefaults()
+	assert.False(t, strings.Contains(buf.String(), "s3cr3t"), buf.String())
+	assert.False(t, strings.Contains(buf.String(), "1234"), buf.String())
+	assert.Equal(t, "s3cr3t", token.Get(), "actual value")
+	assert.False(t, IsChanged(set.Lookup("token")))
+	assert.NoError(t, set.Set("token", "n3w"))
+	assert.True(t, IsChanged(set.Lookup("token")), "changed even though the strings are both redacted")
+	assert.Equal(t, Redacted, token.History()[0].Value)
+	err = set.Set("pin", "12x4")
+	assert.Error(t, err)
+	assert.False(t, strings.Contains(err.Error(), "12x4"), err.Error())
+	assert.True(t, errors.Is(err, strconv.ErrSyntax), "still unwraps")
+	err = set.Set("pin", "99999")
+	assert.Error(t, err)
+	assert.False(t, strings.Contains(err.Error(), "99999"), err.Error())
+	assert.True(t, errors.Is(set.Set("token", Redacted), ErrRedactedValue))
+	assert.True(t, errors.Is(set.Set("static", Redacted), ErrRedactedValue))
+	assert.True(t, errors.Is(token.ValidateInput(Redacted), ErrRedactedValue))
+	assert.Equal(t, "n3w", token.Get())
+	out, err := Dump(set, DumpEnv)
+	assert.NoError(t, err)
+	assert.False(t, strings.Contains(out, "n3w"), out)
+	assert.Contains(t, out, "TOKEN='<redacted>'")
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Package service wires the dflag pieces a fortio style server uses in one place: the dynamic log
+// level and format flags, the configmap directory watcher and the flags endpoints, e.g.
+//
+//	svc := service.New(flag.CommandLine)
+//	flag.Parse()
+//	err := svc.Start(ctx, http.DefaultServeMux) // and defer svc.Stop()
+package service
+
+import (
+	"context"
+	"flag"
+	"net/http"
+
+	"fortio.org/dflag"
+	"fortio.org/dflag/configmap"
+	"fortio.org/dflag/dynloglevel"
+	"fortio.org/dflag/endpoint"
+)
+
+// DefaultPrefix is where Start mounts the flags endpoints.
+const DefaultPrefix = "/debug/flags"
+
+// Service holds the flags defined by New and what Start sets up.
+type Service struct {
+	FlagSet   *flag.FlagSet
+	LogLevel  *dflag.DynValue[string]
+	LogFormat *dflag.DynValue[string]
+	LogColor  *dflag.DynValue[string]
+	// ConfigDir is the `config-dir` flag: the directory (e.g. a mounted ConfigMap) of flag values to watch.
+	ConfigDir *string
+	// FlagsSet is the `flags-set` flag: whether the endpoint can set flags (at DefaultPrefix/set).
+	FlagsSet *bool
+	// Prefix the endpoints are mounted under, DefaultPrefix unless changed before Start.
+	Prefix string
+	// Updater is the configmap watcher, nil when no config directory is set.
+	Updater *configmap.Updater
+	// Endpoint serves the flags, available to add options (e.g. WithAuth) after Start.
+	Endpoint *endpoint.FlagsEndpoint
+}
+
+// New defines in flagSet the `loglevel`, `logformat`, `logcolor`, `config-dir` and `flags-set` flags,
+// to be called before parsing it.
+func New(flagSet *flag.FlagSet) *Service {
+	s := &Service{FlagSet: flagSet, Prefix: DefaultPrefix}
+	if flagSet == flag.CommandLine {
+		// the global setups, as configmap and endpoint also call LoggerFlagSetup.
+		dynloglevel.LoggerFlagSetup()
+		dynloglevel.FormatFlagSetup()
+		s.LogLevel, _ = flagSet.Lookup("loglevel").Value.(*dflag.DynValue[string])
+		s.LogFormat, _ = flagSet.Lookup("logformat").Value.(*dflag.DynValue[string])
+		s.LogColor, _ = flagSet.Lookup("logcolor").Value.(*dflag.DynValue[string])
+	} else {
+		s.LogLevel = dynloglevel.LogLevelFlag(flagSet)
+		s.LogFormat, s.LogColor = dynloglevel.FormatFlags(flagSet)
+	}
+	s.ConfigDir = flagSet.String("config-dir", "", "`directory` of flag values to watch (e.g. a mounted ConfigMap)")
+	s.FlagsSet = flagSet.Bool("flags-set", false, "whether flags can be set through the "+DefaultPrefix+" endpoint")
+	return s
+}
+
+// Start, once the flags are parsed, sets up the configmap Updater when `config-dir` is set (stopping
+// when ctx is done) and mounts the flags endpoints on mux under Prefix, with setting enabled (with
+// CSRF protection) when `flags-set` is true.
+func (s *Service) Start(ctx context.Context, mux *http.ServeMux) error {
+	if *s.ConfigDir != "" {
+		u, err := configmap.SetupWithContext(ctx, s.FlagSet, *s.ConfigDir)
+		if err != nil {
+			return err
+		}
+		s.Updater = u
+	}
+	setURL := ""
+	if *s.FlagsSet {
+		setURL = s.Prefix + "/set"
+	}
+	s.Endpoint = endpoint.NewFlagsEndpoint(s.FlagSet, setURL).WithAuditHistory(100)
+	if setURL != "" {
+		s.Endpoint.WithCSRFProtection(nil)
+	}
+	s.Endpoint.Mount(mux, s.Prefix)
+	return nil
+}
+
+// Stop stops and releases the configmap Updater, if any.
+func (s *Service) Stop() {
+	if s.Updater != nil {
+		_ = s.Updater.Close()
+	}
+}
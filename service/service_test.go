@@ -0,0 +1,66 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package service
+
+import (
+	"context"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+func TestService(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some-flag"), []byte("from file"), 0o600))
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	someFlag := dflag.DynString(flagSet, "some-flag", "default", "a flag")
+	svc := New(flagSet)
+	assert.True(t, svc.LogLevel != nil && svc.LogFormat != nil && svc.LogColor != nil, "log flags defined")
+	assert.NoError(t, flagSet.Parse([]string{"-config-dir", dir, "-flags-set"}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mux := http.NewServeMux()
+	assert.NoError(t, svc.Start(ctx, mux))
+	defer svc.Stop()
+	assert.True(t, svc.Updater != nil, "configmap updater started")
+	assert.Equal(t, "from file", someFlag.Get())
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/debug/flags/some-flag")
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "from file")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "some-flag"), []byte("updated"), 0o600))
+	deadline := time.Now().Add(5 * time.Second)
+	for someFlag.Get() != "updated" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, "updated", someFlag.Get())
+}
+
+func TestServiceNoConfigDir(t *testing.T) {
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	svc := New(flagSet)
+	assert.NoError(t, flagSet.Parse(nil))
+	mux := http.NewServeMux()
+	assert.NoError(t, svc.Start(context.Background(), mux))
+	svc.Stop()
+	assert.True(t, svc.Updater == nil, "no updater without config-dir")
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	resp, err := http.Post(srv.URL+"/debug/flags/set", "application/json", nil)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "set not enabled")
+}
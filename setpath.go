@@ -0,0 +1,95 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"fortio.org/sets"
+)
+
+// DynamicFlagAnySetter is implemented by the dynamic flags to set an already typed value, e.g. from
+// a source providing them, skipping the string formatting and parsing (see SetFlagValue).
+type DynamicFlagAnySetter interface {
+	SetAny(value interface{}) error
+}
+
+// SetAny is SetV for a value known as an interface{}: it must be of the flag's type T, the
+// error then wraps ErrFlagType.
+func (d *DynValue[T]) SetAny(value interface{}) error {
+	v, ok := value.(T)
+	if !ok {
+		return d.setError(fmt.Errorf("%w: %q got a %T, not a %v", ErrFlagType, d.flagName, value, reflect.TypeOf((*T)(nil)).Elem()))
+	}
+	return d.SetV(v)
+}
+
+// SetAny sets the value, which must be a pointer to the flag's struct (or slice) type.
+func (d *DynJSONValue) SetAny(value interface{}) error {
+	if reflect.TypeOf(value) != reflect.PtrTo(d.structType) {
+		return d.setError(fmt.Errorf("%w: %q got a %T, not a *%v", ErrFlagType, d.flagName, value, d.structType))
+	}
+	return d.SetV(value)
+}
+
+// SetFlagValue sets the named dynamic flag of flagSet to the typed value, e.g. an int64 for a DynInt64
+// flag or a *MyConfig for a DynJSON one, without going through its string representation.
+// Unlike flagSet.Set it doesn't mark the flag as set (for flagSet.Visit).
+// The error wraps ErrFlagNotFound, ErrFlagNotDynamic or ErrFlagType when the value can't be set.
+func SetFlagValue(flagSet *flag.FlagSet, name string, value interface{}) error {
+	f := flagSet.Lookup(name)
+	if f == nil {
+		return fmt.Errorf("%w: %q", ErrFlagNotFound, name)
+	}
+	if !IsFlagDynamic(f) {
+		return fmt.Errorf("%w: %q", ErrFlagNotDynamic, name)
+	}
+	s, ok := f.Value.(DynamicFlagAnySetter)
+	if !ok {
+		return fmt.Errorf("%w: %q is a %T, which can't be set from a value", ErrFlagType, name, f.Value)
+	}
+	return s.SetAny(value)
+}
+
+// maxPooledBuffer is the largest parsing buffer kept for reuse, so a one off huge value isn't held on to.
+const maxPooledBuffer = 64 << 10
+
+// parseBuffers are the scratch buffers of the parsing of values that aren't kept, e.g. the []byte
+// json.Unmarshal needs (it copies what it keeps).
+var parseBuffers = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 512)
+		return &b
+	},
+}
+
+// unmarshalJSON is json.Unmarshal of a string without allocating a copy of it.
+func unmarshalJSON(input string, v interface{}) error {
+	bp := parseBuffers.Get().(*[]byte)
+	b := append((*bp)[:0], input...)
+	err := json.Unmarshal(b, v)
+	if cap(b) <= maxPooledBuffer {
+		*bp = b
+		parseBuffers.Put(bp)
+	}
+	return err
+}
+
+// commaStringToSet is sets.FromSlice(CommaStringToSlice(input)) without the intermediate slice.
+func commaStringToSet(input string) sets.Set[string] {
+	res := make(sets.Set[string], strings.Count(input, ",")+1)
+	for {
+		before, after, found := strings.Cut(input, ",")
+		res.Add(before)
+		if !found {
+			return res
+		}
+		input = after
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/sets"
+)
+
+func TestSetFlagValue(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := DynInt64(set, "some_int", 1, "").WithValidator(ValidateRange[int64](0, 10))
+	dynSet := DynStringSet(set, "some_set", []string{"a"}, "")
+	js := DynJSON(set, "some_json", &outerJSON{}, "")
+	set.Int("static_int", 1, "")
+	assert.NoError(t, SetFlagValue(set, "some_int", int64(3)))
+	assert.Equal(t, int64(3), dynInt.Get())
+	assert.Equal(t, uint64(1), dynInt.Generation())
+	assert.Error(t, SetFlagValue(set, "some_int", int64(42)), "validator still applies")
+	err := SetFlagValue(set, "some_int", 3)
+	assert.True(t, errors.Is(err, ErrFlagType), "int isn't int64")
+	assert.Contains(t, err.Error(), "got a int, not a int64")
+	assert.NoError(t, SetFlagValue(set, "some_set", sets.New("b", "c")))
+	assert.Equal(t, sets.New("b", "c"), dynSet.Get())
+	assert.NoError(t, SetFlagValue(set, "some_json", &outerJSON{FieldString: "x"}))
+	assert.Equal(t, "x", js.Get().(*outerJSON).FieldString)
+	err = SetFlagValue(set, "some_json", outerJSON{})
+	assert.True(t, errors.Is(err, ErrFlagType), "not a pointer")
+	assert.True(t, errors.Is(SetFlagValue(set, "static_int", 2), ErrFlagNotDynamic))
+	assert.True(t, errors.Is(SetFlagValue(set, "missing", 2), ErrFlagNotFound))
+}
+
+func TestParseBuffers(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	js := DynJSON(set, "some_json", &outerJSON{}, "")
+	big := strings.Repeat("x", 2*maxPooledBuffer)
+	assert.NoError(t, set.Set("some_json", `{"string": "`+big+`"}`))
+	assert.Equal(t, big, js.Get().(*outerJSON).FieldString)
+	assert.NoError(t, set.Set("some_json", `{"string": "small"}`))
+	assert.Equal(t, "small", js.Get().(*outerJSON).FieldString, "not sharing the (reused) input buffer")
+	assert.Error(t, set.Set("some_json", `{"string": `))
+	assert.Equal(t, sets.New("a", "", "b"), commaStringToSet("a,,b"))
+	assert.Equal(t, sets.New(""), commaStringToSet(""))
+}
+
+func BenchmarkSetFlagValue_Int64(b *testing.B) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	Dyn(set, "some_int", int64(0), "")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = SetFlagValue(set, "some_int", int64(12345))
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package source_test
+
+import (
+	"context"
+	"flag"
+	"testing"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/dflagtest"
+	"fortio.org/dflag/source"
+)
+
+// loggingSource is a FakeSource configured with a logger, see source.LoggerProvider.
+type loggingSource struct {
+	*dflagtest.FakeSource
+	logger dflag.Logger
+}
+
+func (s *loggingSource) Logger() dflag.Logger {
+	return s.logger
+}
+
+func TestApplierLogger(t *testing.T) {
+	fs := flag.NewFlagSet("source_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing").WithValidator(dflag.ValidateRange[int64](0, 10))
+	logger := &dflagtest.Logger{}
+	src := &loggingSource{FakeSource: dflagtest.NewFakeSource(map[string]string{"some_dynint": "5", "unknown": "x"}), logger: logger}
+	a, err := source.Apply(context.Background(), fs, src)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), dynInt.Get())
+	assert.NoError(t, src.Push("some_dynint", "42"))
+	assert.NoError(t, src.Delete("some_dynint"))
+	assert.NoError(t, src.Push("some_dynint", "7"))
+	dflagtest.WaitGeneration(t, dynInt, 2)
+	infos := logger.Entries("info")
+	assert.Equal(t, 4, len(infos))
+	assert.Equal(t, "dflag: updating flag", infos[0].Msg)
+	assert.Equal(t, "5", infos[0].Attr("value"))
+	assert.Equal(t, "dflag: flag removed from source, left unchanged", infos[2].Msg)
+	assert.Equal(t, "7", infos[3].Attr("value"))
+	warnings := logger.Entries("warn")
+	assert.Equal(t, 1, len(warnings))
+	assert.Equal(t, "unknown", warnings[0].Attr("flag"))
+	errs := logger.Entries("error")
+	assert.Equal(t, 1, len(errs), "42 is out of range")
+	assert.Equal(t, "some_dynint", errs[0].Attr("flag"))
+	// WithLogger overrides the source's.
+	other := &dflagtest.Logger{}
+	a.Stop()
+	a = source.NewApplier(fs, dflagtest.NewFakeSource(map[string]string{"some_dynint": "6"})).WithLogger(other)
+	assert.NoError(t, a.Initialize(context.Background()))
+	assert.Equal(t, 1, len(other.Entries("info")))
+}
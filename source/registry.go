@@ -0,0 +1,75 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package source
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownScheme is returned by Open for URLs whose scheme has no registered backend.
+var ErrUnknownScheme = errors.New("no source registered for scheme")
+
+// Factory creates a Source from its URL.
+type Factory func(u *url.URL) (Source, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a backend available for the URL scheme, typically called from the init()
+// of the backend's package. It panics if the scheme is already registered or factory is nil.
+func Register(scheme string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if factory == nil {
+		panic("dflag: source Register factory is nil for " + scheme)
+	}
+	if _, dup := registry[scheme]; dup {
+		panic("dflag: source Register called twice for " + scheme)
+	}
+	registry[scheme] = factory
+}
+
+// Schemes returns the sorted list of registered schemes.
+func Schemes() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	res := make([]string, 0, len(registry))
+	for scheme := range registry {
+		res = append(res, scheme)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Open creates the Source for the URL using the backend registered for its scheme.
+func Open(rawURL string) (Source, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	registryMu.RLock()
+	factory := registry[u.Scheme]
+	registryMu.RUnlock()
+	if factory == nil {
+		return nil, fmt.Errorf("dflag: %w %q (missing import?)", ErrUnknownScheme, u.Scheme)
+	}
+	return factory(u)
+}
+
+// Setup is a combination/shortcut for Open+Apply.
+func Setup(ctx context.Context, flagSet *flag.FlagSet, rawURL string) (*Applier, error) {
+	src, err := Open(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return Apply(ctx, flagSet, src)
+}
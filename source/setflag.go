@@ -0,0 +1,149 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package source
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"fortio.org/dflag"
+	"fortio.org/dflag/dynloglevel"
+)
+
+var (
+	// ErrFlagNotDynamic is returned when trying to update a static flag after initialization.
+	ErrFlagNotDynamic = errors.New("flag is not dynamic")
+	// ErrFlagNotFound is returned for values of unknown flags.
+	ErrFlagNotFound = errors.New("flag not found")
+)
+
+// SetFlag sets the named flag from raw content the same way for all sources of values
+// (files of a directory, the Kubernetes API, Vault,...): binary flags get the content as is,
+// other flags go through flagSet.Set (which marks them as set).
+// When dynamicOnly is true, static flags are rejected with ErrFlagNotDynamic.
+// The updates are logged through fortio.org/log, see SetFlagWithLogger.
+func SetFlag(flagSet *flag.FlagSet, name string, content []byte, dynamicOnly bool) error {
+	return SetFlagWithLogger(dynloglevel.FortioLogger(), flagSet, name, content, dynamicOnly)
+}
+
+// SetFlagWithLogger is SetFlag logging the updates to logger (e.g. a *slog.Logger).
+func SetFlagWithLogger(logger dflag.Logger, flagSet *flag.FlagSet, name string, content []byte, dynamicOnly bool) error {
+	f := flagSet.Lookup(name)
+	if f == nil {
+		return ErrFlagNotFound
+	}
+	if dynamicOnly && !dflag.IsFlagDynamic(f) {
+		return ErrFlagNotDynamic
+	}
+	if v := dflag.IsBinary(f); v != nil {
+		logger.Info("dflag: updating binary flag to new blob", "flag", name, "len", len(content))
+		return v.SetV(content)
+	}
+	logger.Info("dflag: updating flag", "flag", name, "value", dflag.Redact(f, string(content)))
+	// do not call flag.Value.Set, instead go through flagSet.Set to change "changed" state.
+	return flagSet.Set(name, string(content))
+}
+
+// SetFlagFor is SetFlag for the tenant's overlay value of the named dynamic flag (see dflag.GetFor).
+func SetFlagFor(flagSet *flag.FlagSet, tenant, name string, content []byte) error {
+	return setFlagFor(dynloglevel.FortioLogger(), flagSet, tenant, name, content)
+}
+
+func setFlagFor(logger dflag.Logger, flagSet *flag.FlagSet, tenant, name string, content []byte) error {
+	f := flagSet.Lookup(name)
+	if f == nil {
+		return ErrFlagNotFound
+	}
+	if !dflag.IsFlagDynamic(f) {
+		return ErrFlagNotDynamic
+	}
+	if v := dflag.IsBinary(f); v != nil {
+		logger.Info("dflag: updating binary flag to new blob", "flag", name, "tenant", tenant, "len", len(content))
+		return v.SetVFor(tenant, content)
+	}
+	logger.Info("dflag: updating flag", "flag", name, "tenant", tenant, "value", dflag.Redact(f, string(content)))
+	return dflag.SetFlagFor(flagSet, tenant, name, string(content))
+}
+
+// SetFlags sets all the flags from the name to content map using SetFlag (in name order, flags
+// declared with WithApplyAfter after their dependencies).
+// Unknown flags are logged as warnings, static flags skipped when dynamicOnly is true
+// and other errors are aggregated in the returned error.
+func SetFlags(flagSet *flag.FlagSet, values map[string][]byte, dynamicOnly bool) error {
+	return SetFlagsWithLogger(dynloglevel.FortioLogger(), flagSet, values, dynamicOnly)
+}
+
+// SetFlagsWithLogger is SetFlags logging to logger (e.g. a *slog.Logger).
+func SetFlagsWithLogger(logger dflag.Logger, flagSet *flag.FlagSet, values map[string][]byte, dynamicOnly bool) error {
+	return setFlags(logger, flagSet, "", values, dynamicOnly, func(string) {})
+}
+
+// setFlags is SetFlags (SetFlagFor when tenant isn't empty) calling handled for each value that doesn't
+// need to be set again: set successfully, for an unknown flag or skipped static flag.
+func setFlags(logger dflag.Logger, flagSet *flag.FlagSet, tenant string, values map[string][]byte, dynamicOnly bool, handled func(name string)) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	errorStrings := []string{}
+	for _, name := range dflag.ApplyOrder(names, flagSet.Lookup) {
+		var err error
+		if tenant == "" {
+			err = SetFlagWithLogger(logger, flagSet, name, values[name], dynamicOnly)
+		} else {
+			err = setFlagFor(logger, flagSet, tenant, name, values[name])
+		}
+		switch {
+		case errors.Is(err, ErrFlagNotFound):
+			logger.Warn("config value for unknown flag", "flag", name)
+		case errors.Is(err, ErrFlagNotDynamic) && dynamicOnly:
+		case err != nil:
+			errorStrings = append(errorStrings, fmt.Sprintf("flag %v: %v", name, err.Error()))
+			continue
+		}
+		handled(name)
+	}
+	if len(errorStrings) > 0 {
+		return fmt.Errorf("encountered %d errors while setting flags\n  %v",
+			len(errorStrings), strings.Join(errorStrings, "\n"))
+	}
+	return nil
+}
+
+// SetFlagValues is SetFlags for typed values, set on dynamic flags with dflag.SetFlagValue (skipping
+// their parsing): e.g. for a source whose values are already decoded. Unknown flags are logged as
+// warnings and other errors (static flags, values of the wrong type, validation) aggregated in the
+// returned error.
+func SetFlagValues(flagSet *flag.FlagSet, values map[string]interface{}) error {
+	return setFlagValues(dynloglevel.FortioLogger(), flagSet, values)
+}
+
+func setFlagValues(logger dflag.Logger, flagSet *flag.FlagSet, values map[string]interface{}) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	errorStrings := []string{}
+	for _, name := range dflag.ApplyOrder(names, flagSet.Lookup) {
+		err := dflag.SetFlagValue(flagSet, name, values[name])
+		switch {
+		case errors.Is(err, dflag.ErrFlagNotFound):
+			logger.Warn("config value for unknown flag", "flag", name)
+		case err != nil:
+			errorStrings = append(errorStrings, fmt.Sprintf("flag %v: %v", name, err.Error()))
+		default:
+			logger.Debug("dflag: updating flag to a typed value", "flag", name)
+		}
+	}
+	if len(errorStrings) > 0 {
+		return fmt.Errorf("encountered %d errors while setting flags\n  %v",
+			len(errorStrings), strings.Join(errorStrings, "\n"))
+	}
+	return nil
+}
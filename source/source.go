@@ -0,0 +1,233 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Package source defines the Source interface implemented by the remote flag value backends
+// (redis, httppoll, objstore, natskv, configmap, configmap/kubeapi,...), the Applier engine setting their
+// values on a FlagSet, and a registry of backends by URL scheme so third party backends can be
+// plugged in without forking the updating logic:
+//
+//	import _ "fortio.org/dflag/redis" // registers redis:// and rediss://
+//	...
+//	a, err := source.Setup(ctx, flag.CommandLine, "redis://localhost:6379/0?key=dflag")
+//	defer a.Stop()
+package source
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"sync"
+
+	"fortio.org/dflag"
+	"fortio.org/dflag/dynloglevel"
+)
+
+// Update is a flag value change yielded by a Source.
+type Update struct {
+	Name  string
+	Value []byte
+	// Deleted is set when the value was removed from the source, the flag is left unchanged.
+	Deleted bool
+	// Typed, when not nil, is the value already of the flag's type (e.g. an int64 for a DynInt64 flag),
+	// for sources decoding their values themselves: it's set without parsing (see dflag.SetFlagValue),
+	// Value is ignored and the update is never skipped as unchanged.
+	Typed interface{}
+}
+
+// Source is a backend providing flag values.
+type Source interface {
+	// Initialize returns the current flag values, set on both static and dynamic flags.
+	Initialize(ctx context.Context) (map[string][]byte, error)
+	// Watch starts sending updates (set on dynamic flags only) until the context is done or
+	// Stop is called, it must not block. Implementations are expected to retry on errors and
+	// may re-send unchanged values (e.g. after re-reading a whole document): they are skipped.
+	Watch(ctx context.Context, updates chan<- Update) error
+	// Stop stops the watch and waits for its go routine(s) to exit; no updates are sent after it returns.
+	Stop()
+}
+
+// Send sends all the values as updates (in name order), for sources re-reading all their values
+// at once. Returns false if the context is done first.
+func Send(ctx context.Context, updates chan<- Update, values map[string][]byte) bool {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		select {
+		case updates <- Update{Name: name, Value: values[name]}:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+// Applier sets the values from a Source on a FlagSet, skipping unchanged values.
+type Applier struct {
+	flagSet *flag.FlagSet
+	src     Source
+	mu      sync.Mutex
+	// last seen values, to only set flags whose values changed.
+	values map[string][]byte
+	// tenant whose overlay values are set, empty for the flags' values, see WithTenant.
+	tenant string
+	// logger gets the updates and errors logs, see WithLogger.
+	logger dflag.Logger
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewApplier creates an Applier of the src values to the flagSet. It logs to the source's logger
+// when it has one (e.g. the configmap Source's Updater, see LoggerProvider), fortio.org/log otherwise.
+func NewApplier(flagSet *flag.FlagSet, src Source) *Applier {
+	a := &Applier{flagSet: flagSet, src: src, values: make(map[string][]byte), logger: dynloglevel.FortioLogger()}
+	if p, ok := src.(LoggerProvider); ok {
+		a.logger = p.Logger()
+	}
+	return a
+}
+
+// LoggerProvider is optionally implemented by the sources configured with a logger, for their Appliers
+// to log to it too.
+type LoggerProvider interface {
+	Logger() dflag.Logger
+}
+
+// WithLogger sends the Applier's logs (flag updates, errors) to logger (e.g. a *slog.Logger) instead of
+// fortio.org/log, nil restores the default. Must be called before Initialize.
+func (a *Applier) WithLogger(logger dflag.Logger) *Applier {
+	if logger == nil {
+		logger = dynloglevel.FortioLogger()
+	}
+	a.logger = logger
+	return a
+}
+
+// WithTenant makes the Applier set the source's values as the tenant's overlay values of the
+// dynamic flags (see dflag.GetFor) and values removed from the source remove the overlay values.
+// Must be called before Initialize.
+func (a *Applier) WithTenant(tenant string) *Applier {
+	a.tenant = tenant
+	return a
+}
+
+// Apply is a combination/shortcut for NewApplier+Initialize+Start.
+func Apply(ctx context.Context, flagSet *flag.FlagSet, src Source) (*Applier, error) {
+	a := NewApplier(flagSet, src)
+	if err := a.Initialize(ctx); err != nil {
+		return nil, err
+	}
+	if err := a.Start(ctx); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Source returns the underlying Source.
+func (a *Applier) Source() Source {
+	return a.src
+}
+
+// Initialize gets the current values from the source and sets both static and dynamic flags.
+func (a *Applier) Initialize(ctx context.Context) error {
+	values, err := a.src.Initialize(ctx)
+	if err != nil {
+		return err
+	}
+	return a.apply(values, false)
+}
+
+// Start starts the source's watch and the go routine setting the updates on dynamic flags.
+// It stops when the context is done or Stop() is called.
+func (a *Applier) Start(ctx context.Context) error {
+	if a.done != nil {
+		return errors.New("dflag: source already started")
+	}
+	ctx, a.cancel = context.WithCancel(ctx)
+	updates := make(chan Update, 16)
+	if err := a.src.Watch(ctx, updates); err != nil {
+		a.cancel()
+		return err
+	}
+	a.done = make(chan struct{})
+	go a.applyLoop(ctx, updates)
+	return nil
+}
+
+// Stop stops the source's watch and the applying go routine.
+func (a *Applier) Stop() {
+	if a.done == nil {
+		return
+	}
+	a.cancel()
+	a.src.Stop()
+	<-a.done
+	a.done = nil
+}
+
+func (a *Applier) applyLoop(ctx context.Context, updates <-chan Update) {
+	defer close(a.done)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case u := <-updates:
+			if u.Deleted {
+				a.mu.Lock()
+				delete(a.values, u.Name)
+				a.mu.Unlock()
+				if a.tenant == "" {
+					a.logger.Info("dflag: flag removed from source, left unchanged", "flag", u.Name)
+					continue
+				}
+				a.logger.Info("dflag: flag removed from source, removing the tenant's overlay value", "flag", u.Name,
+					"tenant", a.tenant)
+				if err := dflag.ResetFlagFor(a.flagSet, a.tenant, u.Name); err != nil {
+					a.logger.Error("dflag: failed removing overlay value", "flag", u.Name, "tenant", a.tenant, "error", err)
+				}
+				continue
+			}
+			if u.Typed != nil {
+				if err := a.applyTyped(u.Name, u.Typed); err != nil {
+					a.logger.Error("dflag: failed setting flag", "flag", u.Name, "error", err)
+				}
+				continue
+			}
+			if err := a.apply(map[string][]byte{u.Name: u.Value}, true); err != nil {
+				a.logger.Error("dflag: failed setting flag", "flag", u.Name, "error", err)
+			}
+		}
+	}
+}
+
+// apply sets the flags whose values changed since last seen. Values that failed to be set
+// (e.g. rejected by a validator) aren't recorded as seen, so they are retried when sent again.
+func (a *Applier) apply(values map[string][]byte, dynamicOnly bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	changed := make(map[string][]byte)
+	for name, content := range values {
+		if old, found := a.values[name]; !found || !bytes.Equal(old, content) {
+			changed[name] = content
+		}
+	}
+	return setFlags(a.logger, a.flagSet, a.tenant, changed, dynamicOnly, func(name string) {
+		a.values[name] = changed[name]
+	})
+}
+
+// applyTyped sets the typed value of an Update, forgetting the flag's last seen value.
+func (a *Applier) applyTyped(name string, value interface{}) error {
+	if a.tenant != "" {
+		return fmt.Errorf("typed update of %q not supported for the tenant %q", name, a.tenant)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.values, name)
+	return setFlagValues(a.logger, a.flagSet, map[string]interface{}{name: value})
+}
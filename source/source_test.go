@@ -0,0 +1,142 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package source
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+)
+
+// chanSource is a Source whose updates are pushed by the test.
+type chanSource struct {
+	initial map[string][]byte
+	updates chan<- Update
+	stopped atomic.Bool
+}
+
+func (c *chanSource) Initialize(_ context.Context) (map[string][]byte, error) {
+	return c.initial, nil
+}
+
+func (c *chanSource) Watch(_ context.Context, updates chan<- Update) error {
+	c.updates = updates
+	return nil
+}
+
+func (c *chanSource) Stop() {
+	c.stopped.Store(true)
+}
+
+func TestApplier(t *testing.T) {
+	fs := flag.NewFlagSet("source_test", flag.ContinueOnError)
+	var sets atomic.Int32
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing").WithSyncNotifier(func(_, _ int64) {
+		sets.Add(1)
+	})
+	staticInt := fs.Int("some_int", 1, "static int for testing")
+	src := &chanSource{initial: map[string][]byte{"some_dynint": []byte("5"), "some_int": []byte("6")}}
+	a, err := Apply(context.Background(), fs, src)
+	assert.NoError(t, err)
+	assert.Equal(t, src, a.Source())
+	assert.Equal(t, int64(5), dynInt.Get())
+	assert.Equal(t, 6, *staticInt)
+	ctx := context.Background()
+	assert.True(t, Send(ctx, src.updates, map[string][]byte{"some_dynint": []byte("5"), "some_int": []byte("99")}))
+	src.updates <- Update{Name: "some_dynint", Value: []byte("7")}
+	src.updates <- Update{Name: "some_dynint", Value: []byte("7")}
+	src.updates <- Update{Name: "some_dynint", Deleted: true}
+	src.updates <- Update{Name: "some_dynint", Value: []byte("7")} // sets again (after delete) but same value.
+	src.updates <- Update{Name: "some_dynint", Value: []byte("8")}
+	for i := 0; i < 100 && dynInt.Get() != 8; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int64(8), dynInt.Get())
+	assert.Equal(t, 6, *staticInt, "static flags aren't changed by updates")
+	assert.Equal(t, int32(4), sets.Load(), "5, 7, 7 (after delete), 8")
+	a.Stop()
+	assert.True(t, src.stopped.Load())
+	a.Stop() // no-op
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.False(t, Send(cctx, make(chan Update), map[string][]byte{"a": nil}))
+}
+
+func TestApplierRetry(t *testing.T) {
+	fs := flag.NewFlagSet("source_test", flag.ContinueOnError)
+	var allowed atomic.Bool
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing").WithValidator(func(v int64) error {
+		if v > 10 && !allowed.Load() {
+			return errors.New("too big")
+		}
+		return nil
+	})
+	src := &chanSource{initial: map[string][]byte{"some_dynint": []byte("5")}}
+	a := NewApplier(fs, src)
+	assert.NoError(t, a.Initialize(context.Background()))
+	assert.Error(t, a.apply(map[string][]byte{"some_dynint": []byte("42")}, true))
+	assert.Equal(t, int64(5), dynInt.Get())
+	allowed.Store(true)
+	// the rejected value wasn't recorded as seen so it's set when sent again.
+	assert.NoError(t, a.apply(map[string][]byte{"some_dynint": []byte("42")}, true))
+	assert.Equal(t, int64(42), dynInt.Get())
+}
+
+func TestApplierTyped(t *testing.T) {
+	fs := flag.NewFlagSet("source_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	fs.Int("some_int", 1, "static int for testing")
+	src := &chanSource{initial: map[string][]byte{"some_dynint": []byte("5")}}
+	a, err := Apply(context.Background(), fs, src)
+	assert.NoError(t, err)
+	defer a.Stop()
+	src.updates <- Update{Name: "some_dynint", Typed: int64(6)}
+	src.updates <- Update{Name: "some_dynint", Value: []byte("5")} // not skipped: the typed set forgot the last seen value.
+	src.updates <- Update{Name: "some_dynint", Typed: int64(7)}
+	for i := 0; i < 100 && dynInt.Get() != 7; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int64(7), dynInt.Get())
+	assert.Equal(t, uint64(4), dynInt.Generation(), "5, 6, 5, 7")
+	err = SetFlagValues(fs, map[string]interface{}{
+		"some_dynint": int64(8), "some_int": 2, "not_a_flag": 3,
+	})
+	assert.Error(t, err, "static flag")
+	assert.Equal(t, int64(8), dynInt.Get())
+	assert.Error(t, SetFlagValues(fs, map[string]interface{}{"some_dynint": "9"}), "string isn't an int64")
+	assert.Equal(t, int64(8), dynInt.Get())
+}
+
+var gotURL *url.URL
+
+func init() {
+	Register("test", func(u *url.URL) (Source, error) {
+		gotURL = u
+		return &chanSource{initial: map[string][]byte{"some_dynint": []byte("42")}}, nil
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	assert.Equal(t, []string{"test"}, Schemes())
+	fs := flag.NewFlagSet("source_test", flag.ContinueOnError)
+	dynInt := dflag.DynInt64(fs, "some_dynint", 1, "dynamic int for testing")
+	a, err := Setup(context.Background(), fs, "test://host/path?x=y")
+	assert.NoError(t, err)
+	defer a.Stop()
+	assert.Equal(t, int64(42), dynInt.Get())
+	assert.Equal(t, "host", gotURL.Host)
+	_, err = Open("nope://x")
+	assert.True(t, errors.Is(err, ErrUnknownScheme), "unknown scheme error expected")
+	defer func() {
+		assert.True(t, recover() != nil, "duplicate Register should panic")
+	}()
+	Register("test", func(u *url.URL) (Source, error) { return nil, nil })
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// DynamicFlagTenants is implemented by the dynamic flags to hold per tenant (customer, region,...) overlay
+// values on top of their (base) value, see GetFor.
+type DynamicFlagTenants interface {
+	SetFor(tenant, rawInput string) error
+	ResetFor(tenant string)
+	Tenants() []string
+}
+
+// GetFor returns the tenant's overlay value if it has one, the flag's value (Get()) otherwise.
+func (d *DynValue[T]) GetFor(tenant string) T {
+	if m := d.tenants.Load(); m != nil {
+		if v, found := (*m)[tenant]; found {
+			if d.copier != nil {
+				return d.copier(v)
+			}
+			return v
+		}
+	}
+	return d.Get()
+}
+
+// SetFor sets the tenant's overlay value from a string, parsed like Set (the input mutator, mutator and
+// validator apply). Notifiers are only called for the base value changes.
+func (d *DynValue[T]) SetFor(tenant, rawInput string) error {
+	input := rawInput
+	if d.inpMutator != nil {
+		input = d.inpMutator(rawInput)
+	}
+	if d.sensitive && input == Redacted {
+		return d.setError(ErrRedactedValue)
+	}
+	val, err := parse[T](input)
+	if err != nil {
+		return d.setError(d.redactError(err, rawInput, input))
+	}
+	return d.SetVFor(tenant, val)
+}
+
+// SetFor sets the tenant's overlay value from JSON.
+func (d *DynJSONValue) SetFor(tenant, rawInput string) error {
+	input := rawInput
+	if d.inpMutator != nil {
+		input = d.inpMutator(rawInput)
+	}
+	val := reflect.New(d.structType).Interface()
+	if err := unmarshalValue(input, val); err != nil {
+		return d.setError(err)
+	}
+	return d.SetVFor(tenant, val)
+}
+
+// SetVFor is SetFor for an already typed value.
+func (d *DynValue[T]) SetVFor(tenant string, val T) error {
+	if d.mutator != nil {
+		val = d.mutator(val)
+	}
+	if d.validator != nil {
+		if err := d.validator(val); err != nil {
+			return d.setError(d.redactValueError(err, val))
+		}
+	}
+	d.updateTenants(func(m map[string]T) { m[tenant] = val })
+	if d.metrics != nil {
+		d.metrics.IncSetSuccess(d.flagName)
+	}
+	return nil
+}
+
+// ResetFor removes the tenant's overlay value, GetFor then returns the flag's value.
+func (d *DynValue[T]) ResetFor(tenant string) {
+	d.updateTenants(func(m map[string]T) { delete(m, tenant) })
+}
+
+// Tenants returns the sorted names of the tenants with an overlay value.
+func (d *DynValue[T]) Tenants() []string {
+	m := d.tenants.Load()
+	if m == nil {
+		return nil
+	}
+	res := make([]string, 0, len(*m))
+	for tenant := range *m {
+		res = append(res, tenant)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// updateTenants applies change to a copy of the overlay values and stores it, so GetFor doesn't lock.
+func (d *DynValue[T]) updateTenants(change func(m map[string]T)) {
+	d.tenantsMu.Lock()
+	defer d.tenantsMu.Unlock()
+	m := make(map[string]T)
+	if old := d.tenants.Load(); old != nil {
+		for k, v := range *old {
+			m[k] = v
+		}
+	}
+	change(m)
+	d.tenants.Store(&m)
+}
+
+// SetFlagFor sets the tenant's overlay value of the named dynamic flag of flagSet from a string.
+// The error wraps ErrFlagNotFound or ErrFlagNotDynamic when the flag can't have overlays.
+func SetFlagFor(flagSet *flag.FlagSet, tenant, name, input string) error {
+	t, err := lookupTenants(flagSet, name)
+	if err != nil {
+		return err
+	}
+	return t.SetFor(tenant, input)
+}
+
+// ResetFlagFor removes the tenant's overlay value of the named dynamic flag of flagSet.
+func ResetFlagFor(flagSet *flag.FlagSet, tenant, name string) error {
+	t, err := lookupTenants(flagSet, name)
+	if err != nil {
+		return err
+	}
+	t.ResetFor(tenant)
+	return nil
+}
+
+func lookupTenants(flagSet *flag.FlagSet, name string) (DynamicFlagTenants, error) {
+	f := flagSet.Lookup(name)
+	if f == nil {
+		return nil, fmt.Errorf("%w: %q", ErrFlagNotFound, name)
+	}
+	t, ok := f.Value.(DynamicFlagTenants)
+	if !ok || !IsFlagDynamic(f) {
+		return nil, fmt.Errorf("%w: %q", ErrFlagNotDynamic, name)
+	}
+	return t, nil
+}
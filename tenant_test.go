@@ -0,0 +1,43 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"errors"
+	"flag"
+	"testing"
+
+	"fortio.org/assert"
+)
+
+func TestGetFor(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := DynInt64(set, "some_int", 10, "").WithValidator(ValidateRange[int64](0, 100))
+	slice := DynStringSlice(set, "some_slice", []string{"a"}, "")
+	js := DynJSON(set, "some_json", &outerJSON{FieldString: "base"}, "")
+	set.Int("static_int", 1, "")
+	assert.Equal(t, int64(10), dynInt.GetFor("acme"))
+	assert.NoError(t, SetFlagFor(set, "acme", "some_int", " 20 "))
+	assert.Error(t, SetFlagFor(set, "acme", "some_int", "200"), "validator applies")
+	assert.Error(t, SetFlagFor(set, "acme", "some_int", "x"))
+	assert.NoError(t, dynInt.SetV(30))
+	assert.Equal(t, int64(20), dynInt.GetFor("acme"))
+	assert.Equal(t, int64(30), dynInt.GetFor("other"), "base value")
+	assert.Equal(t, uint64(1), dynInt.Generation(), "overlays aren't base sets")
+	assert.NoError(t, dynInt.SetVFor("beta", 40))
+	assert.Equal(t, []string{"acme", "beta"}, dynInt.Tenants())
+	assert.NoError(t, ResetFlagFor(set, "acme", "some_int"))
+	assert.Equal(t, int64(30), dynInt.GetFor("acme"))
+	assert.Equal(t, []string{"beta"}, dynInt.Tenants())
+	assert.NoError(t, slice.SetFor("acme", "x,y"))
+	v := slice.GetFor("acme")
+	v[0] = "changed"
+	assert.Equal(t, []string{"x", "y"}, slice.GetFor("acme"), "copy on get")
+	assert.NoError(t, SetFlagFor(set, "acme", "some_json", `{"string": "acme"}`))
+	assert.Equal(t, "acme", js.GetFor("acme").(*outerJSON).FieldString)
+	assert.Equal(t, "base", js.GetFor("other").(*outerJSON).FieldString)
+	assert.True(t, errors.Is(SetFlagFor(set, "acme", "static_int", "2"), ErrFlagNotDynamic))
+	assert.True(t, errors.Is(ResetFlagFor(set, "acme", "missing"), ErrFlagNotFound))
+	assert.Equal(t, []string{"acme"}, slice.Tenants())
+}
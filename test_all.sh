@@ -12,3 +12,12 @@ for d in $(go list ./... | grep -v vendor); do
     fi
     echo ""
 done
+
+echo -e "TESTS FOR: \033[0;35mconfigmap\033[0m with the no_fsnotify (polling) build tag"
+go test -race -tags no_fsnotify ./configmap/
+
+# Sub modules (backends with heavier dependencies).
+for m in $(find . -mindepth 2 -name go.mod -exec dirname {} \; | sort); do
+    echo -e "TESTS FOR: \033[0;35m${m}\033[0m module"
+    (cd "$m" && go test -race ./...)
+done
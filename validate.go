@@ -0,0 +1,37 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+)
+
+// ErrNotValidatable is returned by ValidateFlag for flags whose value can't be checked without setting it.
+var ErrNotValidatable = errors.New("flag value can't be validated without setting it")
+
+// ValidateFlag checks, without any side effect, whether input would be accepted by Set() on the flag.
+// Dynamic flags run their input mutator, parsing and validator. Standard library flags of basic
+// types (bool, int, string, time.Duration...) are parsed on a scratch copy. Other flags
+// (e.g. flag.Func) return ErrNotValidatable.
+func ValidateFlag(f *flag.Flag, input string) error {
+	if dv, ok := f.Value.(DynamicFlagValidator); ok {
+		return dv.ValidateInput(input)
+	}
+	v := reflect.ValueOf(f.Value)
+	if v.Kind() != reflect.Ptr {
+		return ErrNotValidatable
+	}
+	switch v.Type().Elem().Kind() { //nolint:exhaustive // only basic types are supported
+	case reflect.Bool, reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Float64, reflect.String:
+		scratch, ok := reflect.New(v.Type().Elem()).Interface().(flag.Value)
+		if !ok {
+			return ErrNotValidatable
+		}
+		return scratch.Set(input)
+	default:
+		return ErrNotValidatable
+	}
+}
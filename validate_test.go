@@ -0,0 +1,35 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"errors"
+	"flag"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+)
+
+func TestValidateFlag(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	dynInt := DynInt64(set, "dynint", 5, "some dyn int").WithValidator(ValidateDynInt64Range(1, 10))
+	set.Int("staticint", 3, "some static int")
+	set.Duration("staticdur", time.Second, "some static duration")
+	set.Func("funcflag", "some func flag", func(string) error { return nil })
+	dynJSON := DynJSON(set, "dynjson", &outerJSON{FieldInts: []int{1}}, "some json")
+	assert.NoError(t, ValidateFlag(set.Lookup("dynint"), " 7\n"))
+	assert.Error(t, ValidateFlag(set.Lookup("dynint"), "11"))
+	assert.Error(t, ValidateFlag(set.Lookup("dynint"), "abc"))
+	assert.Equal(t, int64(5), dynInt.Get(), "validation must not change the value")
+	assert.NoError(t, ValidateFlag(set.Lookup("staticint"), "42"))
+	assert.Error(t, ValidateFlag(set.Lookup("staticint"), "x42"))
+	assert.Equal(t, "3", set.Lookup("staticint").Value.String(), "validation must not change the value")
+	assert.NoError(t, ValidateFlag(set.Lookup("staticdur"), "3m"))
+	assert.Error(t, ValidateFlag(set.Lookup("staticdur"), "3"))
+	assert.True(t, errors.Is(ValidateFlag(set.Lookup("funcflag"), "x"), ErrNotValidatable))
+	assert.NoError(t, ValidateFlag(set.Lookup("dynjson"), `{"ints": [2]}`))
+	assert.Error(t, ValidateFlag(set.Lookup("dynjson"), `{"ints": "x"}`))
+	assert.Equal(t, []int{1}, dynJSON.Get().(*outerJSON).FieldInts)
+}
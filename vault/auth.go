@@ -0,0 +1,83 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// DefaultServiceAccountTokenPath is where Kubernetes mounts the pod's service account token.
+const DefaultServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token" //nolint:gosec // a path
+
+// AppRoleAuth is the AppRole auth method, for machines and applications.
+type AppRoleAuth struct {
+	// RoleID of the role.
+	RoleID string
+	// SecretID of the role, or SecretIDFile to read it from at each login.
+	SecretID     string
+	SecretIDFile string
+	// MountPath of the auth method, defaults to "approle".
+	MountPath string
+}
+
+// Login implements api.AuthMethod.
+func (a *AppRoleAuth) Login(ctx context.Context, client *api.Client) (*api.Secret, error) {
+	if a.RoleID == "" {
+		return nil, errors.New("AppRole RoleID is required")
+	}
+	secretID, err := valueOrFile(a.SecretID, a.SecretIDFile)
+	if err != nil {
+		return nil, err
+	}
+	return client.Logical().WriteWithContext(ctx, "auth/"+orDefault(a.MountPath, "approle")+"/login",
+		map[string]interface{}{"role_id": a.RoleID, "secret_id": secretID})
+}
+
+// KubernetesAuth is the Kubernetes auth method, using the pod's service account token.
+type KubernetesAuth struct {
+	// Role to log in as.
+	Role string
+	// TokenPath of the service account token, defaults to DefaultServiceAccountTokenPath.
+	// It is read at each login as it's rotated by Kubernetes.
+	TokenPath string
+	// MountPath of the auth method, defaults to "kubernetes".
+	MountPath string
+}
+
+// Login implements api.AuthMethod.
+func (k *KubernetesAuth) Login(ctx context.Context, client *api.Client) (*api.Secret, error) {
+	if k.Role == "" {
+		return nil, errors.New("kubernetes auth Role is required")
+	}
+	jwt, err := valueOrFile("", orDefault(k.TokenPath, DefaultServiceAccountTokenPath))
+	if err != nil {
+		return nil, err
+	}
+	return client.Logical().WriteWithContext(ctx, "auth/"+orDefault(k.MountPath, "kubernetes")+"/login",
+		map[string]interface{}{"role": k.Role, "jwt": jwt})
+}
+
+func orDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+// valueOrFile returns the value, or the trimmed content of the file if set.
+func valueOrFile(value, file string) (string, error) {
+	if file == "" {
+		return value, nil
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,310 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+// Package vault reads secrets from HashiCorp Vault into designated (dynamic []byte or
+// string) flags and keeps them fresh: leases are renewed by the official client's LifetimeWatcher
+// and the secret is read again when the lease can't be renewed anymore (max TTL reached,
+// revoked,...) or, for secrets without lease like KV ones, periodically; so rotating credentials
+// propagate without restarts. Both KV v2 (data nested under `data`) and other secret engines
+// (e.g. database/creds/role) responses are supported. Authentication is either the client's token
+// (VAULT_TOKEN, or VAULT_AGENT_ADDR to go through a Vault agent's API proxy) or an auth method
+// (e.g. AppRoleAuth or KubernetesAuth) whose token is renewed, logging in again when needed.
+// It uses the github.com/hashicorp/vault/api client and is a separate module so the main dflag
+// module doesn't depend on it.
+// It implements source.Source, the values being set on the flags by a source.Applier (see Setup).
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"sync"
+	"time"
+
+	"fortio.org/dflag/source"
+	"fortio.org/log"
+	"github.com/hashicorp/vault/api"
+)
+
+const (
+	// DefaultRefreshInterval is how often secrets without lease are re-read when not specified in the Config.
+	DefaultRefreshInterval = 5 * time.Minute
+	// Initial and maximum delay between attempts after errors.
+	minBackoff = 1 * time.Second
+	maxBackoff = 30 * time.Second
+)
+
+// Secret maps one field of a Vault secret to a flag.
+type Secret struct {
+	// Flag name to set with the secret's value.
+	Flag string
+	// Path of the secret, e.g. "secret/data/myapp" (KV v2) or "database/creds/myrole".
+	Path string
+	// Field of the secret's data to use, e.g. "password". Non string values are JSON encoded.
+	Field string
+}
+
+// Config is the configuration of the Vault source. Only Secrets is required.
+type Config struct {
+	// Client to use, defaults to one configured from the environment (VAULT_ADDR, VAULT_TOKEN,
+	// VAULT_NAMESPACE, VAULT_CACERT,...).
+	Client *api.Client
+	// Auth method to log in with (e.g. AppRoleAuth or KubernetesAuth) instead of using the client's token.
+	Auth api.AuthMethod
+	// Secrets to read.
+	Secrets []Secret
+	// RefreshInterval for secrets without lease (e.g. KV), defaults to DefaultRefreshInterval.
+	RefreshInterval time.Duration
+}
+
+// Source is the Vault secrets source.
+type Source struct {
+	cfg Config
+	mu  sync.Mutex
+	// current secret (for its lease) and value of each Secret.
+	secrets []*api.Secret
+	values  [][]byte
+	// auth is the login secret, when using an auth method.
+	auth   *api.Secret
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a Vault source.
+func New(cfg Config) (*Source, error) {
+	for _, s := range cfg.Secrets {
+		if s.Flag == "" || s.Path == "" || s.Field == "" {
+			return nil, fmt.Errorf("dflag: vault secret %+v: Flag, Path and Field are required", s)
+		}
+	}
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = DefaultRefreshInterval
+	}
+	if cfg.Client == nil {
+		client, err := api.NewClient(api.DefaultConfig())
+		if err != nil {
+			return nil, fmt.Errorf("dflag: vault client: %w", err)
+		}
+		cfg.Client = client
+	}
+	n := len(cfg.Secrets)
+	return &Source{cfg: cfg, secrets: make([]*api.Secret, n), values: make([][]byte, n)}, nil
+}
+
+// Setup is a combination/shortcut for New+source.Apply: the secrets are read, setting both
+// static and dynamic flags, then kept fresh for dynamic flags.
+func Setup(ctx context.Context, flagSet *flag.FlagSet, cfg Config) (*source.Applier, error) {
+	s, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return source.Apply(ctx, flagSet, s)
+}
+
+// Initialize logs in (when using an auth method) and reads all the secrets, returning their values by flag name.
+func (s *Source) Initialize(ctx context.Context) (map[string][]byte, error) {
+	if s.cfg.Auth != nil {
+		if err := s.login(ctx); err != nil {
+			return nil, err
+		}
+	}
+	values := make(map[string][]byte, len(s.cfg.Secrets))
+	for i, sec := range s.cfg.Secrets {
+		value, _, err := s.read(ctx, i)
+		if err != nil {
+			return nil, err
+		}
+		values[sec.Flag] = value
+	}
+	return values, nil
+}
+
+// Watch kicks off one go routine per secret, renewing or re-reading it as needed and sending
+// the changed values, and one keeping the login token valid when using an auth method;
+// until the context is done or Stop() is called.
+func (s *Source) Watch(ctx context.Context, updates chan<- source.Update) error {
+	if s.cancel != nil {
+		return errors.New("dflag: vault source already started")
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	if s.cfg.Auth != nil {
+		s.wg.Add(1)
+		go s.authLoop(ctx)
+	}
+	for i := range s.cfg.Secrets {
+		s.wg.Add(1)
+		go s.refreshLoop(ctx, i, updates)
+	}
+	return nil
+}
+
+// Stop stops the go routines and waits for them to exit. Leases are left to expire.
+func (s *Source) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+	s.cancel = nil
+}
+
+// login logs in with the auth method, which sets the client's token.
+func (s *Source) login(ctx context.Context) error {
+	auth, err := s.cfg.Client.Auth().Login(ctx, s.cfg.Auth)
+	if err != nil {
+		return fmt.Errorf("dflag: vault login: %w", err)
+	}
+	s.mu.Lock()
+	s.auth = auth
+	s.mu.Unlock()
+	return nil
+}
+
+// read reads the i-th secret, records it and returns its value and whether it changed.
+func (s *Source) read(ctx context.Context, i int) ([]byte, bool, error) {
+	sec := s.cfg.Secrets[i]
+	res, err := s.cfg.Client.Logical().ReadWithContext(ctx, sec.Path)
+	if err != nil {
+		return nil, false, fmt.Errorf("dflag: vault read %v: %w", sec.Path, err)
+	}
+	if res == nil {
+		return nil, false, fmt.Errorf("dflag: vault secret %v not found", sec.Path)
+	}
+	data := res.Data
+	if inner, ok := data["data"].(map[string]interface{}); ok && data["metadata"] != nil {
+		data = inner // KV v2
+	}
+	v, found := data[sec.Field]
+	if !found {
+		return nil, false, fmt.Errorf("dflag: vault secret %v has no field %q", sec.Path, sec.Field)
+	}
+	var value []byte
+	if str, ok := v.(string); ok {
+		value = []byte(str)
+	} else if value, err = json.Marshal(v); err != nil {
+		return nil, false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := !bytes.Equal(s.values[i], value)
+	s.secrets[i], s.values[i] = res, value
+	if changed {
+		log.Infof("dflag: vault secret %v updated, for flag %v", sec.Path, sec.Flag)
+	}
+	return value, changed, nil
+}
+
+// waitForExpiry keeps the secret's lease renewed (for renewable ones) and returns true once
+// it needs to be read (or logged in) again; false if the context is done first. Secrets without
+// lease are read again after the RefreshInterval.
+func (s *Source) waitForExpiry(ctx context.Context, secret *api.Secret, what string) bool {
+	leaseDuration := secret.LeaseDuration
+	if secret.Auth != nil {
+		leaseDuration = secret.Auth.LeaseDuration
+	}
+	if leaseDuration <= 0 {
+		return sleep(ctx, s.cfg.RefreshInterval)
+	}
+	watcher, err := s.cfg.Client.NewLifetimeWatcher(&api.LifetimeWatcherInput{Secret: secret})
+	if err != nil {
+		log.Errf("dflag: vault lifetime watcher for %v: %v", what, err)
+		return sleep(ctx, s.cfg.RefreshInterval)
+	}
+	go watcher.Start()
+	defer watcher.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case err := <-watcher.DoneCh():
+			if err != nil {
+				log.Warnf("dflag: vault renewing lease of %v: %v", what, err)
+			}
+			log.Infof("dflag: vault lease of %v can't be extended anymore, getting a new one", what)
+			return true
+		case r := <-watcher.RenewCh():
+			log.LogVf("dflag: vault renewed lease of %v at %v", what, r.RenewedAt)
+		}
+	}
+}
+
+// retry calls f until it succeeds, with exponential backoff; returns false if the context is done first.
+func retry(ctx context.Context, what string, f func() error) bool {
+	backoff := minBackoff
+	for {
+		err := f()
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		log.Errf("dflag: vault %v: %v, retrying in %v", what, err, backoff)
+		if !sleep(ctx, backoff) {
+			return false
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// authLoop keeps the login token renewed and logs in again when it can't be renewed anymore.
+func (s *Source) authLoop(ctx context.Context) {
+	defer s.wg.Done()
+	for {
+		s.mu.Lock()
+		auth := s.auth
+		s.mu.Unlock()
+		if !s.waitForExpiry(ctx, auth, "login token") {
+			return
+		}
+		if !retry(ctx, "login", func() error { return s.login(ctx) }) {
+			return
+		}
+	}
+}
+
+func (s *Source) refreshLoop(ctx context.Context, i int, updates chan<- source.Update) {
+	defer s.wg.Done()
+	sec := s.cfg.Secrets[i]
+	for {
+		s.mu.Lock()
+		secret := s.secrets[i]
+		s.mu.Unlock()
+		if !s.waitForExpiry(ctx, secret, sec.Path) {
+			return
+		}
+		var value []byte
+		var changed bool
+		if !retry(ctx, "secret "+sec.Path, func() (err error) {
+			value, changed, err = s.read(ctx, i)
+			return err
+		}) {
+			return
+		}
+		if !changed {
+			continue
+		}
+		select {
+		case updates <- source.Update{Name: sec.Flag, Value: value}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
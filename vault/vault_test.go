@@ -0,0 +1,161 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+	"fortio.org/dflag"
+	"fortio.org/dflag/source"
+	"github.com/hashicorp/vault/api"
+)
+
+// newTestClient returns a client for the fake Vault server.
+func newTestClient(t *testing.T, address, token string) *api.Client {
+	client, err := api.NewClient(&api.Config{Address: address, MaxRetries: 0})
+	assert.NoError(t, err)
+	client.SetToken(token)
+	return client
+}
+
+// requireToken wraps the handler with a check of the Vault token, except for logins.
+func requireToken(token string, mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != token && filepath.Base(r.URL.Path) != "login" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"errors": ["permission denied"]}`)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func waitFor(cond func() bool) {
+	for i := 0; i < 300 && !cond(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestVaultSource(t *testing.T) {
+	var reads, renewals atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/secret/data/app", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"lease_duration": 0, "data": {"data": {"api_key": "k1"}, "metadata": {"version": 1}}}`)
+	})
+	mux.HandleFunc("/v1/database/creds/role", func(w http.ResponseWriter, _ *http.Request) {
+		n := reads.Add(1)
+		fmt.Fprintf(w, `{"lease_id": "db/%d", "renewable": true, "lease_duration": 2,
+			"data": {"username": "u", "password": "p%d"}}`, n, n)
+	})
+	mux.HandleFunc("/v1/sys/leases/renew", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		var req struct {
+			LeaseID string `json:"lease_id"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		d := 2
+		if req.LeaseID == "db/1" && renewals.Add(1) >= 2 {
+			d = 0 // max TTL of the first lease reached
+		}
+		fmt.Fprintf(w, `{"lease_id": %q, "renewable": true, "lease_duration": %d}`, req.LeaseID, d)
+	})
+	srv := httptest.NewServer(requireToken("tok", mux))
+	defer srv.Close()
+
+	fs := flag.NewFlagSet("vault_test", flag.ContinueOnError)
+	password := dflag.Dyn(fs, "db_password", []byte{}, "dynamic secret for testing")
+	apiKey := dflag.DynString(fs, "api_key", "", "dynamic string secret for testing")
+	client := newTestClient(t, srv.URL, "tok")
+	_, err := New(Config{Client: client, Secrets: []Secret{{Flag: "nope", Path: "a"}}})
+	assert.Error(t, err, "field is required")
+	a, err := Setup(context.Background(), fs, Config{Client: client, Secrets: []Secret{
+		{Flag: "db_password", Path: "database/creds/role", Field: "password"},
+		{Flag: "api_key", Path: "secret/data/app", Field: "api_key"},
+	}})
+	assert.NoError(t, err)
+	defer a.Stop()
+	assert.Equal(t, "p1", string(password.Get()))
+	assert.Equal(t, "k1", apiKey.Get())
+	waitFor(func() bool { return string(password.Get()) == "p2" })
+	assert.Equal(t, "p2", string(password.Get()), "new secret read once the lease can't be renewed")
+	assert.Equal(t, int32(2), renewals.Load())
+}
+
+func TestVaultForbidden(t *testing.T) {
+	srv := httptest.NewServer(requireToken("tok", http.NewServeMux()))
+	defer srv.Close()
+	fs := flag.NewFlagSet("vault_test", flag.ContinueOnError)
+	dflag.DynString(fs, "api_key", "", "dynamic string secret for testing")
+	cfg := Config{Client: newTestClient(t, srv.URL, "bad"), Secrets: []Secret{{Flag: "api_key", Path: "secret/data/app", Field: "api_key"}}}
+	_, err := Setup(context.Background(), fs, cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "permission denied")
+}
+
+func TestVaultAuth(t *testing.T) {
+	var logins atomic.Int32
+	var loginBody atomic.Value
+	mux := http.NewServeMux()
+	login := func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		loginBody.Store(body)
+		logins.Add(1)
+		fmt.Fprint(w, `{"auth": {"client_token": "tok", "renewable": true, "lease_duration": 2}}`)
+	}
+	mux.HandleFunc("/v1/auth/approle/login", login)
+	mux.HandleFunc("/v1/auth/k8s/login", login)
+	mux.HandleFunc("/v1/auth/token/renew-self", func(w http.ResponseWriter, _ *http.Request) {
+		// can't be extended: logs in again.
+		fmt.Fprint(w, `{"auth": {"client_token": "tok", "renewable": true, "lease_duration": 0}}`)
+	})
+	mux.HandleFunc("/v1/secret/data/app", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"data": {"data": {"api_key": "k1"}, "metadata": {"version": 1}}}`)
+	})
+	srv := httptest.NewServer(requireToken("tok", mux))
+	defer srv.Close()
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenPath, []byte("jwt\n"), 0o600))
+	tests := []struct {
+		name string
+		auth api.AuthMethod
+		want map[string]interface{}
+	}{
+		{"approle", &AppRoleAuth{RoleID: "r", SecretID: "s"}, map[string]interface{}{"role_id": "r", "secret_id": "s"}},
+		{"kubernetes", &KubernetesAuth{Role: "app", TokenPath: tokenPath, MountPath: "k8s"}, map[string]interface{}{"role": "app", "jwt": "jwt"}},
+	}
+	for _, tt := range tests {
+		logins.Store(0)
+		fs := flag.NewFlagSet("vault_test", flag.ContinueOnError)
+		apiKey := dflag.DynString(fs, "api_key", "", "dynamic string secret for testing")
+		a, err := Setup(context.Background(), fs, Config{
+			Client:  newTestClient(t, srv.URL, ""),
+			Auth:    tt.auth,
+			Secrets: []Secret{{Flag: "api_key", Path: "secret/data/app", Field: "api_key"}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "k1", apiKey.Get())
+		assert.Equal(t, tt.want, loginBody.Load().(map[string]interface{}), tt.name)
+		waitFor(func() bool { return logins.Load() >= 2 })
+		assert.True(t, logins.Load() >= 2, tt.name+" should log in again once the token can't be renewed")
+		a.Stop()
+	}
+	_, err := (&KubernetesAuth{}).Login(context.Background(), newTestClient(t, srv.URL, ""))
+	assert.Error(t, err, "role is required")
+	_, err = (&AppRoleAuth{RoleID: "r", SecretIDFile: "/nonexistent"}).Login(context.Background(), newTestClient(t, srv.URL, ""))
+	assert.Error(t, err, "missing secret id file")
+}
+
+var _ source.Source = (*Source)(nil)
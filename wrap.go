@@ -0,0 +1,169 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// DynWrappedValue is a standard (e.g. third party library's) flag made dynamic by Wrap. Parsing is
+// delegated to the original flag.Value, and the value is the one returned by its Get() when it implements
+// flag.Getter (e.g. an int for flag.Int), the input string otherwise (e.g. for flag.Func).
+type DynWrappedValue struct {
+	DynValue[interface{}]
+	mu       sync.Mutex // serializes the changes of orig.
+	orig     flag.Value
+	defInput string
+}
+
+// wrappedBoolValue keeps the -name (without value) syntax of wrapped bool flags.
+type wrappedBoolValue struct {
+	DynamicBoolValueTag
+	*DynWrappedValue
+}
+
+// Wrap replaces the already defined standard flag name of flagSet by a dynamic one delegating the parsing
+// to (and still setting) the original flag.Value, so flags of libraries can be changed at runtime and
+// get validators, notifiers, history... Note that the library's own reads of its variable aren't
+// synchronized with the sets: it's safe when the library reads it once or through the flag's Get,
+// consumers of the change should use the returned value (Get, notifiers).
+func Wrap(flagSet *flag.FlagSet, name string) (*DynWrappedValue, error) {
+	f := flagSet.Lookup(name)
+	if f == nil {
+		return nil, fmt.Errorf("dflag: flag %q not found", name)
+	}
+	if IsFlagDynamic(f) {
+		return nil, fmt.Errorf("dflag: flag %q is already dynamic", name)
+	}
+	dynValue := &DynWrappedValue{orig: f.Value, defInput: f.DefValue}
+	dynInit(&dynValue.DynValue, wrappedValue(f.Value, f.DefValue), f.Usage)
+	dynValue.flagSet = flagSet
+	dynValue.flagName = name
+	if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+		f.Value = &wrappedBoolValue{DynWrappedValue: dynValue}
+	} else {
+		f.Value = dynValue
+	}
+	return dynValue, nil
+}
+
+func wrappedValue(v flag.Value, input string) interface{} {
+	if g, ok := v.(flag.Getter); ok {
+		return g.Get()
+	}
+	return input
+}
+
+// scratch returns a new value of the original's type when it's safe to parse into one, i.e. for the
+// standard library's basic types (pointers to bool, ints, floats, strings...), nil otherwise.
+func (d *DynWrappedValue) scratch() flag.Value {
+	t := reflect.TypeOf(d.orig)
+	if t.Kind() != reflect.Ptr {
+		return nil
+	}
+	switch t.Elem().Kind() { //nolint:exhaustive // only the basic kinds are safe to zero value.
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String:
+		v, _ := reflect.New(t.Elem()).Interface().(flag.Value)
+		return v
+	default:
+		return nil
+	}
+}
+
+// Set parses the input with the original flag.Value and, when the result passes the validator, keeps it
+// and calls the notifier (the original is reverted otherwise).
+func (d *DynWrappedValue) Set(rawInput string) error {
+	input := rawInput
+	if d.inpMutator != nil {
+		input = d.inpMutator(rawInput)
+	}
+	if d.sensitive && input == Redacted {
+		return d.setError(ErrRedactedValue)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.orig.(flag.Getter); !ok {
+		// The value is the input: validated before calling the original, which may not be revertible.
+		if err := d.ValidateV(input); err != nil {
+			return d.setError(err)
+		}
+		if err := d.orig.Set(input); err != nil {
+			return d.setError(d.redactError(err, rawInput, input))
+		}
+		return d.DynValue.SetV(input)
+	}
+	prev := d.orig.String()
+	if err := d.orig.Set(input); err != nil {
+		_ = d.orig.Set(prev) // e.g. the standard numeric flags are zeroed by parse errors.
+		return d.setError(d.redactError(err, rawInput, input))
+	}
+	if err := d.DynValue.SetV(wrappedValue(d.orig, input)); err != nil {
+		_ = d.orig.Set(prev)
+		return err
+	}
+	return nil
+}
+
+// SetV sets the value from its string representation, as the original flag.Value must parse it.
+func (d *DynWrappedValue) SetV(val interface{}) error {
+	return d.Set(valueString(val))
+}
+
+// ValidateInput checks whether Set(rawInput) would succeed. Only the validator checks the input of the
+// original flag.Values without flag.Getter (e.g. flag.Func ones), and ErrNotValidatable is returned for
+// the ones that can't be parsed on a scratch copy (e.g. flag.TextVar ones) as setting the original,
+// even briefly, would be seen by its readers.
+func (d *DynWrappedValue) ValidateInput(rawInput string) error {
+	input := rawInput
+	if d.inpMutator != nil {
+		input = d.inpMutator(rawInput)
+	}
+	if _, ok := d.orig.(flag.Getter); !ok {
+		return d.ValidateV(input)
+	}
+	if v := d.scratch(); v != nil {
+		if err := v.Set(input); err != nil {
+			return err
+		}
+		return d.ValidateV(wrappedValue(v, input))
+	}
+	return ErrNotValidatable
+}
+
+// Reset sets the flag back to its default value string.
+func (d *DynWrappedValue) Reset() error {
+	return d.Set(d.defInput)
+}
+
+// String returns the original flag.Value's string representation (the value for the ones without
+// flag.Getter, as e.g. flag.Func ones are always empty).
+func (d *DynWrappedValue) String() string {
+	if !d.ready {
+		return ""
+	}
+	if d.sensitive {
+		return Redacted
+	}
+	if _, ok := d.orig.(flag.Getter); !ok {
+		return valueString(d.Get())
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.orig.String()
+}
+
+// Type is an indicator of what this flag represents.
+func (d *DynWrappedValue) Type() string {
+	return fmt.Sprintf("dyn_%T", d.Get())
+}
+
+// Unwrap returns the original flag.Value.
+func (d *DynWrappedValue) Unwrap() flag.Value {
+	return d.orig
+}
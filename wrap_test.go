@@ -0,0 +1,107 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"errors"
+	"flag"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"fortio.org/assert"
+)
+
+func TestWrap(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	libInt := set.Int("lib-int", 5, "a library's int")
+	libDuration := set.Duration("lib-duration", time.Second, "a library's duration")
+	_, err := Wrap(set, "missing")
+	assert.Error(t, err)
+	dynInt, err := Wrap(set, "lib-int")
+	assert.NoError(t, err)
+	_, err = Wrap(set, "lib-int")
+	assert.Error(t, err, "already dynamic")
+	assert.True(t, IsFlagDynamic(set.Lookup("lib-int")), "wrapped flag is dynamic")
+	assert.Equal(t, 5, dynInt.Get())
+	assert.Equal(t, "dyn_int", dynInt.Type())
+	var notified []interface{}
+	dynInt.WithValidator(func(v interface{}) error {
+		if v.(int) < 0 {
+			return errors.New("must be positive")
+		}
+		return nil
+	}).WithSyncNotifier(func(_, newValue interface{}) {
+		notified = append(notified, newValue)
+	})
+	assert.NoError(t, set.Parse([]string{"-lib-int", " 42 "}))
+	assert.Equal(t, 42, *libInt, "original still set")
+	assert.Equal(t, 42, dynInt.Get())
+	assert.Equal(t, "42", set.Lookup("lib-int").Value.String())
+	assert.Error(t, set.Set("lib-int", "x"))
+	assert.Error(t, set.Set("lib-int", "-1"))
+	assert.Equal(t, 42, *libInt, "original reverted when not valid")
+	assert.NoError(t, dynInt.ValidateInput("7"))
+	assert.Error(t, dynInt.ValidateInput("-7"))
+	assert.NoError(t, dynInt.SetV(8))
+	assert.Equal(t, 8, *libInt)
+	assert.NoError(t, dynInt.Reset())
+	assert.Equal(t, 5, *libInt)
+	assert.Equal(t, []interface{}{42, 8, 5}, notified)
+	dynDuration, err := Wrap(set, "lib-duration")
+	assert.NoError(t, err)
+	assert.NoError(t, set.Set("lib-duration", "1m"))
+	assert.Equal(t, time.Minute, *libDuration)
+	assert.Equal(t, time.Minute, dynDuration.Get())
+}
+
+func TestWrapBoolAndFunc(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	libBool := set.Bool("lib-bool", false, "a library's bool")
+	var got []string
+	set.Func("lib-func", "a library's func", func(s string) error {
+		if strings.Contains(s, "bad") {
+			return errors.New("bad value")
+		}
+		got = append(got, s)
+		return nil
+	})
+	dynBool, err := Wrap(set, "lib-bool")
+	assert.NoError(t, err)
+	dynFunc, err := Wrap(set, "lib-func")
+	assert.NoError(t, err)
+	assert.Equal(t, "", dynFunc.Get())
+	assert.NoError(t, set.Parse([]string{"-lib-bool", "-lib-func", "a"}))
+	assert.True(t, *libBool, "bool flag without value")
+	assert.Equal(t, true, dynBool.Get())
+	assert.Equal(t, "a", dynFunc.Get())
+	assert.Equal(t, "a", set.Lookup("lib-func").Value.String())
+	dynFunc.WithValidator(func(v interface{}) error {
+		if v.(string) == "" {
+			return errors.New("empty")
+		}
+		return nil
+	})
+	assert.NoError(t, dynFunc.ValidateInput("bad"), "only the validator is checked")
+	assert.Error(t, dynFunc.ValidateInput(" "))
+	assert.Error(t, set.Set("lib-func", " "))
+	assert.Error(t, set.Set("lib-func", "bad"))
+	assert.Equal(t, "a", dynFunc.Get())
+	assert.Equal(t, []string{"a"}, got, "func only called with valid values")
+}
+
+func TestWrapNotValidatable(t *testing.T) {
+	set := flag.NewFlagSet("foobar", flag.ContinueOnError)
+	var addr netip.Addr
+	set.TextVar(&addr, "lib-addr", netip.MustParseAddr("127.0.0.1"), "a library's text var")
+	dynAddr, err := Wrap(set, "lib-addr")
+	assert.NoError(t, err)
+	assert.True(t, errors.Is(dynAddr.ValidateInput("10.0.0.1"), ErrNotValidatable))
+	assert.True(t, errors.Is(ValidateFlag(set.Lookup("lib-addr"), "10.0.0.1"), ErrNotValidatable))
+	assert.Equal(t, "127.0.0.1", addr.String(), "validation doesn't set the original")
+	assert.NoError(t, set.Set("lib-addr", "10.0.0.2"))
+	assert.Equal(t, "10.0.0.2", addr.String())
+	assert.Equal(t, "10.0.0.2", dynAddr.String())
+}
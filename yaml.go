@@ -0,0 +1,42 @@
+// Copyright 2026 Fortio Authors. All Rights Reserved.
+// See LICENSE for licensing terms.
+
+package dflag
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// yamlUnmarshal is set by RegisterYAML.
+var yamlUnmarshal func(data []byte, v interface{}) error
+
+// RegisterYAML sets the function used to decode the YAML values of the DynJSON flags, e.g. gopkg.in/yaml.v3's
+// Unmarshal (which is what importing fortio.org/dflag/httppoll/yaml does). The JSON flags then also accept
+// YAML maps and lists, e.g. pasted in a ConfigMap, which are converted to JSON.
+func RegisterYAML(unmarshal func(data []byte, v interface{}) error) {
+	yamlUnmarshal = unmarshal
+}
+
+// unmarshalValue is unmarshalJSON falling back to YAML, when registered, for inputs that aren't JSON.
+func unmarshalValue(input string, v interface{}) error {
+	err := unmarshalJSON(input, v)
+	var syntaxErr *json.SyntaxError
+	if err == nil || yamlUnmarshal == nil || !errors.As(err, &syntaxErr) {
+		return err // other errors are from valid JSON (e.g. of the wrong type), which YAML would repeat.
+	}
+	var y interface{}
+	if yamlUnmarshal([]byte(input), &y) != nil {
+		return err
+	}
+	switch y.(type) {
+	case map[string]interface{}, []interface{}:
+	default:
+		return err // a scalar, e.g. truncated JSON, the JSON error is the relevant one.
+	}
+	b, yErr := json.Marshal(y) // e.g. fails for non string keys.
+	if yErr != nil {
+		return err
+	}
+	return json.Unmarshal(b, v) // the syntax error happened before v was modified.
+}